@@ -15,5 +15,10 @@ func GetBundleCmd(config *config.Config) *cobra.Command {
 		},
 	}
 	bundleCmd.AddCommand(getGenerateCmd(config))
+	bundleCmd.AddCommand(getListCmd())
+	bundleCmd.AddCommand(getInfoCmd(config))
+	bundleCmd.AddCommand(getRemoveCmd())
+	bundleCmd.AddCommand(getPruneCmd(config))
+	bundleCmd.AddCommand(getMirrorCmd(config))
 	return bundleCmd
 }
@@ -2,7 +2,6 @@ package bundle
 
 import (
 	"github.com/crc-org/crc/v2/pkg/crc/config"
-	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
 	"github.com/crc-org/crc/v2/pkg/crc/machine"
 	"github.com/spf13/cobra"
@@ -14,16 +13,20 @@ func getGenerateCmd(config *config.Config) *cobra.Command {
 		Use:   "generate",
 		Short: "Generate a custom bundle from the running OpenShift cluster",
 		Long:  "Generate a custom bundle from the running OpenShift cluster",
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return runGenerate(config, forceStop)
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			name, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return err
+			}
+			return runGenerate(config, name, forceStop)
 		},
 	}
 	generateCmd.PersistentFlags().BoolVarP(&forceStop, "force-stop", "f", false, "Forcefully stop the instance")
 	return generateCmd
 }
 
-func runGenerate(config *config.Config, forceStop bool) error {
-	client := machine.NewClient(constants.DefaultName, logging.IsDebug(), config)
+func runGenerate(config *config.Config, name string, forceStop bool) error {
+	client := machine.NewClient(name, logging.IsDebug(), config)
 
 	return client.GenerateBundle(forceStop)
 }
@@ -0,0 +1,40 @@
+package bundle
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/spf13/cobra"
+)
+
+func getInfoCmd(config *crcConfig.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "info [NAME]",
+		Short: "Display the metadata of a bundle",
+		Long:  "Display the metadata of an extracted bundle as JSON, defaults to the configured bundle",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return runInfo(os.Stdout, args[0])
+			}
+			bundleName, err := bundle.GetBundleNameFromURI(config.Get(crcConfig.Bundle).AsString())
+			if err != nil {
+				return err
+			}
+			return runInfo(os.Stdout, bundleName)
+		},
+	}
+}
+
+func runInfo(writer io.Writer, bundleName string) error {
+	bundleInfo, err := bundle.Get(bundleName)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bundleInfo)
+}
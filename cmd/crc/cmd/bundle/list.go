@@ -0,0 +1,66 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+func getListCmd() *cobra.Command {
+	var outputFormat string
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the bundles extracted in the cache directory",
+		Long:  "List the bundles extracted in the cache directory with their version, preset, size on disk and the instances using them",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runList(os.Stdout, outputFormat)
+		},
+	}
+	listCmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format. One of: json")
+	return listCmd
+}
+
+func runList(writer io.Writer, outputFormat string) error {
+	bundles, err := machine.ListBundles()
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(bundles)
+	case "":
+		return printBundleList(writer, bundles)
+	default:
+		return fmt.Errorf("invalid format: %s", outputFormat)
+	}
+}
+
+func printBundleList(writer io.Writer, bundles []types.CachedBundle) error {
+	if len(bundles) == 0 {
+		_, err := fmt.Fprintln(writer, "No bundle found in the cache directory")
+		return err
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(w, "NAME\tVERSION\tPRESET\tSIZE\tUSED BY"); err != nil {
+		return err
+	}
+	for _, bundle := range bundles {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", bundle.Name, bundle.Version, bundle.Preset.ForDisplay(),
+			units.HumanSize(float64(bundle.Size)), strings.Join(bundle.UsedBy, ",")); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,41 @@
+package bundle
+
+import (
+	"fmt"
+	"path/filepath"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/spf13/cobra"
+)
+
+func getMirrorCmd(config *crcConfig.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "mirror DIR",
+		Short: "Fill a bundle mirror directory from the cache directory",
+		Long: "Copy the default bundles from the cache directory to DIR, along with their signed sha256sum " +
+			"and the release information. DIR can then be served over http or used as a local directory " +
+			"with 'crc config set " + crcConfig.BundleMirror + " <location>' on hosts without internet access",
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runMirror(config, args[0])
+		},
+	}
+}
+
+func runMirror(config *crcConfig.Config, dir string) error {
+	destDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	source := config.Get(crcConfig.BundleMirror).AsString()
+	if source != "" && filepath.Clean(source) == destDir {
+		return fmt.Errorf("%s is the configured bundle mirror, it cannot be filled from itself", destDir)
+	}
+	mirrored, err := bundle.Mirror(source, destDir)
+	for _, bundlePath := range mirrored {
+		logging.Infof("Mirrored %s", bundlePath)
+	}
+	return err
+}
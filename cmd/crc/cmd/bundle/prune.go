@@ -0,0 +1,74 @@
+package bundle
+
+import (
+	"fmt"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/input"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+func getPruneCmd(config *crcConfig.Config) *cobra.Command {
+	var force bool
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove unused bundles from the cache directory",
+		Long: "Remove the bundles which are not used by any instance from the cache directory. " +
+			"The default bundle for the configured preset is kept",
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runPrune(config, force)
+		},
+	}
+	pruneCmd.Flags().BoolVarP(&force, "force", "f", false, "Remove the bundles without asking for confirmation")
+	return pruneCmd
+}
+
+func runPrune(config *crcConfig.Config, force bool) error {
+	bundles, err := bundle.List()
+	if err != nil {
+		return err
+	}
+	inUse, err := machine.BundlesInUse()
+	if err != nil {
+		return err
+	}
+	defaultBundle := bundle.GetBundleNameWithoutExtension(constants.GetDefaultBundle(crcConfig.GetPreset(config)))
+
+	var unused []bundle.CrcBundleInfo
+	var reclaimable int64
+	for i := range bundles {
+		name := bundles[i].GetBundleName()
+		if _, ok := inUse[name]; ok || name == defaultBundle {
+			continue
+		}
+		size, err := bundles[i].GetDiskUsage()
+		if err != nil {
+			return err
+		}
+		reclaimable += size
+		unused = append(unused, bundles[i])
+	}
+	if len(unused) == 0 {
+		logging.Info("No unused bundle to remove")
+		return nil
+	}
+	for i := range unused {
+		logging.Infof("Unused bundle: %s", unused[i].GetBundleName())
+	}
+	if !input.PromptUserForYesOrNo(fmt.Sprintf("Do you want to remove %d bundle(s) and reclaim %s", len(unused), units.HumanSize(float64(reclaimable))), force) {
+		return nil
+	}
+	for i := range unused {
+		if err := bundle.Remove(unused[i].GetBundleName()); err != nil {
+			return err
+		}
+		logging.Infof("Removed bundle %s", unused[i].GetBundleName())
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package bundle
+
+import (
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/spf13/cobra"
+)
+
+func getRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove NAME...",
+		Short: "Remove bundles from the cache directory",
+		Long:  "Remove extracted bundles and their archives from the cache directory. Bundles used by an existing instance cannot be removed",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runRemove(args)
+		},
+	}
+}
+
+func runRemove(bundleNames []string) error {
+	for _, bundleName := range bundleNames {
+		if err := machine.RemoveBundle(bundleName); err != nil {
+			return err
+		}
+		logging.Infof("Removed bundle %s", bundle.GetBundleNameWithoutExtension(bundleName))
+	}
+	return nil
+}
@@ -25,7 +25,7 @@ var cleanupCmd = &cobra.Command{
 }
 
 func runCleanup() error {
-	err := preflight.CleanUpHost()
+	err := preflight.CleanUpHost(config)
 	return render(&cleanupResult{
 		Success: err == nil,
 		Error:   crcErrors.ToSerializableError(err),
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/telemetry"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +20,9 @@ CONFIG-KEYS: ` + "\n\n" + configurableFields(config),
 			if len(args) < 2 {
 				return errors.New("Please provide a configuration property and its value as in 'crc config set KEY VALUE'")
 			}
+			if err := validateInstanceSetting(cmd, args[0]); err != nil {
+				return err
+			}
 			setMessage, err := config.Set(args[0], args[1])
 			if err != nil {
 				return err
@@ -33,3 +37,13 @@ CONFIG-KEYS: ` + "\n\n" + configurableFields(config),
 		},
 	}
 }
+
+// validateInstanceSetting checks that key can be set for the instance selected
+// with the --name flag of the root command
+func validateInstanceSetting(cmd *cobra.Command, key string) error {
+	name := constants.DefaultName
+	if flag := cmd.Flags().Lookup("name"); flag != nil {
+		name = flag.Value.String()
+	}
+	return config.ValidateInstanceSetting(name, key)
+}
@@ -35,7 +35,7 @@ var consoleCmd = &cobra.Command{
 	Short:   "Open the OpenShift Web Console in the default browser",
 	Long:    `Open the OpenShift Web Console in the default browser or print its URL or credentials`,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		return runConsole(os.Stdout, daemonclient.New(), consolePrintURL, consolePrintCredentials, outputFormat)
+		return runConsole(os.Stdout, daemonclient.NewForInstance(instanceName), consolePrintURL, consolePrintCredentials, outputFormat)
 	},
 }
 
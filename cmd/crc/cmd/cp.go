@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// prefix of the paths in the instance
+const instancePathPrefix = "crc:"
+
+var (
+	cpRecursive  bool
+	cpPrivileged bool
+)
+
+func init() {
+	cpCmd.Flags().BoolVarP(&cpRecursive, "recursive", "r", false, "Copy directories recursively")
+	cpCmd.Flags().BoolVar(&cpPrivileged, "privileged", false, "Read or write the files of the instance as root")
+	rootCmd.AddCommand(cpCmd)
+}
+
+var cpCmd = &cobra.Command{
+	Use:   "cp [flags] SOURCE DESTINATION",
+	Short: "Copy files between the host and the instance",
+	Long: "Copy files between the host and the instance over SFTP. The paths in the instance are prefixed with '" + instancePathPrefix + "', " +
+		"for instance 'crc cp image.tar crc:/var/tmp' or 'crc cp -r crc:/var/log/pods logs'. " +
+		"Relative paths in the instance are relative to the home directory of the core user",
+	Args: cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runCp(args[0], args[1], cpRecursive, cpPrivileged)
+	},
+}
+
+func runCp(src, dest string, recursive, privileged bool) error {
+	src, dest, upload, err := parseCpPaths(src, dest)
+	if err != nil {
+		return err
+	}
+	sshRunner, err := newSSHRunner(newMachine())
+	if err != nil {
+		return err
+	}
+	defer sshRunner.Close()
+
+	if upload {
+		return sshRunner.Upload(src, dest, recursive, privileged)
+	}
+	return sshRunner.Download(src, dest, recursive, privileged)
+}
+
+// parseCpPaths returns the paths without their instance prefix, and whether
+// the copy is from the host to the instance. As with scp, an empty destination
+// in the instance is the home directory.
+func parseCpPaths(src, dest string) (string, string, bool, error) {
+	srcInInstance := strings.HasPrefix(src, instancePathPrefix)
+	destInInstance := strings.HasPrefix(dest, instancePathPrefix)
+	src = strings.TrimPrefix(src, instancePathPrefix)
+	dest = strings.TrimPrefix(dest, instancePathPrefix)
+	switch {
+	case srcInInstance && destInInstance:
+		return "", "", false, errors.New("cannot copy files within the instance, use 'crc exec -- cp' instead")
+	case !srcInInstance && !destInInstance:
+		return "", "", false, fmt.Errorf("one of the paths must be in the instance, with the '%s' prefix", instancePathPrefix)
+	case src == "" || (!destInInstance && dest == ""):
+		return "", "", false, errors.New("missing path")
+	case destInInstance && dest == "":
+		dest = "."
+	}
+	return src, dest, destInInstance, nil
+}
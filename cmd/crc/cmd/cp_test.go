@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCpPaths(t *testing.T) {
+	src, dest, upload, err := parseCpPaths("image.tar", "crc:/var/tmp")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"image.tar", "/var/tmp", true}, []interface{}{src, dest, upload})
+
+	src, dest, upload, err = parseCpPaths("crc:logs", ".")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"logs", ".", false}, []interface{}{src, dest, upload})
+
+	_, dest, _, err = parseCpPaths("image.tar", "crc:")
+	assert.NoError(t, err)
+	assert.Equal(t, ".", dest)
+
+	_, _, _, err = parseCpPaths("crc:a", "crc:b")
+	assert.EqualError(t, err, "cannot copy files within the instance, use 'crc exec -- cp' instead")
+	_, _, _, err = parseCpPaths("a", "b")
+	assert.EqualError(t, err, "one of the paths must be in the instance, with the 'crc:' prefix")
+	_, _, _, err = parseCpPaths("crc:", "b")
+	assert.EqualError(t, err, "missing path")
+}
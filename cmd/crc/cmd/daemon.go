@@ -12,6 +12,9 @@ import (
 	"os/signal"
 	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -21,10 +24,15 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/api"
 	"github.com/crc-org/crc/v2/pkg/crc/api/client"
 	"github.com/crc-org/crc/v2/pkg/crc/api/events"
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	"github.com/crc-org/crc/v2/pkg/crc/imagecache"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/metrics"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/fileserver/fs9p"
 	"github.com/crc-org/machine/libmachine/drivers"
 	"github.com/docker/go-units"
@@ -48,7 +56,6 @@ func init() {
 }
 
 const (
-	hostVirtualIP           = "192.168.127.254"
 	ErrDaemonAlreadyRunning = "daemon has been started in the background"
 )
 
@@ -69,46 +76,53 @@ var daemonCmd = &cobra.Command{
 			return errors.New(ErrDaemonAlreadyRunning)
 		}
 
-		virtualNetworkConfig := createNewVirtualNetworkConfig(config)
-		err := run(&virtualNetworkConfig)
-		return err
+		virtualNetworkConfig, err := createNewVirtualNetworkConfig(config)
+		if err != nil {
+			return err
+		}
+		return run(&virtualNetworkConfig)
 	},
 }
 
-func createNewVirtualNetworkConfig(providedConfig *crcConfig.Config) types.Configuration {
+func createNewVirtualNetworkConfig(providedConfig *crcConfig.Config) (types.Configuration, error) {
+	userNetwork, err := crcConfig.GetUserNetwork(providedConfig)
+	if err != nil {
+		return types.Configuration{}, err
+	}
+	hostVirtualIP := userNetwork.HostVirtualIP.String()
 	virtualNetworkConfig := types.Configuration{
 		Debug:             false, // never log packets
 		CaptureFile:       os.Getenv("CRC_DAEMON_PCAP_FILE"),
 		MTU:               4000, // Large packets slightly improve the performance. Less small packets.
-		Subnet:            "192.168.127.0/24",
-		GatewayIP:         constants.VSockGateway,
-		GatewayMacAddress: "5a:94:ef:e4:0c:dd",
+		Subnet:            userNetwork.Subnet.String(),
+		GatewayIP:         userNetwork.GatewayIP.String(),
+		GatewayMacAddress: userNetwork.GatewayMAC,
 		DHCPStaticLeases: map[string]string{
-			"192.168.127.2": constants.VsockMacAddress,
+			userNetwork.InstanceIP.String(): constants.VsockMacAddress,
 		},
 		DNS: []types.Zone{
 			{
 				Name:      "apps-crc.testing.",
-				DefaultIP: net.ParseIP("192.168.127.2"),
+				DefaultIP: userNetwork.InstanceIP.To16(),
 			},
 			{
 				Name: "crc.testing.",
 				Records: []types.Record{
 					{
 						Name: "host",
-						IP:   net.ParseIP(hostVirtualIP),
+						IP:   userNetwork.HostVirtualIP.To16(),
 					},
 					{
 						Name: "gateway",
-						IP:   net.ParseIP("192.168.127.1"),
+						IP:   userNetwork.GatewayIP.To16(),
 					},
 					{
 						Name: "api",
-						IP:   net.ParseIP("192.168.127.2"),
+						IP:   userNetwork.InstanceIP.To16(),
 					},
 					{
 						Name: "api-int",
-						IP:   net.ParseIP("192.168.127.2"),
+						IP:   userNetwork.InstanceIP.To16(),
 					},
 					{
 						Regexp: regexp.MustCompile("crc-(.*?)-master-0"),
@@ -121,7 +135,7 @@ func createNewVirtualNetworkConfig(providedConfig *crcConfig.Config) types.Confi
 				Records: []types.Record{
 					{
 						Name: "gateway",
-						IP:   net.ParseIP(hostVirtualIP),
+						IP:   userNetwork.HostVirtualIP.To16(),
 					},
 				},
 			},
@@ -130,7 +144,7 @@ func createNewVirtualNetworkConfig(providedConfig *crcConfig.Config) types.Confi
 				Records: []types.Record{
 					{
 						Name: "gateway",
-						IP:   net.ParseIP(hostVirtualIP),
+						IP:   userNetwork.HostVirtualIP.To16(),
 					},
 				},
 			},
@@ -138,6 +152,16 @@ func createNewVirtualNetworkConfig(providedConfig *crcConfig.Config) types.Confi
 		Protocol:          types.HyperKitProtocol,
 		GatewayVirtualIPs: []string{hostVirtualIP},
 	}
+	if appsDomain := providedConfig.Get(crcConfig.AppsDomain).AsString(); appsDomain != "" {
+		// before the builtin zones, a custom apps domain can be a subdomain of crc.testing
+		virtualNetworkConfig.DNS = append([]types.Zone{
+			{
+				Name:      appsDomain + ".",
+				DefaultIP: userNetwork.InstanceIP.To16(),
+			},
+		}, virtualNetworkConfig.DNS...)
+	}
+	virtualNetworkConfig.DNS = addDNSRecords(virtualNetworkConfig.DNS, userNetwork.DNSRecords)
 	if providedConfig.Get(crcConfig.HostNetworkAccess).AsBool() {
 		log.Debugf("Enabling host network access")
 		if virtualNetworkConfig.NAT == nil {
@@ -145,7 +169,59 @@ func createNewVirtualNetworkConfig(providedConfig *crcConfig.Config) types.Confi
 		}
 		virtualNetworkConfig.NAT[hostVirtualIP] = "127.0.0.1"
 	}
-	return virtualNetworkConfig
+	return virtualNetworkConfig, nil
+}
+
+// addDNSRecords adds the user defined records to the DNS zones. A wildcard
+// record is the default answer of the zone of its domain, the other records
+// are added to the zone of their parent domain. The zones which don't exist
+// are created, and the zones are then sorted from the most specific to the
+// least specific one, as the first zone matching a name answers for it.
+// The records conflicting with the built-in zones are ignored.
+func addDNSRecords(zones []types.Zone, records []network.DNSRecord) []types.Zone {
+	builtinZones := len(zones)
+	for _, record := range records {
+		zoneName := record.Zone() + "."
+		i := slices.IndexFunc(zones, func(zone types.Zone) bool {
+			return zone.Name == zoneName
+		})
+		if i >= 0 && i < builtinZones && conflictsWithZone(zones[i], record) {
+			logging.Warnf("Ignoring the DNS record of %s, it conflicts with the built-in records of %s", record.Name, record.Zone())
+			continue
+		}
+		if i < 0 {
+			zones = append(zones, types.Zone{Name: zoneName})
+			i = len(zones) - 1
+		}
+		if record.Wildcard() {
+			zones[i].DefaultIP = record.IP.To16()
+		} else {
+			zones[i].Records = append(zones[i].Records, types.Record{
+				Name: record.Label(),
+				IP:   record.IP.To16(),
+			})
+		}
+	}
+	for _, zone := range zones[builtinZones:] {
+		if zone.DefaultIP == nil {
+			logging.Warnf("The names of %s without a DNS record will not resolve in the instance", strings.TrimSuffix(zone.Name, "."))
+		}
+	}
+	slices.SortStableFunc(zones, func(a, b types.Zone) int {
+		return strings.Count(b.Name, ".") - strings.Count(a.Name, ".")
+	})
+	return zones
+}
+
+// conflictsWithZone returns true when record would change the answer of zone
+// for one of its names
+func conflictsWithZone(zone types.Zone, record network.DNSRecord) bool {
+	if zone.DefaultIP != nil || record.Wildcard() {
+		return true
+	}
+	return slices.ContainsFunc(zone.Records, func(builtin types.Record) bool {
+		return builtin.Name == record.Label() || (builtin.Regexp != nil && builtin.Regexp.MatchString(record.Label()))
+	})
 }
 
 func run(configuration *types.Configuration) error {
@@ -167,9 +243,12 @@ func run(configuration *types.Configuration) error {
 		}
 		mux := http.NewServeMux()
 		mux.Handle("/network/", interceptResponseBodyMiddleware(logRequestMiddleware(http.StripPrefix("/network", vn.Mux()), "network request"), logResponseBodyConditionally))
-		machineClient := newMachine()
+		machineClient := machine.NewSynchronizedMachine(machine.NewClient(constants.DefaultName, logging.IsDebug(), config))
 		mux.Handle("/api/", interceptResponseBodyMiddleware(http.StripPrefix("/api", api.NewMux(config, machineClient, logging.Memory, segmentClient)), logResponseBodyConditionally))
 		mux.Handle("/events", interceptResponseBodyMiddleware(http.StripPrefix("/events", events.NewEventServer(machineClient)), logResponseBodyConditionally))
+		metricsHandler := metrics.NewHandler(machineClient, vn)
+		mux.Handle("/instances/", interceptResponseBodyMiddleware(newInstancesHandler(config, machineClient, metricsHandler), logResponseBodyConditionally))
+		mux.Handle("/metrics", metricsHandler)
 		s := &http.Server{
 			Handler:           handlers.LoggingHandler(os.Stderr, mux),
 			ReadHeaderTimeout: 10 * time.Second,
@@ -195,7 +274,7 @@ func run(configuration *types.Configuration) error {
 		}
 	}()
 
-	networkListener, err := vn.Listen("tcp", net.JoinHostPort(hostVirtualIP, "80"))
+	networkListener, err := vn.Listen("tcp", net.JoinHostPort(configuration.GatewayVirtualIPs[0], "80"))
 	if err != nil {
 		return err
 	}
@@ -211,6 +290,28 @@ func run(configuration *types.Configuration) error {
 		}
 	}()
 
+	// the image cache settings can only be set for the default instance, the
+	// cache is shared by all the instances like the user mode network
+	if config.Get(crcConfig.EnableImageCache).AsBool() {
+		// only reachable from the instance, as host.crc.testing
+		imageCacheListener, err := vn.Listen("tcp", net.JoinHostPort(configuration.GatewayVirtualIPs[0], strconv.Itoa(constants.ImageCachePort)))
+		if err != nil {
+			return err
+		}
+		go func() {
+			pullSecret := cluster.NewNonInteractivePullSecretLoader(config, "")
+			cache := imagecache.New(constants.ImageCacheDir, crcConfig.GetImageCacheRegistries(config),
+				int64(config.Get(crcConfig.ImageCacheMaxSize).AsUInt())*units.GiB, pullSecret.Value)
+			s := &http.Server{
+				Handler:           handlers.LoggingHandler(os.Stderr, cache),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			if err := s.Serve(imageCacheListener); err != nil {
+				errCh <- errors.Wrap(err, "image cache http.Serve failed")
+			}
+		}()
+	}
+
 	go func() {
 		var oldCancel context.CancelFunc
 		for {
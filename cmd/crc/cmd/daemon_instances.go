@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/crc-org/crc/v2/pkg/crc/api"
+	"github.com/crc-org/crc/v2/pkg/crc/api/events"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/metrics"
+	"github.com/crc-org/crc/v2/pkg/crc/validation"
+)
+
+// instancesHandler serves the API, the events and the metrics of named
+// instances under /instances/<name>/api/, /instances/<name>/events and
+// /instances/<name>/metrics
+type instancesHandler struct {
+	lock     sync.Mutex
+	handlers map[string]http.Handler
+}
+
+// newInstancesHandler returns an instancesHandler which serves the default
+// instance with the given configuration, machine client and metrics handler
+func newInstancesHandler(defaultConfig *crcConfig.Config, defaultMachine machine.Client, defaultMetrics http.Handler) *instancesHandler {
+	return &instancesHandler{
+		handlers: map[string]http.Handler{
+			constants.DefaultName: newInstanceMux(defaultConfig, defaultMachine, defaultMetrics),
+		},
+	}
+}
+
+func newInstanceMux(config *crcConfig.Config, machineClient machine.Client, metricsHandler http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", api.NewMux(config, machineClient, logging.Memory, segmentClient)))
+	mux.Handle("/events", http.StripPrefix("/events", events.NewEventServer(machineClient)))
+	mux.Handle("/metrics", metricsHandler)
+	return mux
+}
+
+func (h *instancesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/instances/"), "/")
+	if err := validation.ValidateInstanceName(name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	handler, err := h.handlerFor(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.StripPrefix(fmt.Sprintf("/instances/%s", name), handler).ServeHTTP(w, r)
+}
+
+func (h *instancesHandler) handlerFor(name string) (http.Handler, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if handler, ok := h.handlers[name]; ok {
+		return handler, nil
+	}
+
+	instanceConfig, _, err := newInstanceConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	machineClient := machine.NewSynchronizedMachine(machine.NewClient(name, logging.IsDebug(), instanceConfig))
+	// the virtual network of the daemon is only used by the default instance
+	handler := newInstanceMux(instanceConfig, machineClient, metrics.NewHandler(machineClient, nil))
+	h.handlers[name] = handler
+	return handler, nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/containers/gvisor-tap-vsock/pkg/types"
 	"github.com/crc-org/crc/v2/pkg/crc/api/client"
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -100,11 +101,13 @@ func TestCreateNewVirtualNetworkConfig(t *testing.T) {
 		assert.NoError(t, err)
 	}("CRC_DAEMON_PCAP_FILE", oldPcapFileEnvVal)
 	testCrcConfig := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	crcConfig.RegisterSettings(testCrcConfig)
 
 	// When
-	virtualNetworkConfig := createNewVirtualNetworkConfig(testCrcConfig)
+	virtualNetworkConfig, err := createNewVirtualNetworkConfig(testCrcConfig)
 
 	// Then
+	assert.NoError(t, err)
 	assert.Equal(t, false, virtualNetworkConfig.Debug)
 	assert.Equal(t, "/tmp/pcapfile", virtualNetworkConfig.CaptureFile)
 	assert.Equal(t, 4000, virtualNetworkConfig.MTU)
@@ -145,17 +148,87 @@ func TestCreateNewVirtualNetworkConfig(t *testing.T) {
 func TestCreateNewVirtualNetworkConfig_WhenHostNetworkConfigSet_ThenSetNAT(t *testing.T) {
 	// Given
 	testCrcConfig := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
-	testCrcConfig.AddSetting("host-network-access", false, crcConfig.ValidateBool, crcConfig.SuccessfullyApplied, "test message")
+	crcConfig.RegisterSettings(testCrcConfig)
 	_, err := testCrcConfig.Set(crcConfig.HostNetworkAccess, true)
 	assert.NoError(t, err)
 
 	// When
-	virtualNetworkConfig := createNewVirtualNetworkConfig(testCrcConfig)
+	virtualNetworkConfig, err := createNewVirtualNetworkConfig(testCrcConfig)
 
 	// Then
+	assert.NoError(t, err)
 	assert.Equal(t, "127.0.0.1", virtualNetworkConfig.NAT["192.168.127.254"])
 }
 
+func TestCreateNewVirtualNetworkConfig_WhenUserNetworkConfigSet(t *testing.T) {
+	// Given
+	testCrcConfig := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	crcConfig.RegisterSettings(testCrcConfig)
+	_, err := testCrcConfig.Set(crcConfig.UserNetworkSubnet, "10.77.0.0/16")
+	assert.NoError(t, err)
+	_, err = testCrcConfig.Set(crcConfig.UserNetworkGatewayMAC, "5a:94:ef:00:00:01")
+	assert.NoError(t, err)
+	_, err = testCrcConfig.Set(crcConfig.UserNetworkDNSRecords, "registry.corp.local=host,*.dev.test=10.0.0.5,db.crc.testing=10.77.0.2")
+	assert.NoError(t, err)
+
+	// When
+	virtualNetworkConfig, err := createNewVirtualNetworkConfig(testCrcConfig)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "10.77.0.0/16", virtualNetworkConfig.Subnet)
+	assert.Equal(t, "10.77.0.1", virtualNetworkConfig.GatewayIP)
+	assert.Equal(t, "5a:94:ef:00:00:01", virtualNetworkConfig.GatewayMacAddress)
+	assert.Equal(t, []string{"10.77.255.254"}, virtualNetworkConfig.GatewayVirtualIPs)
+	assert.Equal(t, map[string]string{"10.77.0.2": "5a:94:ef:e4:0c:ee"}, virtualNetworkConfig.DHCPStaticLeases)
+	assert.Equal(t, net.ParseIP("10.77.0.2"), virtualNetworkConfig.DNS[0].DefaultIP)
+
+	assert.Len(t, virtualNetworkConfig.DNS, 6)
+	assert.Equal(t, "db", virtualNetworkConfig.DNS[1].Records[5].Name)
+	assert.Equal(t, "corp.local.", virtualNetworkConfig.DNS[4].Name)
+	assert.Equal(t, []types.Record{{Name: "registry", IP: net.ParseIP("10.77.255.254")}}, virtualNetworkConfig.DNS[4].Records)
+	assert.Equal(t, "dev.test.", virtualNetworkConfig.DNS[5].Name)
+	assert.Empty(t, virtualNetworkConfig.DNS[5].Records)
+	assert.Equal(t, net.ParseIP("10.0.0.5"), virtualNetworkConfig.DNS[5].DefaultIP)
+}
+
+func TestAddDNSRecords(t *testing.T) {
+	builtin := []types.Zone{
+		{
+			Name:      "apps-crc.testing.",
+			DefaultIP: net.ParseIP("192.168.127.2"),
+		},
+		{
+			Name:    "crc.testing.",
+			Records: []types.Record{{Name: "api", IP: net.ParseIP("192.168.127.2")}},
+		},
+	}
+	records, err := network.ParseUserNetwork(network.DefaultUserNetworkSubnet, network.DefaultUserNetworkGatewayMAC,
+		"api.crc.testing=10.0.0.1,*.crc.testing=10.0.0.1,foo.apps-crc.testing=10.0.0.1,registry.corp.local=10.0.0.2,*.dev.corp.local=10.0.0.3")
+	assert.NoError(t, err)
+
+	zones := addDNSRecords(builtin, records.DNSRecords)
+
+	assert.Equal(t, []types.Zone{
+		{
+			Name:      "dev.corp.local.",
+			DefaultIP: net.ParseIP("10.0.0.3"),
+		},
+		{
+			Name:      "apps-crc.testing.",
+			DefaultIP: net.ParseIP("192.168.127.2"),
+		},
+		{
+			Name:    "crc.testing.",
+			Records: []types.Record{{Name: "api", IP: net.ParseIP("192.168.127.2")}},
+		},
+		{
+			Name:    "corp.local.",
+			Records: []types.Record{{Name: "registry", IP: net.ParseIP("10.0.0.2")}},
+		},
+	}, zones)
+}
+
 type fakeHostsFileEditor struct {
 	addCalled    bool
 	removeCalled bool
@@ -231,3 +304,25 @@ func TestGatewayAPIMux_HostsEndpointsRespectModifyHostsFile(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateNewVirtualNetworkConfig_WhenAppsDomainSet(t *testing.T) {
+	// Given
+	testCrcConfig := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	crcConfig.RegisterSettings(testCrcConfig)
+	_, err := testCrcConfig.Set(crcConfig.AppsDomain, "apps.dev.example.com")
+	assert.NoError(t, err)
+	_, err = testCrcConfig.Set(crcConfig.UserNetworkDNSRecords, "db.apps.dev.example.com=10.0.0.5")
+	assert.NoError(t, err)
+
+	// When
+	virtualNetworkConfig, err := createNewVirtualNetworkConfig(testCrcConfig)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Len(t, virtualNetworkConfig.DNS, 5)
+	assert.Equal(t, "apps.dev.example.com.", virtualNetworkConfig.DNS[0].Name)
+	assert.Equal(t, net.ParseIP("192.168.127.2"), virtualNetworkConfig.DNS[0].DefaultIP)
+	// the names of the apps domain all go to the router
+	assert.Empty(t, virtualNetworkConfig.DNS[0].Records)
+	assert.Equal(t, "apps-crc.testing.", virtualNetworkConfig.DNS[1].Name)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addOutputFormatFlag(diagnoseCmd)
+	rootCmd.AddCommand(diagnoseCmd)
+}
+
+var diagnoseCmd = &cobra.Command{
+	Use:   "diagnose [ARCHIVE]",
+	Short: "Collect the information needed to troubleshoot the instance in an archive",
+	Long: "Collect the logs of crc and of the daemon, the configuration without its secrets, the bundle metadata and, " +
+		"when the instance is running, the journal of the cluster services and the status of the cluster in a single archive. " +
+		"The archive is created in the current directory unless its path is given",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		archivePath := machine.DiagnosticsArchiveName(instanceName, time.Now())
+		if len(args) == 1 {
+			archivePath = args[0]
+		}
+		return runDiagnose(os.Stdout, newMachine(), archivePath, outputFormat)
+	},
+}
+
+type diagnoseResult struct {
+	Success  bool                         `json:"success"`
+	Error    *crcErrors.SerializableError `json:"error,omitempty"`
+	Path     string                       `json:"path,omitempty"`
+	Failures []string                     `json:"failures,omitempty"`
+}
+
+func (s *diagnoseResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if _, err := fmt.Fprintf(writer, "Diagnostics archive created at %s\n", s.Path); err != nil {
+		return err
+	}
+	if len(s.Failures) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(writer, "\nSome information could not be collected:"); err != nil {
+		return err
+	}
+	for _, failure := range s.Failures {
+		if _, err := fmt.Fprintf(writer, "  - %s\n", failure); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runDiagnose(writer io.Writer, client machine.Client, archivePath string, outputFormat string) error {
+	archivePath, err := filepath.Abs(archivePath)
+	if err != nil {
+		return err
+	}
+	diagnostics, err := client.Diagnose(context.Background(), archivePath)
+	return render(toDiagnoseResult(diagnostics, err), writer, outputFormat)
+}
+
+func toDiagnoseResult(diagnostics *types.Diagnostics, err error) *diagnoseResult {
+	if err != nil {
+		return &diagnoseResult{
+			Success: false,
+			Error:   crcErrors.ToSerializableError(err),
+		}
+	}
+	return &diagnoseResult{
+		Success:  true,
+		Path:     diagnostics.Path,
+		Failures: diagnostics.Failures,
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosePlainSuccess(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "crc-diagnose.tar.zst")
+	out := new(bytes.Buffer)
+	assert.NoError(t, runDiagnose(out, fakemachine.NewClient(), archivePath, ""))
+	assert.Equal(t, fmt.Sprintf(`Diagnostics archive created at %s
+
+Some information could not be collected:
+  - instance: the instance is stopped, its journal and the cluster status cannot be collected
+`, archivePath), out.String())
+}
+
+func TestDiagnoseJSONSuccess(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "crc-diagnose.tar.zst")
+	out := new(bytes.Buffer)
+	assert.NoError(t, runDiagnose(out, fakemachine.NewClient(), archivePath, jsonFormat))
+	assert.JSONEq(t, fmt.Sprintf(`{
+  "success": true,
+  "path": %q,
+  "failures": ["instance: the instance is stopped, its journal and the cluster status cannot be collected"]
+}`, archivePath), out.String())
+}
+
+func TestDiagnoseRelativePath(t *testing.T) {
+	out := new(bytes.Buffer)
+	require.NoError(t, runDiagnose(out, fakemachine.NewClient(), "crc-diagnose.tar.zst", jsonFormat))
+	absPath, err := filepath.Abs("crc-diagnose.tar.zst")
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), fmt.Sprintf("%q", absPath))
+}
+
+func TestDiagnoseJSONError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runDiagnose(out, fakemachine.NewFailingClient(), "crc-diagnose.tar.zst", jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "diagnostics collection failed"}`, out.String())
+}
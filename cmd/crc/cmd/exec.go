@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var execTTY bool
+
+func init() {
+	execCmd.Flags().SetInterspersed(false)
+	execCmd.Flags().BoolVarP(&execTTY, "tty", "t", false, "Allocate a pseudo-terminal for the command")
+	rootCmd.AddCommand(execCmd)
+}
+
+var execCmd = &cobra.Command{
+	Use:   "exec [flags] -- COMMAND [ARGS...]",
+	Short: "Run a command in the instance",
+	Long: "Run a command in the instance as the core user, using the SSH keys of crc. " +
+		"As with ssh, the command and its arguments are joined and run by the shell of the instance. " +
+		"crc exits with the exit status of the command",
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runSSH(newMachine(), strings.Join(args, " "), execTTY)
+	},
+}
@@ -35,7 +35,7 @@ func runGenerateKubeconfig() error {
 		return fmt.Errorf("the CRC instance is not running, cannot retrieve kubeconfig")
 	}
 
-	data, err := os.ReadFile(constants.KubeconfigFilePath)
+	data, err := os.ReadFile(constants.GetKubeconfigFilePath(instanceName))
 	if err != nil {
 		return fmt.Errorf("error reading kubeconfig: %w", err)
 	}
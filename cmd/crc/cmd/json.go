@@ -4,18 +4,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-const jsonFormat = "json"
+const (
+	jsonFormat      = "json"
+	jsonLinesFormat = "json-lines"
+)
 
 var (
 	outputFormat string
 )
 
-func addOutputFormatFlag(cmd *cobra.Command) {
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format. One of: json")
+func addOutputFormatFlag(cmd *cobra.Command, extraFormats ...string) {
+	formats := append([]string{jsonFormat}, extraFormats...)
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", fmt.Sprintf("Output format. One of: %s", strings.Join(formats, ", ")))
 }
 
 type prettyPrintable interface {
@@ -28,6 +33,8 @@ func render(obj prettyPrintable, writer io.Writer, outputFormat string) error {
 		encoder := json.NewEncoder(writer)
 		encoder.SetIndent("", "  ")
 		return encoder.Encode(obj)
+	case jsonLinesFormat:
+		return json.NewEncoder(writer).Encode(obj)
 	case "":
 		return obj.prettyPrintTo(writer)
 	default:
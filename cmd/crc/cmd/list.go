@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addOutputFormatFlag(listCmd)
+	rootCmd.AddCommand(listCmd)
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the CRC instances",
+	Long:  "List the CRC instances and their state",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runList(os.Stdout, machine.List, instanceName, outputFormat)
+	},
+}
+
+type instance struct {
+	Name          string        `json:"name"`
+	State         string        `json:"state"`
+	Preset        preset.Preset `json:"preset,omitempty"`
+	BundleVersion string        `json:"bundleVersion,omitempty"`
+	Selected      bool          `json:"selected"`
+}
+
+type listResult struct {
+	Success   bool                         `json:"success"`
+	Error     *crcErrors.SerializableError `json:"error,omitempty"`
+	Instances []instance                   `json:"instances"`
+}
+
+func runList(writer io.Writer, lister func() ([]types.InstanceInfo, error), selected string, outputFormat string) error {
+	infos, err := lister()
+	result := &listResult{
+		Success:   err == nil,
+		Error:     crcErrors.ToSerializableError(err),
+		Instances: []instance{},
+	}
+	for _, info := range infos {
+		result.Instances = append(result.Instances, instance{
+			Name:          info.Name,
+			State:         string(info.State),
+			Preset:        info.Preset,
+			BundleVersion: info.BundleVersion,
+			Selected:      info.Name == selected,
+		})
+	}
+	return render(result, writer, outputFormat)
+}
+
+func (l *listResult) prettyPrintTo(writer io.Writer) error {
+	if l.Error != nil {
+		return l.Error
+	}
+	if len(l.Instances) == 0 {
+		_, err := fmt.Fprintln(writer, "No CRC instance found, create one with 'crc start'")
+		return err
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(w, "NAME\tSTATE\tPRESET\tVERSION"); err != nil {
+		return err
+	}
+	for _, i := range l.Instances {
+		name := i.Name
+		if i.Selected {
+			name += " *"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, i.State, i.Preset.ForDisplay(), i.BundleVersion); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
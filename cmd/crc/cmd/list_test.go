@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeInstances() ([]types.InstanceInfo, error) {
+	return []types.InstanceInfo{
+		{
+			Name:          "crc",
+			State:         state.Running,
+			Preset:        preset.OpenShift,
+			BundleVersion: "4.5.1",
+		},
+		{
+			Name:          "micro",
+			State:         state.Stopped,
+			Preset:        preset.Microshift,
+			BundleVersion: "4.5.2",
+		},
+	}, nil
+}
+
+func TestPlainList(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runList(out, fakeInstances, "micro", ""))
+	assert.Equal(t, `NAME      STATE     PRESET       VERSION
+crc       Running   OpenShift    4.5.1
+micro *   Stopped   MicroShift   4.5.2
+`, out.String())
+}
+
+func TestPlainListEmpty(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runList(out, func() ([]types.InstanceInfo, error) {
+		return nil, nil
+	}, "crc", ""))
+	assert.Equal(t, "No CRC instance found, create one with 'crc start'\n", out.String())
+}
+
+func TestJSONList(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runList(out, fakeInstances, "crc", jsonFormat))
+	assert.JSONEq(t, `{
+  "success": true,
+  "instances": [
+    {"name": "crc", "state": "Running", "preset": "openshift", "bundleVersion": "4.5.1", "selected": true},
+    {"name": "micro", "state": "Stopped", "preset": "microshift", "bundleVersion": "4.5.2", "selected": false}
+  ]
+}`, out.String())
+}
+
+func TestJSONListError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runList(out, func() ([]types.InstanceInfo, error) {
+		return nil, errors.New("list failed")
+	}, "crc", jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "list failed", "instances": []}`, out.String())
+}
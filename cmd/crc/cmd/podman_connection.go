@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+	"github.com/spf13/cobra"
+)
+
+var (
+	podmanConnectionRoot    bool
+	podmanConnectionDefault bool
+	podmanConnectionRemove  bool
+)
+
+var podmanConnectionCmd = &cobra.Command{
+	Use:   "podman-connection",
+	Short: "Add the podman socket of the instance to the podman connections",
+	Long: `Add the podman socket of the instance to the connections of the 'podman' executable of the host,
+so that 'podman --connection <instance name>' uses it. The connection is stored in the podman configuration of the user.`,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runPodmanConnection()
+	},
+}
+
+func runPodmanConnection() error {
+	podman, err := exec.LookPath("podman")
+	if err != nil {
+		return fmt.Errorf("podman is not installed on the host, see https://podman.io/docs/installation")
+	}
+	if podmanConnectionRemove {
+		if _, stderr, err := crcos.RunWithDefaultLocale(podman, "system", "connection", "remove", instanceName); err != nil {
+			return fmt.Errorf("failed to remove the %s podman connection: %s: %w", instanceName, stderr, err)
+		}
+		logging.Infof("Podman connection %s removed", instanceName)
+		return nil
+	}
+
+	client := newMachine()
+	if err := checkIfMachineMissing(client); err != nil {
+		return err
+	}
+	connectionDetails, err := client.ConnectionDetails()
+	if err != nil {
+		return err
+	}
+
+	socket := crcConfig.GetPodmanSocket(config)
+	if podmanConnectionRoot {
+		socket = constants.RootfulPodmanSocket
+	}
+	// 'podman system connection add' fails when the connection already exists
+	_, _, _ = crcos.RunWithDefaultLocale(podman, "system", "connection", "remove", instanceName)
+	if _, stderr, err := crcos.RunWithDefaultLocale(podman, podmanConnectionAddArgs(instanceName, connectionDetails, socket, podmanConnectionDefault)...); err != nil {
+		return fmt.Errorf("failed to add the %s podman connection: %s: %w", instanceName, stderr, err)
+	}
+	logging.Infof("Podman connection %s added, use it with 'podman --connection %s'", instanceName, instanceName)
+	return nil
+}
+
+func podmanConnectionAddArgs(name string, connectionDetails *types.ConnectionDetails, socket string, makeDefault bool) []string {
+	args := []string{"system", "connection", "add", "--identity", connectionDetails.SSHKeys[0]}
+	if makeDefault {
+		args = append(args, "--default")
+	}
+	return append(args, name, fmt.Sprintf("ssh://%s@%s:%d%s",
+		connectionDetails.SSHUsername,
+		connectionDetails.IP,
+		connectionDetails.SSHPort,
+		socket))
+}
+
+func init() {
+	podmanConnectionCmd.Flags().BoolVar(&podmanConnectionRoot, "root", false, "Use root podman in the virtual machine instead of the 'podman-socket-mode' setting")
+	podmanConnectionCmd.Flags().BoolVar(&podmanConnectionDefault, "default", false, "Make the connection the default one of podman")
+	podmanConnectionCmd.Flags().BoolVar(&podmanConnectionRemove, "remove", false, "Remove the connection from the podman configuration")
+	rootCmd.AddCommand(podmanConnectionCmd)
+}
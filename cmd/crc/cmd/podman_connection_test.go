@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodmanConnectionAddArgs(t *testing.T) {
+	connectionDetails := &types.ConnectionDetails{
+		IP:          "127.0.0.1",
+		SSHPort:     2222,
+		SSHUsername: "core",
+		SSHKeys:     []string{"/home/user/.crc/machines/crc/id_ed25519"},
+	}
+	assert.Equal(t, []string{"system", "connection", "add", "--identity", "/home/user/.crc/machines/crc/id_ed25519",
+		"crc", "ssh://core@127.0.0.1:2222/run/user/1000/podman/podman.sock"},
+		podmanConnectionAddArgs("crc", connectionDetails, constants.RootlessPodmanSocket, false))
+	assert.Equal(t, []string{"system", "connection", "add", "--identity", "/home/user/.crc/machines/crc/id_ed25519", "--default",
+		"crc", "ssh://core@127.0.0.1:2222/run/podman/podman.sock"},
+		podmanConnectionAddArgs("crc", connectionDetails, constants.RootfulPodmanSocket, true))
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/os/shell"
 	"github.com/spf13/cobra"
@@ -52,6 +54,7 @@ func runPodmanEnv() error {
 	fmt.Fprintln(os.Stderr, "In future releases, the podman-env command will be removed from crc.")
 	fmt.Fprintln(os.Stderr, "Please create an issue at https://github.com/crc-org/crc/issues with details")
 	fmt.Fprintln(os.Stderr, "if you think this command is still useful for your workflow.")
+	fmt.Fprintln(os.Stderr, "'crc podman-connection' adds the instance to the podman connections instead.")
 	fmt.Fprintln(os.Stderr)
 
 	fmt.Println(shell.GetEnvString(userShell, "CONTAINER_SSHKEY", connectionDetails.SSHKeys[0]))
@@ -63,10 +66,11 @@ func runPodmanEnv() error {
 			socket)))
 	// Todo: This need to fixed by using named pipe for windows
 	// https://docs.docker.com/desktop/faqs/#how-do-i-connect-to-the-remote-docker-engine-api
+	hostSocket := crcConfig.GetHostPodmanSocket(config, instanceName)
 	if runtime.GOOS != "windows" {
-		fmt.Println(shell.GetEnvString(userShell, "DOCKER_HOST", fmt.Sprintf("unix://%s", constants.GetHostDockerSocketPath())))
+		fmt.Println(shell.GetEnvString(userShell, "DOCKER_HOST", fmt.Sprintf("unix://%s", hostSocket)))
 	} else {
-		fmt.Println(shell.GetEnvString(userShell, "DOCKER_HOST", "npipe:////./pipe/crc-podman"))
+		fmt.Println(shell.GetEnvString(userShell, "DOCKER_HOST", fmt.Sprintf("npipe://%s", strings.ReplaceAll(hostSocket, `\`, "/"))))
 	}
 	if root {
 		fmt.Println(shell.GenerateUsageHintWithComment(userShell, "crc podman-env --root"))
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addOutputFormatFlag(portForwardAddCmd)
+	addOutputFormatFlag(portForwardListCmd)
+	addOutputFormatFlag(portForwardRemoveCmd)
+	portForwardCmd.AddCommand(portForwardAddCmd, portForwardListCmd, portForwardRemoveCmd)
+	rootCmd.AddCommand(portForwardCmd)
+}
+
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward SUBCOMMAND [flags]",
+	Short: "Manage extra port forwards to the instance",
+	Long:  "Forward additional host ports to the instance, only supported with the user network mode",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var portForwardAddCmd = &cobra.Command{
+	Use:   "add HOST_PORT:GUEST_PORT[/PROTOCOL]",
+	Short: "Forward a host port to the instance",
+	Long: "Forward a host port to a port of the instance. PROTOCOL is 'tcp' (default) or 'udp'. " +
+		"The forward is stored in the configuration and set up again on each start",
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		portForward, err := network.ParsePortForward(args[0])
+		if err != nil {
+			return err
+		}
+		return runPortForwardAdd(os.Stdout, newMachine(), portForward, outputFormat)
+	},
+}
+
+var portForwardListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the extra port forwards",
+	Long:  "List the extra port forwards stored in the configuration",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runPortForwardList(os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+var portForwardRemoveCmd = &cobra.Command{
+	Use:   "remove HOST_PORT[/PROTOCOL]",
+	Short: "Remove a port forward",
+	Long:  "Remove the forward of a host port from the configuration and stop forwarding it if the instance is running",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		portForward, err := network.ParseHostPort(args[0])
+		if err != nil {
+			return err
+		}
+		return runPortForwardRemove(os.Stdout, newMachine(), portForward, outputFormat)
+	},
+}
+
+type portForward struct {
+	Protocol  string `json:"protocol"`
+	HostPort  uint   `json:"hostPort"`
+	GuestPort uint   `json:"guestPort"`
+}
+
+type portForwardResult struct {
+	Success bool                         `json:"success"`
+	Error   *crcErrors.SerializableError `json:"error,omitempty"`
+	message string
+}
+
+func (s *portForwardResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	_, err := fmt.Fprintln(writer, s.message)
+	return err
+}
+
+type portForwardListResult struct {
+	Success      bool                         `json:"success"`
+	Error        *crcErrors.SerializableError `json:"error,omitempty"`
+	PortForwards []portForward                `json:"portForwards"`
+}
+
+func (s *portForwardListResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if len(s.PortForwards) == 0 {
+		_, err := fmt.Fprintln(writer, "No port forwards")
+		return err
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(w, "HOST PORT\tGUEST PORT\tPROTOCOL"); err != nil {
+		return err
+	}
+	for _, pf := range s.PortForwards {
+		if _, err := fmt.Fprintf(w, "%d\t%d\t%s\n", pf.HostPort, pf.GuestPort, pf.Protocol); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func runPortForwardAdd(writer io.Writer, client machine.Client, pf network.PortForward, outputFormat string) error {
+	err := client.AddPortForward(pf)
+	return render(&portForwardResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		message: fmt.Sprintf("Forwarding host port %d/%s to port %d of the instance", pf.HostPort, pf.Protocol, pf.GuestPort),
+	}, writer, outputFormat)
+}
+
+func runPortForwardList(writer io.Writer, client machine.Client, outputFormat string) error {
+	portForwards, err := client.ListPortForwards()
+	result := &portForwardListResult{
+		Success:      err == nil,
+		Error:        crcErrors.ToSerializableError(err),
+		PortForwards: []portForward{},
+	}
+	for _, pf := range portForwards {
+		result.PortForwards = append(result.PortForwards, portForward{
+			Protocol:  pf.Protocol,
+			HostPort:  pf.HostPort,
+			GuestPort: pf.GuestPort,
+		})
+	}
+	return render(result, writer, outputFormat)
+}
+
+func runPortForwardRemove(writer io.Writer, client machine.Client, pf network.PortForward, outputFormat string) error {
+	err := client.RemovePortForward(pf)
+	return render(&portForwardResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		message: fmt.Sprintf("Removed the forward of host port %d/%s", pf.HostPort, pf.Protocol),
+	}, writer, outputFormat)
+}
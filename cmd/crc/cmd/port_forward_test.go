@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortForwardAddPlainSuccess(t *testing.T) {
+	out := new(bytes.Buffer)
+	pf := network.PortForward{Protocol: network.TCPProtocol, HostPort: 5432, GuestPort: 30432}
+	assert.NoError(t, runPortForwardAdd(out, fakemachine.NewClient(), pf, ""))
+	assert.Equal(t, "Forwarding host port 5432/tcp to port 30432 of the instance\n", out.String())
+}
+
+func TestPortForwardAddJSONError(t *testing.T) {
+	out := new(bytes.Buffer)
+	pf := network.PortForward{Protocol: network.TCPProtocol, HostPort: 5432, GuestPort: 30432}
+	assert.NoError(t, runPortForwardAdd(out, fakemachine.NewFailingClient(), pf, jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "port forward creation failed"}`, out.String())
+}
+
+func TestPortForwardListPlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPortForwardList(out, fakemachine.NewClient(), ""))
+	assert.Equal(t, `HOST PORT   GUEST PORT   PROTOCOL
+8080        30080        tcp
+`, out.String())
+}
+
+func TestPortForwardListJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPortForwardList(out, fakemachine.NewClient(), jsonFormat))
+	assert.JSONEq(t, `{
+  "success": true,
+  "portForwards": [
+    {"protocol": "tcp", "hostPort": 8080, "guestPort": 30080}
+  ]
+}`, out.String())
+}
+
+func TestPortForwardRemovePlainError(t *testing.T) {
+	out := new(bytes.Buffer)
+	pf := network.PortForward{Protocol: network.UDPProtocol, HostPort: 8080}
+	assert.EqualError(t, runPortForwardRemove(out, fakemachine.NewClient(), pf, ""), "host port 8080/udp is not forwarded")
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addOutputFormatFlag(registryEnableCmd)
+	addOutputFormatFlag(registryDisableCmd)
+	registryCmd.AddCommand(registryEnableCmd, registryDisableCmd)
+	rootCmd.AddCommand(registryCmd)
+}
+
+var registryCmd = &cobra.Command{
+	Use:   "registry SUBCOMMAND [flags]",
+	Short: "Manage the access to the registry of the cluster from the host",
+	Long:  "Push the images built on the host to the registry of the cluster",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var registryEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Make the registry of the cluster reachable from the host",
+	Long: "Make the registry of the cluster reachable from the host. On OpenShift the internal registry is exposed, " +
+		"on MicroShift a registry is deployed. The access from the host is allowed in the registries.conf drop-ins of the user. " +
+		"The registry is set up again on each start",
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runRegistryEnable(os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+var registryDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Remove the access to the registry of the cluster from the host",
+	Long:  "Remove the access to the registry of the cluster from the host, the pushed images are kept",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runRegistryDisable(os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+type registryResult struct {
+	Success        bool                         `json:"success"`
+	Error          *crcErrors.SerializableError `json:"error,omitempty"`
+	Address        string                       `json:"address,omitempty"`
+	ClusterAddress string                       `json:"clusterAddress,omitempty"`
+	LoginCommand   string                       `json:"loginCommand,omitempty"`
+	message        string
+}
+
+func (s *registryResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if s.Address == "" {
+		_, err := fmt.Fprintln(writer, s.message)
+		return err
+	}
+	namespace := ""
+	if s.LoginCommand != "" {
+		// the internal registry of OpenShift stores the images by project
+		namespace = "PROJECT/"
+		if _, err := fmt.Fprintf(writer, "Log in to the registry with:\n  %s\n\n", s.LoginCommand); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(writer, "Push images with:\n  podman push IMAGE %s/%sIMAGE\n\nUse them in the cluster as:\n  %s/%sIMAGE\n",
+		s.Address, namespace, s.ClusterAddress, namespace)
+	return err
+}
+
+func runRegistryEnable(writer io.Writer, client machine.Client, outputFormat string) error {
+	registry, err := client.EnableRegistry()
+	return render(toRegistryResult(registry, err), writer, outputFormat)
+}
+
+func toRegistryResult(registry *types.Registry, err error) *registryResult {
+	result := &registryResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		message: "The registry will be available after the next start",
+	}
+	if registry == nil {
+		return result
+	}
+	result.Address = registry.Address
+	result.ClusterAddress = registry.ClusterAddress
+	if registry.RequiresLogin {
+		result.LoginCommand = fmt.Sprintf("podman login -u unused -p $(oc whoami -t) %s", registry.Address)
+	}
+	return result
+}
+
+func runRegistryDisable(writer io.Writer, client machine.Client, outputFormat string) error {
+	err := client.DisableRegistry()
+	return render(&registryResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		message: "The registry is no longer reachable from the host",
+	}, writer, outputFormat)
+}
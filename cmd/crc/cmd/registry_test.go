@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryEnablePlainSuccess(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runRegistryEnable(out, fakemachine.NewClient(), ""))
+	assert.Equal(t, `Log in to the registry with:
+  podman login -u unused -p $(oc whoami -t) 127.0.0.1:5000
+
+Push images with:
+  podman push IMAGE 127.0.0.1:5000/PROJECT/IMAGE
+
+Use them in the cluster as:
+  image-registry.openshift-image-registry.svc:5000/PROJECT/IMAGE
+`, out.String())
+}
+
+func TestRegistryEnableJSONSuccess(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runRegistryEnable(out, fakemachine.NewClient(), jsonFormat))
+	assert.JSONEq(t, `{
+  "success": true,
+  "address": "127.0.0.1:5000",
+  "clusterAddress": "image-registry.openshift-image-registry.svc:5000",
+  "loginCommand": "podman login -u unused -p $(oc whoami -t) 127.0.0.1:5000"
+}`, out.String())
+}
+
+func TestRegistryEnablePlainMicroShift(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, render(toRegistryResult(&types.Registry{
+		Address:        "127.0.0.1:5000",
+		ClusterAddress: "localhost:5000",
+	}, nil), out, ""))
+	assert.Equal(t, `Push images with:
+  podman push IMAGE 127.0.0.1:5000/IMAGE
+
+Use them in the cluster as:
+  localhost:5000/IMAGE
+`, out.String())
+}
+
+func TestRegistryEnableStopped(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, render(toRegistryResult(nil, nil), out, ""))
+	assert.Equal(t, "The registry will be available after the next start\n", out.String())
+}
+
+func TestRegistryDisableJSONError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runRegistryDisable(out, fakemachine.NewFailingClient(), jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "registry disabling failed"}`, out.String())
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/preflight"
 	"github.com/crc-org/crc/v2/pkg/crc/segment"
 	"github.com/crc-org/crc/v2/pkg/crc/telemetry"
+	"github.com/crc-org/crc/v2/pkg/crc/validation"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/util/exec"
 )
@@ -44,6 +46,7 @@ var rootCmd = &cobra.Command{
 
 var (
 	globalForce   bool
+	instanceName  string
 	viper         *crcConfig.ViperStorage
 	config        *crcConfig.Config
 	segmentClient *segment.Client
@@ -73,6 +76,7 @@ func init() {
 	rootCmd.AddCommand(cmdBundle.GetBundleCmd(config))
 
 	logging.AddLogLevelFlag(rootCmd.PersistentFlags())
+	rootCmd.PersistentFlags().StringVar(&instanceName, "name", constants.DefaultName, "Name of the CRC instance to act on")
 }
 
 func runPrerun(cmd *cobra.Command) error {
@@ -91,10 +95,30 @@ func runPrerun(cmd *cobra.Command) error {
 		if err := constants.EnsureAdminHelperLogFileExists(); err != nil {
 			logging.Warn("Error creating admin helper log file: ", err.Error())
 		}
+		if err := useInstance(instanceName); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// useInstance switches the configuration to the one of the instance called name
+func useInstance(name string) error {
+	if err := validation.ValidateInstanceName(name); err != nil {
+		return err
+	}
+	if name == constants.DefaultName {
+		return nil
+	}
+	configPath := constants.GetInstanceConfigPath(name)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0750); err != nil {
+		return err
+	}
+	viper.SetConfigFile(configPath)
+	crcConfig.UpdateDefaults(config)
+	return setProxyDefaults()
+}
+
 func runPostrun() {
 	segmentClient.Close()
 	logging.CloseLogging()
@@ -117,6 +141,11 @@ func Execute() {
 	}
 
 	if err := rootCmd.ExecuteContext(telemetry.NewContext(context.Background())); err != nil {
+		var remoteErr remoteExitError
+		if errors.As(err, &remoteErr) {
+			runPostrun()
+			os.Exit(remoteErr.ExitStatus())
+		}
 		logging.Error(err.Error())
 		runPostrun()
 		var e exec.CodeExitError
@@ -158,7 +187,15 @@ func setProxyDefaults() error {
 }
 
 func newConfig() (*crcConfig.Config, *crcConfig.ViperStorage, error) {
-	viper, err := crcConfig.NewViperStorage(constants.ConfigPath, constants.CrcEnvPrefix)
+	return newInstanceConfig(constants.DefaultName)
+}
+
+func newInstanceConfig(name string) (*crcConfig.Config, *crcConfig.ViperStorage, error) {
+	configPath := constants.GetInstanceConfigPath(name)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0750); err != nil {
+		return nil, nil, err
+	}
+	viper, err := crcConfig.NewViperStorage(configPath, constants.CrcEnvPrefix)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -169,7 +206,7 @@ func newConfig() (*crcConfig.Config, *crcConfig.ViperStorage, error) {
 }
 
 func newMachine() machine.Client {
-	return machine.NewSynchronizedMachine(machine.NewClient(constants.DefaultName, logging.IsDebug(), config))
+	return machine.NewSynchronizedMachine(machine.NewClient(instanceName, logging.IsDebug(), config))
 }
 
 func addForceFlag(cmd *cobra.Command) {
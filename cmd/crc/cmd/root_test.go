@@ -24,6 +24,11 @@ func TestCrcManPageGenerator_WhenInvoked_GeneratesManPagesForAllCrcSubCommands(t
 	}
 	assert.ElementsMatch(t, []string{
 		"crc-bundle-generate.1",
+		"crc-bundle-info.1",
+		"crc-bundle-list.1",
+		"crc-bundle-mirror.1",
+		"crc-bundle-prune.1",
+		"crc-bundle-remove.1",
 		"crc-bundle.1",
 		"crc-cleanup.1",
 		"crc-config-get.1",
@@ -32,12 +37,30 @@ func TestCrcManPageGenerator_WhenInvoked_GeneratesManPagesForAllCrcSubCommands(t
 		"crc-config-view.1",
 		"crc-config.1",
 		"crc-console.1",
+		"crc-cp.1",
 		"crc-delete.1",
+		"crc-diagnose.1",
+		"crc-exec.1",
 		"crc-generate-kubeconfig.1",
 		"crc-ip.1",
+		"crc-list.1",
 		"crc-oc-env.1",
+		"crc-podman-connection.1",
 		"crc-podman-env.1",
+		"crc-port-forward-add.1",
+		"crc-port-forward-list.1",
+		"crc-port-forward-remove.1",
+		"crc-port-forward.1",
+		"crc-registry-disable.1",
+		"crc-registry-enable.1",
+		"crc-registry.1",
 		"crc-setup.1",
+		"crc-snapshot-create.1",
+		"crc-snapshot-delete.1",
+		"crc-snapshot-list.1",
+		"crc-snapshot-restore.1",
+		"crc-snapshot.1",
+		"crc-ssh.1",
 		"crc-start.1",
 		"crc-status.1",
 		"crc-stop.1",
@@ -66,7 +66,7 @@ func runSetup(_ []string) error {
 
 	// set global variable to force terminal output
 	crcTerminal.ForceShowOutput = forceShowProgressbars
-	err := preflight.SetupHost(config, checkOnly)
+	err := preflight.SetupHost(config, instanceName, checkOnly)
 	if err != nil && checkOnly {
 		err = exec.CodeExitError{
 			Err:  err,
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addOutputFormatFlag(snapshotCreateCmd)
+	addOutputFormatFlag(snapshotListCmd)
+	addOutputFormatFlag(snapshotRestoreCmd)
+	addOutputFormatFlag(snapshotDeleteCmd)
+	snapshotCmd.AddCommand(snapshotCreateCmd, snapshotListCmd, snapshotRestoreCmd, snapshotDeleteCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot SUBCOMMAND [flags]",
+	Short: "Manage snapshots of the instance disk",
+	Long:  "Save and restore the state of the instance disk",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Create a snapshot of the instance disk",
+	Long:  "Create a snapshot of the instance disk, the instance must be stopped",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runSnapshotCreate(os.Stdout, newMachine(), args[0], outputFormat)
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the snapshots of the instance disk",
+	Long:  "List the snapshots of the instance disk",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runSnapshotList(os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore NAME",
+	Short: "Restore a snapshot of the instance disk and start the instance",
+	Long: "Restore a snapshot of the instance disk, the instance must be stopped. " +
+		"The instance is then started to bring certificates, kubeconfig and DNS configuration in sync with the restored disk",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newMachine()
+		if err := checkIfMachineMissing(client); err != nil {
+			return err
+		}
+		if err := client.RestoreSnapshot(args[0]); err != nil {
+			return render(&snapshotResult{
+				Success: false,
+				Error:   crcErrors.ToSerializableError(err),
+			}, os.Stdout, outputFormat)
+		}
+		logging.Infof("Snapshot %s restored, starting the instance", args[0])
+		return renderStartResult(runStart(cmd.Context()))
+	},
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete a snapshot of the instance disk",
+	Long:  "Delete a snapshot of the instance disk, the instance must be stopped",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runSnapshotDelete(os.Stdout, newMachine(), args[0], outputFormat)
+	},
+}
+
+type snapshot struct {
+	Name             string    `json:"name"`
+	BundleName       string    `json:"bundleName"`
+	OpenshiftVersion string    `json:"openshiftVersion"`
+	CreationTime     time.Time `json:"creationTime"`
+}
+
+type snapshotResult struct {
+	Success  bool                         `json:"success"`
+	Error    *crcErrors.SerializableError `json:"error,omitempty"`
+	Snapshot *snapshot                    `json:"snapshot,omitempty"`
+	message  string
+}
+
+func (s *snapshotResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	_, err := fmt.Fprintln(writer, s.message)
+	return err
+}
+
+type snapshotListResult struct {
+	Success   bool                         `json:"success"`
+	Error     *crcErrors.SerializableError `json:"error,omitempty"`
+	Snapshots []snapshot                   `json:"snapshots"`
+}
+
+func (s *snapshotListResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if len(s.Snapshots) == 0 {
+		_, err := fmt.Fprintln(writer, "No snapshot found, create one with 'crc snapshot create'")
+		return err
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(w, "NAME\tVERSION\tCREATED"); err != nil {
+		return err
+	}
+	for _, snapshot := range s.Snapshots {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", snapshot.Name, snapshot.OpenshiftVersion, snapshot.CreationTime.Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func runSnapshotCreate(writer io.Writer, client machine.Client, name string, outputFormat string) error {
+	result := &snapshotResult{
+		message: fmt.Sprintf("Created snapshot %s", name),
+	}
+	created, err := client.CreateSnapshot(name)
+	if err == nil {
+		result.Snapshot = &snapshot{
+			Name:             created.Name,
+			BundleName:       created.BundleName,
+			OpenshiftVersion: created.OpenshiftVersion,
+			CreationTime:     created.CreationTime,
+		}
+	}
+	result.Success = err == nil
+	result.Error = crcErrors.ToSerializableError(err)
+	return render(result, writer, outputFormat)
+}
+
+func runSnapshotList(writer io.Writer, client machine.Client, outputFormat string) error {
+	snapshots, err := client.ListSnapshots()
+	result := &snapshotListResult{
+		Success:   err == nil,
+		Error:     crcErrors.ToSerializableError(err),
+		Snapshots: []snapshot{},
+	}
+	for _, s := range snapshots {
+		result.Snapshots = append(result.Snapshots, snapshot{
+			Name:             s.Name,
+			BundleName:       s.BundleName,
+			OpenshiftVersion: s.OpenshiftVersion,
+			CreationTime:     s.CreationTime,
+		})
+	}
+	return render(result, writer, outputFormat)
+}
+
+func runSnapshotDelete(writer io.Writer, client machine.Client, name string, outputFormat string) error {
+	err := client.DeleteSnapshot(name)
+	return render(&snapshotResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		message: fmt.Sprintf("Deleted snapshot %s", name),
+	}, writer, outputFormat)
+}
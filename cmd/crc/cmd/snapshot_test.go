@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotCreatePlainSuccess(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotCreate(out, fakemachine.NewClient(), "before-upgrade", ""))
+	assert.Equal(t, "Created snapshot before-upgrade\n", out.String())
+}
+
+func TestSnapshotCreateJSONError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotCreate(out, fakemachine.NewFailingClient(), "before-upgrade", jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "snapshot creation failed"}`, out.String())
+}
+
+func TestSnapshotListPlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotList(out, fakemachine.NewClient(), ""))
+	assert.Equal(t, `NAME   VERSION   CREATED
+base   4.5.1     2024-01-02T03:04:05Z
+`, out.String())
+}
+
+func TestSnapshotListJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotList(out, fakemachine.NewClient(), jsonFormat))
+	assert.JSONEq(t, `{
+  "success": true,
+  "snapshots": [
+    {"name": "base", "bundleName": "crc_libvirt_4.5.1_amd64.crcbundle", "openshiftVersion": "4.5.1", "creationTime": "2024-01-02T03:04:05Z"}
+  ]
+}`, out.String())
+}
+
+func TestSnapshotDeletePlainError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.EqualError(t, runSnapshotDelete(out, fakemachine.NewFailingClient(), "base", ""), "snapshot deletion failed")
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/exec"
+)
+
+func init() {
+	rootCmd.AddCommand(sshCmd)
+}
+
+var sshCmd = &cobra.Command{
+	Use:   "ssh",
+	Short: "Open an interactive shell in the instance",
+	Long:  "Open an interactive shell in the instance as the core user, using the SSH keys of crc",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runSSH(newMachine(), "", true)
+	},
+}
+
+// runSSH runs command in the instance with the standard streams of crc, or
+// a shell when command is empty. The exit status of command becomes the one of crc.
+func runSSH(client machine.Client, command string, tty bool) error {
+	sshRunner, err := newSSHRunner(client)
+	if err != nil {
+		return err
+	}
+	defer sshRunner.Close()
+
+	return toCodeExitError(sshRunner.RunInteractive(command, tty, os.Stdin, os.Stdout, os.Stderr))
+}
+
+// remoteExitError is the non-zero exit status of the command run by 'crc ssh',
+// crc exits with the same code without reporting an error
+type remoteExitError struct {
+	exec.CodeExitError
+}
+
+// toCodeExitError converts the exit status of a remote command to an error
+// setting the exit code of crc
+func toCodeExitError(err error) error {
+	var exitErr *crcssh.ExitError
+	if errors.As(err, &exitErr) {
+		return remoteExitError{exec.CodeExitError{
+			Err:  fmt.Errorf("command exited with status %d", exitErr.ExitStatus()),
+			Code: exitErr.ExitStatus(),
+		}}
+	}
+	return err
+}
+
+func newSSHRunner(client machine.Client) (*crcssh.Runner, error) {
+	if err := checkIfMachineMissing(client); err != nil {
+		return nil, err
+	}
+	running, err := client.IsRunning()
+	if err != nil {
+		return nil, err
+	}
+	if !running {
+		return nil, errors.New("the CRC instance is not running")
+	}
+	connectionDetails, err := client.ConnectionDetails()
+	if err != nil {
+		return nil, err
+	}
+	return crcssh.CreateRunner(connectionDetails.IP, connectionDetails.SSHPort, connectionDetails.KnownHostsFile, connectionDetails.BundleKnownHostsFile, connectionDetails.SSHKeys...)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -19,11 +20,13 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
 	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/preflight"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/spec"
 	"github.com/crc-org/crc/v2/pkg/crc/validation"
 	crcversion "github.com/crc-org/crc/v2/pkg/crc/version"
 	crcos "github.com/crc-org/crc/v2/pkg/os"
@@ -34,7 +37,7 @@ import (
 
 func init() {
 	rootCmd.AddCommand(startCmd)
-	addOutputFormatFlag(startCmd)
+	addOutputFormatFlag(startCmd, jsonLinesFormat)
 
 	flagSet := pflag.NewFlagSet("start", pflag.ExitOnError)
 	flagSet.StringP(crcConfig.Bundle, "b", constants.GetDefaultBundlePath(crcConfig.GetPreset(config)), crcConfig.BundleHelpMsg(config))
@@ -44,10 +47,14 @@ func init() {
 	flagSet.UintP(crcConfig.DiskSize, "d", constants.DefaultDiskSize, "Total size in GiB of the disk used by the instance")
 	flagSet.StringP(crcConfig.NameServer, "n", "", "IPv4 address of nameserver to use for the instance")
 	flagSet.Bool(crcConfig.DisableUpdateCheck, false, "Don't check for update")
+	flagSet.String(crcConfig.AppsDomain, "", "Domain of the OpenShift console, oauth server and new routes, such as 'apps.dev.example.com'")
 
 	startCmd.Flags().AddFlagSet(flagSet)
+	startCmd.Flags().StringVarP(&startSpecFile, "file", "f", "", "crc.yaml file holding the settings of the instance and the actions applied to the cluster once it's started")
 }
 
+var startSpecFile string
+
 var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start the instance",
@@ -56,16 +63,35 @@ var startCmd = &cobra.Command{
 		if err := viper.BindFlagSet(cmd.Flags()); err != nil {
 			return err
 		}
-		return renderStartResult(runStart(cmd.Context()))
+		ctx := cmd.Context()
+		if outputFormat == jsonLinesFormat {
+			ctx = machine.WithStartProgressReporter(ctx, newJSONLinesStartProgressReporter(os.Stdout))
+		}
+		return renderStartResult(runStart(ctx))
 	},
 }
 
+// newJSONLinesStartProgressReporter writes each start progress update to
+// writer as a single line of JSON, before the final start result
+func newJSONLinesStartProgressReporter(writer io.Writer) machine.StartProgressReporter {
+	encoder := json.NewEncoder(writer)
+	return machine.StartProgressReporterFunc(func(progress types.StartProgress) {
+		if err := encoder.Encode(progress); err != nil {
+			logging.Debugf("Cannot write start progress: %v", err)
+		}
+	})
+}
+
 func runStart(ctx context.Context) (*types.StartResult, error) {
+	postStart, err := applySpecFile(startSpecFile)
+	if err != nil {
+		return nil, err
+	}
 	if err := validateStartFlags(); err != nil {
 		return nil, err
 	}
 
-	if err := checkIfNewVersionAvailable(config.Get(crcConfig.DisableUpdateCheck).AsBool()); err != nil {
+	if err := checkIfNewVersionAvailable(config.Get(crcConfig.DisableUpdateCheck).AsBool(), config.Get(crcConfig.BundleMirror).AsString()); err != nil {
 		logging.Debugf("Unable to find out if a new version is available: %v", err)
 	}
 
@@ -88,6 +114,19 @@ func runStart(ctx context.Context) (*types.StartResult, error) {
 		PersistentVolumeSize: config.Get(crcConfig.PersistentVolumeSize).AsInt(),
 
 		EnableBundleQuayFallback: config.Get(crcConfig.EnableBundleQuayFallback).AsBool(),
+		BundleMirror:             config.Get(crcConfig.BundleMirror).AsString(),
+
+		AppsDomain: config.Get(crcConfig.AppsDomain).AsString(),
+
+		IngressTLSCertFile: config.Get(crcConfig.IngressTLSCertFile).AsString(),
+		IngressTLSKeyFile:  config.Get(crcConfig.IngressTLSKeyFile).AsString(),
+		APITLSCertFile:     config.Get(crcConfig.APITLSCertFile).AsString(),
+		APITLSKeyFile:      config.Get(crcConfig.APITLSKeyFile).AsString(),
+		TLSCAFile:          config.Get(crcConfig.TLSCAFile).AsString(),
+
+		TrustClusterCA: config.Get(crcConfig.TrustClusterCA).AsBool(),
+
+		PostStart: postStart,
 	}
 
 	client := newMachine()
@@ -98,7 +137,7 @@ func runStart(ctx context.Context) (*types.StartResult, error) {
 			return nil, err
 		}
 
-		if err := preflight.StartPreflightChecks(config); err != nil {
+		if err := preflight.StartPreflightChecks(config, instanceName); err != nil {
 			return nil, crcos.CodeExitError{
 				Err:  err,
 				Code: preflightFailedExitCode,
@@ -109,14 +148,47 @@ func runStart(ctx context.Context) (*types.StartResult, error) {
 	return client.Start(ctx, startConfig)
 }
 
+// applySpecFile stores the settings of the spec file at path in the
+// configuration, and returns the actions to apply once the cluster is started
+func applySpecFile(path string) (types.PostStartActions, error) {
+	if path == "" {
+		return types.PostStartActions{}, nil
+	}
+	clusterSpec, err := spec.Load(path)
+	if err != nil {
+		return types.PostStartActions{}, err
+	}
+	changes, err := clusterSpec.Apply(config)
+	for _, change := range changes {
+		logging.Infof("%s: %v -> %v", change.Name, change.Current, change.Wanted)
+		logging.Info(change.Message)
+	}
+	if err != nil {
+		return types.PostStartActions{}, fmt.Errorf("cannot apply the settings of %s: %w", path, err)
+	}
+	return clusterSpec.PostStartActions(), nil
+}
+
 func renderStartResult(result *types.StartResult, err error) error {
 	return render(&startResult{
-		Success:       err == nil,
-		Error:         crcErrors.ToSerializableError(err),
-		ClusterConfig: toClusterConfig(result),
+		Success:        err == nil,
+		Error:          crcErrors.ToSerializableError(err),
+		ClusterConfig:  toClusterConfig(result),
+		PostStartHooks: toHookResults(result),
 	}, os.Stdout, outputFormat)
 }
 
+func toHookResults(result *types.StartResult) []hookResult {
+	if result == nil {
+		return nil
+	}
+	var hooks []hookResult
+	for _, hook := range result.PostStartHooks {
+		hooks = append(hooks, hookResult(hook))
+	}
+	return hooks
+}
+
 func toClusterConfig(result *types.StartResult) *clusterConfig {
 	if result == nil {
 		return nil
@@ -151,10 +223,17 @@ type credentials struct {
 	Password string `json:"password"` // nolint:gosec
 }
 
+type hookResult struct {
+	Name    string `json:"name"`
+	LogFile string `json:"logFile"`
+	Error   string `json:"error,omitempty"`
+}
+
 type startResult struct {
-	Success       bool                         `json:"success"`
-	Error         *crcErrors.SerializableError `json:"error,omitempty"`
-	ClusterConfig *clusterConfig               `json:"clusterConfig,omitempty"`
+	Success        bool                         `json:"success"`
+	Error          *crcErrors.SerializableError `json:"error,omitempty"`
+	ClusterConfig  *clusterConfig               `json:"clusterConfig,omitempty"`
+	PostStartHooks []hookResult                 `json:"postStartHooks,omitempty"`
 }
 
 func (s *startResult) prettyPrintTo(writer io.Writer) error {
@@ -169,7 +248,22 @@ func (s *startResult) prettyPrintTo(writer io.Writer) error {
 		return errors.New("either Error or ClusterConfig is needed")
 	}
 
-	return writeTemplatedMessage(writer, s)
+	if err := writeTemplatedMessage(writer, s); err != nil {
+		return err
+	}
+	return writeFailedHooks(writer, s.PostStartHooks)
+}
+
+func writeFailedHooks(writer io.Writer, hooks []hookResult) error {
+	for _, hook := range hooks {
+		if hook.Error == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(writer, "\nPost-start hook %s failed: %s\nSee %s for its output.\n", hook.Name, hook.Error, hook.LogFile); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func validateStartFlags() error {
@@ -190,14 +284,19 @@ func validateStartFlags() error {
 			return err
 		}
 	}
+	if config.Get(crcConfig.AppsDomain).AsString() != "" {
+		if err := validation.ValidateAppsDomain(config.Get(crcConfig.AppsDomain).AsString()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func checkIfNewVersionAvailable(noUpdateCheck bool) error {
+func checkIfNewVersionAvailable(noUpdateCheck bool, bundleMirror string) error {
 	if noUpdateCheck {
 		return nil
 	}
-	isNewVersionAvailable, newVersion, link, err := newVersionAvailable()
+	isNewVersionAvailable, newVersion, link, err := newVersionAvailable(bundleMirror)
 	if err != nil {
 		return err
 	}
@@ -209,8 +308,8 @@ func checkIfNewVersionAvailable(noUpdateCheck bool) error {
 	return nil
 }
 
-func newVersionAvailable() (bool, string, string, error) {
-	release, err := bundle.FetchLatestReleaseInfo()
+func newVersionAvailable(bundleMirror string) (bool, string, string, error) {
+	release, err := bundle.FetchLatestReleaseInfo(bundleMirror)
 	if err != nil {
 		return false, "", "", err
 	}
@@ -314,7 +413,7 @@ func writeMicroShiftTemplatedMessage(writer io.Writer) error {
 	return parsed.Execute(writer, &templateVariables{
 		EvalCommandLine:   shell.GenerateUsageHint(userShell, "crc oc-env"),
 		CommandLinePrefix: commandLinePrefix(userShell),
-		KubeConfigPath:    constants.KubeconfigFilePath,
+		KubeConfigPath:    constants.GetKubeconfigFilePath(instanceName),
 	})
 }
 
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"runtime"
 	"testing"
+	"time"
 
 	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/crc-org/crc/v2/pkg/os/shell"
 	"github.com/stretchr/testify/assert"
@@ -170,3 +172,28 @@ func expectedTemplate(shell string) string {
 	}
 	return unixTemplate
 }
+
+func TestRenderStartProgressJSONLines(t *testing.T) {
+	out := new(bytes.Buffer)
+	reporter := newJSONLinesStartProgressReporter(out)
+	reporter.StartProgress(types.StartProgress{Phase: types.StartPhaseStartVM, State: types.PhaseRunning})
+	reporter.StartProgress(types.StartProgress{Phase: types.StartPhaseStartVM, State: types.PhaseFailed, Elapsed: time.Second, Error: "failed"})
+	assert.NoError(t, render(&startResult{
+		Success: false,
+		Error:   crcErrors.ToSerializableError(errors.New("failed")),
+	}, out, jsonLinesFormat))
+
+	assert.Equal(t, `{"phase":"start-vm","state":"Running","elapsedMs":0}
+{"phase":"start-vm","state":"Failed","error":"failed","elapsedMs":1000}
+{"success":false,"error":"failed"}
+`, out.String())
+}
+
+func TestWriteFailedHooks(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, writeFailedHooks(out, []hookResult{
+		{Name: "10-imagestreams.yaml", LogFile: "/home/user/.crc/machines/crc/hooks/10-imagestreams.yaml.log"},
+		{Name: "20-samples.sh", LogFile: "/home/user/.crc/machines/crc/hooks/20-samples.sh.log", Error: "exit status 1"},
+	}))
+	assert.Equal(t, "\nPost-start hook 20-samples.sh failed: exit status 1\nSee /home/user/.crc/machines/crc/hooks/20-samples.sh.log for its output.\n", out.String())
+}
@@ -38,7 +38,7 @@ var statusCmd = &cobra.Command{
 	Short: "Display status of the OpenShift cluster",
 	Long:  "Show details about the OpenShift cluster",
 	RunE: func(_ *cobra.Command, _ []string) error {
-		return runStatus(os.Stdout, daemonclient.New(), constants.MachineCacheDir, outputFormat, watch)
+		return runStatus(os.Stdout, daemonclient.NewForInstance(instanceName), constants.MachineCacheDir, outputFormat, watch)
 	},
 }
 
@@ -27,7 +27,7 @@ var versionCmd = &cobra.Command{
 }
 
 func runPrintVersion(writer io.Writer, version *version, outputFormat string) error {
-	if err := checkIfNewVersionAvailable(config.Get(crcConfig.DisableUpdateCheck).AsBool()); err != nil {
+	if err := checkIfNewVersionAvailable(config.Get(crcConfig.DisableUpdateCheck).AsBool(), config.Get(crcConfig.BundleMirror).AsString()); err != nil {
 		logging.Debugf("Unable to find out if a new version is available: %v", err)
 	}
 	return render(version, writer, outputFormat)
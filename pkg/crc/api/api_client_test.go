@@ -14,6 +14,7 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/crc-org/crc/v2/pkg/crc/version"
 	"github.com/stretchr/testify/assert"
@@ -120,6 +121,55 @@ func TestDelete(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestBundles(t *testing.T) {
+	client := newTestClient()
+	defer client.Close()
+
+	bundles, err := client.ListBundles()
+	assert.NoError(t, err)
+	assert.Equal(t, apiClient.BundlesResult{Bundles: []types.CachedBundle{fakemachine.DummyBundle}}, bundles)
+
+	bundleInfo, err := client.GetBundle(fakemachine.DummyBundle.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, &fakemachine.DummyBundleInfo, bundleInfo)
+
+	_, err = client.GetBundle("crc_libvirt_4.6.0_amd64")
+	assert.EqualError(t, err, "bundle crc_libvirt_4.6.0_amd64 not found: file does not exist")
+
+	download, err := client.DownloadBundle("/tmp/crc_libvirt_4.5.2_amd64.crcbundle")
+	assert.NoError(t, err)
+	assert.Equal(t, apiClient.DownloadBundleResult{Bundle: "crc_libvirt_4.5.2_amd64"}, download)
+
+	assert.NoError(t, client.RemoveBundle(fakemachine.DummyBundle.Name))
+}
+
+func TestPortForwards(t *testing.T) {
+	client := newTestClient()
+	defer client.Close()
+
+	portForwards, err := client.ListPortForwards()
+	assert.NoError(t, err)
+	assert.Equal(t, apiClient.PortForwardsResult{PortForwards: []network.PortForward{fakemachine.DummyPortForward}}, portForwards)
+
+	assert.NoError(t, client.AddPortForward(network.PortForward{Protocol: network.UDPProtocol, HostPort: 5353, GuestPort: 53}))
+	assert.NoError(t, client.RemovePortForward(fakemachine.DummyPortForward))
+	assert.Error(t, client.RemovePortForward(network.PortForward{Protocol: network.UDPProtocol, HostPort: 8080}))
+}
+
+func TestDiagnose(t *testing.T) {
+	client := newTestClient()
+	defer client.Close()
+
+	archivePath := filepath.Join(t.TempDir(), "crc-diagnose.tar.zst")
+	diagnostics, err := client.Diagnose(archivePath)
+	assert.NoError(t, err)
+	assert.Equal(t, archivePath, diagnostics.Path)
+	assert.Len(t, diagnostics.Failures, 1)
+
+	_, err = client.Diagnose("crc-diagnose.tar.zst")
+	assert.Error(t, err)
+}
+
 func TestConfigGet(t *testing.T) {
 	client := newTestClient()
 	defer client.Close()
@@ -25,6 +25,9 @@ func newServerWithRoutes(handler *Handler) *server {
 	server.POST("/stop", handler.Stop)
 	server.GET("/stop", handler.Stop)
 
+	server.GET("/operations/{id}", handler.GetOperation)
+	server.DELETE("/operations/{id}", handler.CancelOperation)
+
 	server.POST("/poweroff", handler.PowerOff)
 
 	server.GET("/status", handler.Status)
@@ -45,6 +48,17 @@ func newServerWithRoutes(handler *Handler) *server {
 	server.GET("/telemetry", handler.UploadTelemetry)
 	server.POST("/telemetry", handler.UploadTelemetry)
 
+	server.GET("/bundles", handler.ListBundles)
+	server.GET("/bundles/{name}", handler.GetBundle)
+	server.DELETE("/bundles/{name}", handler.RemoveBundle)
+	server.POST("/bundles/download", handler.DownloadBundle)
+
+	server.GET("/port-forwards", handler.ListPortForwards)
+	server.POST("/port-forwards", handler.AddPortForward)
+	server.DELETE("/port-forwards/{protocol}/{port}", handler.RemovePortForward)
+
+	server.POST("/diagnose", handler.Diagnose)
+
 	server.GET("/pull-secret", getPullSecret(handler.Config))
 	server.POST("/pull-secret", setPullSecret())
 
@@ -21,8 +21,9 @@ import (
 
 type mockServer struct {
 	*server
-	client *fakemachine.Client
-	config crcConfig.Storage
+	client     *fakemachine.Client
+	config     crcConfig.Storage
+	operations *operations
 }
 
 func createDummyPullSecret(t *testing.T) string {
@@ -48,11 +49,15 @@ func newMockServer(pullSecretPath string) *mockServer {
 	_, _ = config.Set(crcConfig.PullSecretFile, pullSecretPath)
 
 	handler := NewHandler(config, fakeMachine, &mockLogger{}, &mockTelemetry{})
+	handler.operations.newID = func() string {
+		return "e1d3c7a2"
+	}
 
 	return &mockServer{
-		server: newServerWithRoutes(handler),
-		client: fakeMachine,
-		config: config,
+		server:     newServerWithRoutes(handler),
+		client:     fakeMachine,
+		config:     config,
+		operations: handler.operations,
 	}
 }
 
@@ -167,7 +172,7 @@ var testCases = []testCase{
 	// start
 	{
 		request:  post("start"),
-		response: jSon(`{"Status":"","ClusterConfig":{"ClusterType":"openshift","ClusterCACert":"MIIDODCCAiCgAwIBAgIIRVfCKNUa1wIwDQYJ","KubeConfig":"/tmp/kubeconfig","KubeAdminPass":"foobar","DeveloperPass":"foobar","ClusterAPI":"https://foo.testing:6443","WebConsoleURL":"https://console.foo.testing:6443","ProxyConfig":null},"KubeletStarted":true}`),
+		response: httpError(202).withBody(`{"ID":"e1d3c7a2","Type":"start","State":"Running"}`),
 	},
 	{
 		request:  get("start"),
@@ -178,7 +183,7 @@ var testCases = []testCase{
 	{
 		request:     post("start"),
 		failRequest: true,
-		response:    httpError(500).withBody("Failed to start\n"),
+		response:    httpError(202).withBody(`{"ID":"e1d3c7a2","Type":"start","State":"Running"}`),
 	},
 	{
 		request:     get("start"),
@@ -195,6 +200,10 @@ var testCases = []testCase{
 		request:  get("stop"),
 		response: empty(),
 	},
+	{
+		request:  post("stop?async=true"),
+		response: httpError(202).withBody(`{"ID":"e1d3c7a2","Type":"stop","State":"Running"}`),
+	},
 
 	// stop with failure
 	{
@@ -203,6 +212,12 @@ var testCases = []testCase{
 		// error message comes from fakemachine
 		response: httpError(500).withBody("stop failed\n"),
 	},
+	{
+		request:     post("stop?async=true"),
+		failRequest: true,
+		// the failure is reported by the operation
+		response: httpError(202).withBody(`{"ID":"e1d3c7a2","Type":"stop","State":"Running"}`),
+	},
 	{
 		request:     get("stop"),
 		failRequest: true,
@@ -210,6 +225,16 @@ var testCases = []testCase{
 		response: httpError(500).withBody("stop failed\n"),
 	},
 
+	// operations
+	{
+		request:  get("operations/unknown"),
+		response: httpError(404).withBody("operation unknown not found"),
+	},
+	{
+		request:  deleteRequest("operations/unknown"),
+		response: httpError(404).withBody("operation unknown not found"),
+	},
+
 	// poweroff
 	{
 		request:  post("poweroff"),
@@ -357,6 +382,98 @@ var testCases = []testCase{
 		response:    httpError(500).withBody("empty pull secret\n"),
 	},
 
+	// bundles
+	{
+		request:  get("bundles"),
+		response: jSon(`{"Bundles":[{"Name":"crc_libvirt_4.5.1_amd64","Version":"4.5.1","Preset":"openshift","Size":1000000000,"UsedBy":["crc"]}]}`),
+	},
+	{
+		request:  get("bundles/crc_libvirt_4.5.1_amd64"),
+		response: jSon(`{"version":"1.0","type":"snc","name":"crc_libvirt_4.5.1_amd64","buildInfo":{"buildTime":"","openshiftInstallerVersion":"","sncVersion":""},"clusterInfo":{"openshiftVersion":null,"clusterName":"","baseDomain":"","appsDomain":"","sshPrivateKeyFile":"","kubeConfig":""},"nodes":null,"storage":{"diskImages":null,"fileList":null},"driverInfo":{"name":""}}`),
+	},
+	{
+		request:  get("bundles/crc_libvirt_4.6.0_amd64"),
+		response: httpError(404).withBody("bundle crc_libvirt_4.6.0_amd64 not found: file does not exist"),
+	},
+	{
+		request:  deleteRequest("bundles/crc_libvirt_4.5.1_amd64"),
+		response: empty(),
+	},
+	{
+		request:  post("bundles/download").withBody(`{"bundleURI":"/tmp/crc_libvirt_4.5.1_amd64.crcbundle"}`),
+		response: httpError(202).withBody(`{"Bundle":"crc_libvirt_4.5.1_amd64"}`),
+	},
+
+	// bundles with failure
+	{
+		request:     get("bundles"),
+		failRequest: true,
+		response:    httpError(500).withBody("bundle listing failed\n"),
+	},
+	{
+		request:     get("bundles/crc_libvirt_4.5.1_amd64"),
+		failRequest: true,
+		response:    httpError(500).withBody("bundle metadata is invalid\n"),
+	},
+	{
+		request:     deleteRequest("bundles/crc_libvirt_4.5.1_amd64"),
+		failRequest: true,
+		response:    httpError(500).withBody("bundle removal failed\n"),
+	},
+
+	// port forwards
+	{
+		request:  get("port-forwards"),
+		response: jSon(`{"PortForwards":[{"Protocol":"tcp","HostPort":8080,"GuestPort":30080}]}`),
+	},
+	{
+		request:  post("port-forwards").withBody(`{"HostPort":5432,"GuestPort":5432}`),
+		response: httpError(201).withBody(`{"Protocol":"tcp","HostPort":5432,"GuestPort":5432}`),
+	},
+	{
+		request:  post("port-forwards").withBody(`{"Protocol":"sctp","HostPort":5432,"GuestPort":5432}`),
+		response: httpError(400).withBody("invalid protocol 'sctp', should be either tcp or udp"),
+	},
+	{
+		request:  deleteRequest("port-forwards/tcp/8080"),
+		response: empty(),
+	},
+	{
+		request:  deleteRequest("port-forwards/tcp/9090"),
+		response: httpError(500).withBody("host port 9090/tcp is not forwarded\n"),
+	},
+
+	// port forwards with failure
+	{
+		request:     get("port-forwards"),
+		failRequest: true,
+		response:    httpError(500).withBody("port forward listing failed\n"),
+	},
+	{
+		request:     post("port-forwards").withBody(`{"HostPort":5432,"GuestPort":5432}`),
+		failRequest: true,
+		response:    httpError(500).withBody("port forward creation failed\n"),
+	},
+
+	// diagnose
+	{
+		request:  post("diagnose").withBody(`{"path":"/tmp/crc-diagnose.tar.zst"}`),
+		response: jSon(`{"Path":"/tmp/crc-diagnose.tar.zst","Failures":["instance: the instance is stopped, its journal and the cluster status cannot be collected"]}`),
+	},
+	{
+		request:  post("diagnose").withBody(`{"path":"crc-diagnose.tar.zst"}`),
+		response: httpError(400).withBody("the path of the archive must be absolute: crc-diagnose.tar.zst"),
+	},
+	{
+		request:  post("diagnose").withBody(`{"path":"/tmp"}`),
+		response: httpError(409).withBody("the archive cannot replace an existing file: /tmp"),
+	},
+	{
+		request:     post("diagnose").withBody(`{"path":"/tmp/crc-diagnose.tar.zst"}`),
+		failRequest: true,
+		response:    httpError(500).withBody("diagnostics collection failed\n"),
+	},
+
 	// not found
 	{
 		request:  get("notfound"),
@@ -383,6 +500,12 @@ var invalidHTTPMethods = []testCase{
 		response: httpError(404).withBody("Not Found\n"),
 	},
 
+	// operations
+	{
+		request:  post("operations/e1d3c7a2"),
+		response: httpError(404).withBody("Not Found\n"),
+	},
+
 	// poweroff
 	{
 		request:  get("poweroff"),
@@ -439,6 +562,36 @@ var invalidHTTPMethods = []testCase{
 		response: httpError(404).withBody("Not Found\n"),
 	},
 
+	// bundles
+	{
+		request:  post("bundles"),
+		response: httpError(404).withBody("Not Found\n"),
+	},
+	{
+		request:  post("bundles/crc_libvirt_4.5.1_amd64"),
+		response: httpError(404).withBody("Not Found\n"),
+	},
+	{
+		request:  get("bundles/download"),
+		response: httpError(404).withBody("Not Found\n"),
+	},
+	{
+		request:  get("bundles/"),
+		response: httpError(404).withBody("Not Found\n"),
+	},
+
+	// port forwards
+	{
+		request:  get("port-forwards/tcp/8080"),
+		response: httpError(404).withBody("Not Found\n"),
+	},
+
+	// diagnose
+	{
+		request:  get("diagnose"),
+		response: httpError(404).withBody("Not Found\n"),
+	},
+
 	// telemetry
 	{
 		request:  deleteRequest("telemetry"),
@@ -459,6 +612,8 @@ func testOne(t *testing.T, testCase *testCase, server *mockServer) {
 		testCase.preTestFunc(t, server)
 	}
 	resp := sendRequest(server.Handler(), &testCase.request)
+	// let the operations started by the request complete before changing the fake machine
+	server.operations.wait()
 
 	require.Equal(t, testCase.response.statusCode, resp.StatusCode, testCase.request)
 	require.Equal(t, testCase.response.protoMajor, resp.ProtoMajor, testCase.request)
@@ -489,7 +644,11 @@ func TestRoutes(t *testing.T) {
 	var routes = map[string][]string{}
 	for _, testCase := range testCases {
 		// Add leading '/', remove trailing '?....'
-		pattern := fmt.Sprintf("/%s", strings.SplitN(testCase.request.resource, "?", 2)[0])
+		path := fmt.Sprintf("/%s", strings.SplitN(testCase.request.resource, "?", 2)[0])
+		pattern, _, _, ok := newMockServer("").match(path)
+		if !ok {
+			pattern = path
+		}
 		if _, ok := routes[pattern]; !ok {
 			routes[pattern] = []string{}
 		}
@@ -3,11 +3,16 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 )
 
 type Client interface {
@@ -23,8 +28,25 @@ type Client interface {
 	Telemetry(action string) error
 	IsPullSecretDefined() (bool, error)
 	SetPullSecret(data string) error
+	ListBundles() (BundlesResult, error)
+	GetBundle(name string) (*bundle.CrcBundleInfo, error)
+	DownloadBundle(bundleURI string) (DownloadBundleResult, error)
+	RemoveBundle(name string) error
+	GetOperation(id string) (Operation, error)
+	CancelOperation(id string) (Operation, error)
+	ListPortForwards() (PortForwardsResult, error)
+	AddPortForward(portForward network.PortForward) error
+	RemovePortForward(portForward network.PortForward) error
+	Diagnose(path string) (DiagnoseResult, error)
 }
 
+const (
+	operationPollInterval = 500 * time.Millisecond
+	// operationTimeout bounds how long the client waits for an operation
+	// to complete, a start can take several minutes
+	operationTimeout = 30 * time.Minute
+)
+
 type HTTPError struct {
 	URL        string
 	Method     string
@@ -74,8 +96,10 @@ func (c *client) Status() (ClusterStatusResult, error) {
 	return sr, nil
 }
 
+// Start starts the instance and waits until the start operation is complete
 func (c *client) Start(config StartConfig) (StartResult, error) {
 	var sr = StartResult{}
+	var op = Operation{}
 	var data = new(bytes.Buffer)
 
 	if config != (StartConfig{}) {
@@ -87,13 +111,69 @@ func (c *client) Start(config StartConfig) (StartResult, error) {
 	if err != nil {
 		return sr, err
 	}
-	err = json.Unmarshal(body, &sr)
+	err = json.Unmarshal(body, &op)
+	if err != nil {
+		return sr, err
+	}
+	op, err = c.waitForOperation(op.ID, operationTimeout)
 	if err != nil {
 		return sr, err
 	}
+	if op.StartResult != nil {
+		sr = *op.StartResult
+	}
 	return sr, nil
 }
 
+func (c *client) waitForOperation(id string, timeout time.Duration) (Operation, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		op, err := c.GetOperation(id)
+		if err != nil {
+			return op, err
+		}
+		switch op.State {
+		case OperationRunning:
+			if time.Now().After(deadline) {
+				return op, fmt.Errorf("timed out after %s waiting for %s operation %s", timeout, op.Type, id)
+			}
+			time.Sleep(operationPollInterval)
+		case OperationSucceeded:
+			return op, nil
+		case OperationCancelled:
+			return op, fmt.Errorf("%s operation was cancelled", op.Type)
+		default:
+			return op, errors.New(op.Error)
+		}
+	}
+}
+
+func (c *client) GetOperation(id string) (Operation, error) {
+	var op = Operation{}
+	body, err := c.sendGetRequest(fmt.Sprintf("/operations/%s", url.PathEscape(id)))
+	if err != nil {
+		return op, err
+	}
+	err = json.Unmarshal(body, &op)
+	if err != nil {
+		return op, err
+	}
+	return op, nil
+}
+
+func (c *client) CancelOperation(id string) (Operation, error) {
+	var op = Operation{}
+	body, err := c.sendDeleteRequest(fmt.Sprintf("/operations/%s", url.PathEscape(id)), nil)
+	if err != nil {
+		return op, err
+	}
+	err = json.Unmarshal(body, &op)
+	if err != nil {
+		return op, err
+	}
+	return op, nil
+}
+
 func (c *client) Stop() error {
 	_, err := c.sendGetRequest("/stop")
 	return err
@@ -210,6 +290,105 @@ func (c *client) SetPullSecret(data string) error {
 	return nil
 }
 
+func (c *client) ListBundles() (BundlesResult, error) {
+	var br = BundlesResult{}
+	body, err := c.sendGetRequest("/bundles")
+	if err != nil {
+		return br, err
+	}
+	err = json.Unmarshal(body, &br)
+	if err != nil {
+		return br, err
+	}
+	return br, nil
+}
+
+func (c *client) GetBundle(name string) (*bundle.CrcBundleInfo, error) {
+	var bundleInfo = bundle.CrcBundleInfo{}
+	body, err := c.sendGetRequest(fmt.Sprintf("/bundles/%s", url.PathEscape(name)))
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(body, &bundleInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &bundleInfo, nil
+}
+
+// DownloadBundle starts the download of the bundle at bundleURI, or of the configured bundle when bundleURI is empty.
+// It returns once the download is started, its progress is sent on the bundle-download event stream.
+func (c *client) DownloadBundle(bundleURI string) (DownloadBundleResult, error) {
+	var dr = DownloadBundleResult{}
+	data, err := json.Marshal(DownloadBundleRequest{
+		BundleURI: bundleURI,
+	})
+	if err != nil {
+		return dr, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/bundles/download", bytes.NewReader(data))
+	if err != nil {
+		return dr, err
+	}
+	err = json.Unmarshal(body, &dr)
+	if err != nil {
+		return dr, err
+	}
+	return dr, nil
+}
+
+func (c *client) RemoveBundle(name string) error {
+	_, err := c.sendDeleteRequest(fmt.Sprintf("/bundles/%s", url.PathEscape(name)), nil)
+	return err
+}
+
+func (c *client) ListPortForwards() (PortForwardsResult, error) {
+	var pr = PortForwardsResult{}
+	body, err := c.sendGetRequest("/port-forwards")
+	if err != nil {
+		return pr, err
+	}
+	err = json.Unmarshal(body, &pr)
+	if err != nil {
+		return pr, err
+	}
+	return pr, nil
+}
+
+func (c *client) AddPortForward(portForward network.PortForward) error {
+	data, err := json.Marshal(portForward)
+	if err != nil {
+		return fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	_, err = c.sendPostRequest("/port-forwards", bytes.NewReader(data))
+	return err
+}
+
+func (c *client) RemovePortForward(portForward network.PortForward) error {
+	_, err := c.sendDeleteRequest(fmt.Sprintf("/port-forwards/%s/%d", url.PathEscape(portForward.Protocol), portForward.HostPort), nil)
+	return err
+}
+
+// Diagnose creates the diagnostics archive at path, or in the crc directory when path is empty
+func (c *client) Diagnose(path string) (DiagnoseResult, error) {
+	var dr = DiagnoseResult{}
+	data, err := json.Marshal(DiagnoseRequest{
+		Path: path,
+	})
+	if err != nil {
+		return dr, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/diagnose", bytes.NewReader(data))
+	if err != nil {
+		return dr, err
+	}
+	err = json.Unmarshal(body, &dr)
+	if err != nil {
+		return dr, err
+	}
+	return dr, nil
+}
+
 func (c *client) sendGetRequest(url string) ([]byte, error) {
 	res, err := c.client.Get(fmt.Sprintf("%s%s", c.base, url))
 	if err != nil {
@@ -257,7 +436,7 @@ func (c *client) sendRequest(url string, method string, data io.Reader) ([]byte,
 
 	switch method {
 	case http.MethodPost:
-		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusAccepted {
 			return nil, fmt.Errorf("Error occurred sending POST request to : %s : %d", url, res.StatusCode)
 		}
 	case http.MethodDelete, http.MethodGet:
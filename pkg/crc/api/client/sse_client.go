@@ -13,8 +13,8 @@ type SSEClient struct {
 	client *sse.Client
 }
 
-func NewSSEClient(transport *http.Transport) *SSEClient {
-	client := sse.NewClient("http://unix/events")
+func NewSSEClient(transport *http.Transport, baseURL string) *SSEClient {
+	client := sse.NewClient(baseURL)
 	client.Connection.Transport = transport
 	return &SSEClient{
 		client: client,
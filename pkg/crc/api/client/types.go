@@ -3,6 +3,7 @@ package client
 import (
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
 	"go.podman.io/common/pkg/strongunits"
 )
@@ -18,6 +19,7 @@ type StartResult struct {
 	Status         string
 	ClusterConfig  types.ClusterConfig
 	KubeletStarted bool
+	PostStartHooks []types.HookResult `json:"PostStartHooks,omitempty"`
 }
 
 type ClusterStatusResult struct {
@@ -66,3 +68,46 @@ type TelemetryRequest struct {
 	Source string `json:"source"`
 	Status string `json:"status"`
 }
+
+type BundlesResult struct {
+	Bundles []types.CachedBundle
+}
+
+type DownloadBundleRequest struct {
+	BundleURI string `json:"bundleURI"`
+}
+
+type DownloadBundleResult struct {
+	Bundle string
+}
+
+type PortForwardsResult struct {
+	PortForwards []network.PortForward
+}
+
+type DiagnoseRequest struct {
+	Path string `json:"path,omitempty"`
+}
+
+type DiagnoseResult struct {
+	Path     string
+	Failures []string `json:"Failures,omitempty"`
+}
+
+type OperationState string
+
+const (
+	OperationRunning   OperationState = "Running"
+	OperationSucceeded OperationState = "Succeeded"
+	OperationFailed    OperationState = "Failed"
+	OperationCancelled OperationState = "Cancelled"
+)
+
+// Operation describes a start or stop running in the background in the daemon
+type Operation struct {
+	ID          string
+	Type        string
+	State       OperationState
+	Error       string       `json:"Error,omitempty"`
+	StartResult *StartResult `json:"StartResult,omitempty"`
+}
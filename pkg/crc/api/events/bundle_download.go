@@ -0,0 +1,66 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/download"
+	"github.com/r3labs/sse/v2"
+)
+
+type BundleDownloadState string
+
+const (
+	BundleDownloadStarted   BundleDownloadState = "Started"
+	BundleDownloadRunning   BundleDownloadState = "Downloading"
+	BundleDownloadCompleted BundleDownloadState = "Completed"
+	BundleDownloadFailed    BundleDownloadState = "Failed"
+)
+
+type BundleDownloadEvent struct {
+	Bundle   string
+	State    BundleDownloadState
+	Progress *download.Progress `json:"Progress,omitempty"`
+	Error    string             `json:"Error,omitempty"`
+}
+
+// Bundle downloads are not tied to an instance, their events are sent to the
+// event servers of all the instances served by the daemon
+var (
+	bundleDownloadServersLock sync.Mutex
+	bundleDownloadServers     []*sse.Server
+)
+
+func registerBundleDownloadServer(server *sse.Server) {
+	bundleDownloadServersLock.Lock()
+	defer bundleDownloadServersLock.Unlock()
+	bundleDownloadServers = append(bundleDownloadServers, server)
+}
+
+// PublishBundleDownload sends event to the subscribers of the bundle-download stream
+func PublishBundleDownload(event BundleDownloadEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		logging.Errorf("unexpected error during bundle download event to JSON conversion: %v", err)
+		return
+	}
+	bundleDownloadServersLock.Lock()
+	defer bundleDownloadServersLock.Unlock()
+	for _, server := range bundleDownloadServers {
+		server.Publish(BUNDLE_DOWNLOAD, &sse.Event{Event: []byte(BUNDLE_DOWNLOAD), Data: data})
+	}
+}
+
+type bundleDownloadStream struct{}
+
+func newBundleDownloadStream() EventStream {
+	return &bundleDownloadStream{}
+}
+
+func (s *bundleDownloadStream) AddSubscriber(_ *sse.Subscriber) {
+	// events are published by PublishBundleDownload when a download progresses
+}
+
+func (s *bundleDownloadStream) RemoveSubscriber(_ *sse.Subscriber) {
+}
@@ -56,6 +56,10 @@ func NewEventServer(machine machine.Client) *EventServer {
 
 	sseServer.CreateStream(LOGS)
 	sseServer.CreateStream(STATUS)
+	sseServer.CreateStream(BUNDLE_DOWNLOAD)
+	sseServer.CreateStream(START)
+	registerBundleDownloadServer(sseServer)
+	registerStartServer(machine.GetName(), sseServer)
 	return eventServer
 }
 
@@ -69,6 +73,10 @@ func createEventStream(server *EventServer, streamID string) EventStream {
 		return newLogsStream(server)
 	case STATUS:
 		return newStatusStream(server)
+	case BUNDLE_DOWNLOAD:
+		return newBundleDownloadStream()
+	case START:
+		return newStartStream()
 	}
 	return nil
 }
@@ -5,6 +5,9 @@ import "github.com/r3labs/sse/v2"
 const (
 	LOGS   = "logs"   // Logs event channel, contains daemon logs
 	STATUS = "status" // status event channel, contains VM load info
+
+	BUNDLE_DOWNLOAD = "bundle-download" // bundle download event channel, contains the state of the bundle downloads
+	START           = "start"           // start event channel, contains the progress of the start phases
 )
 
 type EventPublisher interface {
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/r3labs/sse/v2"
+)
+
+// The start progress is reported by the API handlers which don't have access
+// to the event server, the servers are looked up by instance name instead
+var (
+	startServersLock sync.Mutex
+	startServers     = map[string][]*sse.Server{}
+)
+
+func registerStartServer(instanceName string, server *sse.Server) {
+	startServersLock.Lock()
+	defer startServersLock.Unlock()
+	startServers[instanceName] = append(startServers[instanceName], server)
+}
+
+// PublishStartProgress sends progress to the subscribers of the start stream of the instanceName instance
+func PublishStartProgress(instanceName string, progress types.StartProgress) {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		logging.Errorf("unexpected error during start progress to JSON conversion: %v", err)
+		return
+	}
+	startServersLock.Lock()
+	defer startServersLock.Unlock()
+	for _, server := range startServers[instanceName] {
+		server.Publish(START, &sse.Event{Event: []byte(START), Data: data})
+	}
+}
+
+type startStream struct{}
+
+func newStartStream() EventStream {
+	return &startStream{}
+}
+
+func (s *startStream) AddSubscriber(_ *sse.Subscriber) {
+	// events are published by PublishStartProgress while the instance starts
+}
+
+func (s *startStream) RemoveSubscriber(_ *sse.Subscriber) {
+}
@@ -2,19 +2,32 @@ package api
 
 import (
 	gocontext "context"
+	goerrors "errors"
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"go.podman.io/common/pkg/strongunits"
 
 	"github.com/crc-org/crc/v2/pkg/crc/api/client"
+	"github.com/crc-org/crc/v2/pkg/crc/api/events"
 	"github.com/crc-org/crc/v2/pkg/crc/cluster"
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
 	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/metrics"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/preflight"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/crc-org/crc/v2/pkg/crc/version"
+	"github.com/crc-org/crc/v2/pkg/download"
 )
 
 type Handler struct {
@@ -22,6 +35,8 @@ type Handler struct {
 	Client    machine.Client
 	Config    *crcConfig.Config
 	Telemetry Telemetry
+
+	operations *operations
 }
 
 type Logger interface {
@@ -48,6 +63,8 @@ func NewHandler(config *crcConfig.Config, machine machine.Client, logger Logger,
 		Config:    config,
 		Logger:    logger,
 		Telemetry: telemetry,
+
+		operations: newOperations(),
 	}
 }
 
@@ -78,7 +95,17 @@ func (h *Handler) Status(c *context) error {
 	})
 }
 
+// Stop stops the instance and returns once it is stopped. When called with
+// POST /stop?async=true, it stops the instance in the background and returns
+// the operation tracking it instead.
 func (h *Handler) Stop(c *context) error {
+	if c.method == http.MethodPost && c.url.Query().Get("async") == "true" {
+		op := h.operations.run(stopOperation, func() (*client.StartResult, error) {
+			_, err := h.Client.Stop()
+			return nil, err
+		}, nil)
+		return c.JSON(http.StatusAccepted, op)
+	}
 	_, err := h.Client.Stop()
 	if err != nil {
 		return err
@@ -96,6 +123,8 @@ func (h *Handler) PowerOff(c *context) error {
 	return c.Code(http.StatusOK)
 }
 
+// Start starts the instance in the background when called with POST and
+// returns the operation tracking it. GET blocks until the start is complete.
 func (h *Handler) Start(c *context) error {
 	crcConfig.UpdateDefaults(h.Config)
 	var parsedArgs client.StartConfig
@@ -104,20 +133,77 @@ func (h *Handler) Start(c *context) error {
 			return err
 		}
 	}
-	if err := preflight.StartPreflightChecks(h.Config); err != nil {
+	if err := preflight.StartPreflightChecks(h.Config, h.Client.GetName()); err != nil {
 		return err
 	}
 
 	startConfig := getStartConfig(h.Config, parsedArgs)
-	res, err := h.Client.Start(gocontext.Background(), startConfig)
+	if c.method == http.MethodPost {
+		op := h.operations.run(startOperation, func() (*client.StartResult, error) {
+			return h.start(startConfig)
+		}, h.cancelStart)
+		return c.JSON(http.StatusAccepted, op)
+	}
+
+	res, err := h.start(startConfig)
 	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, client.StartResult{
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) start(startConfig types.StartConfig) (*client.StartResult, error) {
+	ctx := machine.WithStartProgressReporter(gocontext.Background(), machine.StartProgressReporterFunc(func(progress types.StartProgress) {
+		events.PublishStartProgress(h.Client.GetName(), progress)
+		metrics.ObserveStartProgress(h.Client.GetName(), progress)
+	}))
+	startTime := time.Now()
+	res, err := h.Client.Start(ctx, startConfig)
+	metrics.ObserveStart(h.Client.GetName(), time.Since(startTime), err)
+	if err != nil {
+		return nil, err
+	}
+	return &client.StartResult{
 		Status:         string(res.Status),
 		ClusterConfig:  res.ClusterConfig,
 		KubeletStarted: res.KubeletStarted,
-	})
+		PostStartHooks: res.PostStartHooks,
+	}, nil
+}
+
+// startCanceller is implemented by machine.Synchronized
+type startCanceller interface {
+	CancelStart() error
+}
+
+func (h *Handler) cancelStart() error {
+	canceller, ok := h.Client.(startCanceller)
+	if !ok {
+		return fmt.Errorf("cancelling a start is not supported")
+	}
+	return canceller.CancelStart()
+}
+
+func (h *Handler) GetOperation(c *context) error {
+	op, ok := h.operations.Get(c.Param("id"))
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("operation %s not found", c.Param("id")))
+	}
+	return c.JSON(http.StatusOK, op)
+}
+
+func (h *Handler) CancelOperation(c *context) error {
+	op, ok, err := h.operations.Cancel(c.Param("id"))
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("operation %s not found", c.Param("id")))
+	}
+	if goerrors.Is(err, errOperationNotCancellable) {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, op)
 }
 
 func getStartConfig(cfg crcConfig.Storage, args client.StartConfig) types.StartConfig {
@@ -136,6 +222,14 @@ func getStartConfig(cfg crcConfig.Storage, args client.StartConfig) types.StartC
 		EnableSharedDirs:         cfg.Get(crcConfig.EnableSharedDirs).AsBool(),
 		EmergencyLogin:           cfg.Get(crcConfig.EmergencyLogin).AsBool(),
 		EnableBundleQuayFallback: cfg.Get(crcConfig.EnableBundleQuayFallback).AsBool(),
+		BundleMirror:             cfg.Get(crcConfig.BundleMirror).AsString(),
+		AppsDomain:               cfg.Get(crcConfig.AppsDomain).AsString(),
+		IngressTLSCertFile:       cfg.Get(crcConfig.IngressTLSCertFile).AsString(),
+		IngressTLSKeyFile:        cfg.Get(crcConfig.IngressTLSKeyFile).AsString(),
+		APITLSCertFile:           cfg.Get(crcConfig.APITLSCertFile).AsString(),
+		APITLSKeyFile:            cfg.Get(crcConfig.APITLSKeyFile).AsString(),
+		TLSCAFile:                cfg.Get(crcConfig.TLSCAFile).AsString(),
+		TrustClusterCA:           cfg.Get(crcConfig.TrustClusterCA).AsBool(),
 	}
 }
 
@@ -187,6 +281,10 @@ func (h *Handler) SetConfig(c *context) error {
 	var successProps []string
 	var multiError = errors.MultiError{}
 	for k, v := range req.Properties {
+		if err := crcConfig.ValidateInstanceSetting(h.Client.GetName(), k); err != nil {
+			multiError.Collect(err)
+			continue
+		}
 		_, err := h.Config.Set(k, v)
 		if err != nil {
 			multiError.Collect(err)
@@ -282,3 +380,168 @@ func (h *Handler) UploadTelemetry(c *context) error {
 	}
 	return c.Code(http.StatusOK)
 }
+
+func (h *Handler) ListBundles(c *context) error {
+	bundles, err := h.Client.ListBundles()
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, client.BundlesResult{
+		Bundles: bundles,
+	})
+}
+
+func (h *Handler) GetBundle(c *context) error {
+	bundleInfo, err := h.Client.GetBundle(c.Param("name"))
+	if goerrors.Is(err, os.ErrNotExist) {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, bundleInfo)
+}
+
+func (h *Handler) RemoveBundle(c *context) error {
+	if err := h.Client.RemoveBundle(c.Param("name")); err != nil {
+		return err
+	}
+	return c.Code(http.StatusOK)
+}
+
+// bundleDownloads contains the bundles being downloaded, they are shared by all the instances
+var (
+	bundleDownloadsLock sync.Mutex
+	bundleDownloads     = map[string]struct{}{}
+)
+
+func (h *Handler) DownloadBundle(c *context) error {
+	var req client.DownloadBundleRequest
+	if len(c.requestBody) > 0 {
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+	}
+	bundleURI := req.BundleURI
+	if bundleURI == "" {
+		bundleURI = h.Config.Get(crcConfig.Bundle).AsString()
+	}
+	bundleFilename, err := bundle.GetBundleNameFromURI(bundleURI)
+	if err != nil {
+		return err
+	}
+	bundleName := bundle.GetBundleNameWithoutExtension(bundleFilename)
+
+	bundleDownloadsLock.Lock()
+	defer bundleDownloadsLock.Unlock()
+	if _, ok := bundleDownloads[bundleName]; ok {
+		return c.String(http.StatusConflict, fmt.Sprintf("bundle %s is already being downloaded", bundleName))
+	}
+	bundleDownloads[bundleName] = struct{}{}
+
+	go func() {
+		defer func() {
+			bundleDownloadsLock.Lock()
+			defer bundleDownloadsLock.Unlock()
+			delete(bundleDownloads, bundleName)
+		}()
+		events.PublishBundleDownload(events.BundleDownloadEvent{
+			Bundle: bundleName,
+			State:  events.BundleDownloadStarted,
+		})
+		ctx := download.WithProgressReporter(gocontext.Background(), download.ProgressReporterFunc(func(progress download.Progress) {
+			events.PublishBundleDownload(events.BundleDownloadEvent{
+				Bundle:   bundleName,
+				State:    events.BundleDownloadRunning,
+				Progress: &progress,
+			})
+		}))
+		if _, err := h.Client.DownloadBundle(ctx, bundleURI); err != nil {
+			logging.Errorf("Failed to download bundle %s: %v", bundleName, err)
+			events.PublishBundleDownload(events.BundleDownloadEvent{
+				Bundle: bundleName,
+				State:  events.BundleDownloadFailed,
+				Error:  err.Error(),
+			})
+			return
+		}
+		events.PublishBundleDownload(events.BundleDownloadEvent{
+			Bundle: bundleName,
+			State:  events.BundleDownloadCompleted,
+		})
+	}()
+
+	return c.JSON(http.StatusAccepted, client.DownloadBundleResult{
+		Bundle: bundleName,
+	})
+}
+
+func (h *Handler) ListPortForwards(c *context) error {
+	portForwards, err := h.Client.ListPortForwards()
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, client.PortForwardsResult{
+		PortForwards: portForwards,
+	})
+}
+
+func (h *Handler) AddPortForward(c *context) error {
+	var portForward network.PortForward
+	if err := c.Bind(&portForward); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if portForward.Protocol == "" {
+		portForward.Protocol = network.TCPProtocol
+	}
+	// round-trip through the string format to validate the ports and protocol
+	portForward, err := network.ParsePortForward(portForward.String())
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err := h.Client.AddPortForward(portForward); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, portForward)
+}
+
+// Diagnose creates the diagnostics archive at the requested path, or in the
+// crc directory when no path is given. Existing files are not overwritten.
+func (h *Handler) Diagnose(c *context) error {
+	var req client.DiagnoseRequest
+	if len(c.requestBody) > 0 {
+		if err := c.Bind(&req); err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+	}
+	path := req.Path
+	if path == "" {
+		path = filepath.Join(constants.CrcBaseDir, machine.DiagnosticsArchiveName(h.Client.GetName(), time.Now()))
+	}
+	if !filepath.IsAbs(path) {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("the path of the archive must be absolute: %s", path))
+	}
+	// the archive is written by the daemon, it must not replace an existing file
+	if _, err := os.Lstat(path); !goerrors.Is(err, os.ErrNotExist) {
+		return c.String(http.StatusConflict, fmt.Sprintf("the archive cannot replace an existing file: %s", path))
+	}
+	diagnostics, err := h.Client.Diagnose(gocontext.Background(), path)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, client.DiagnoseResult{
+		Path:     diagnostics.Path,
+		Failures: diagnostics.Failures,
+	})
+}
+
+func (h *Handler) RemovePortForward(c *context) error {
+	portForward, err := network.ParseHostPort(fmt.Sprintf("%s/%s", c.Param("port"), c.Param("protocol")))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err := h.Client.RemovePortForward(portForward); err != nil {
+		return err
+	}
+	return c.Code(http.StatusOK)
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
@@ -14,6 +15,7 @@ type context struct {
 	method      string
 	requestBody []byte
 	url         *url.URL
+	params      map[string]string
 
 	code         int
 	headers      map[string]string
@@ -24,6 +26,11 @@ func (c *context) Bind(r interface{}) error {
 	return json.Unmarshal(c.requestBody, r)
 }
 
+// Param returns the value of the {name} segment of the route pattern
+func (c *context) Param(name string) string {
+	return c.params[name]
+}
+
 func (c *context) JSON(code int, r interface{}) error {
 	c.code = code
 	var err error
@@ -82,6 +89,45 @@ func (s *server) DELETE(pattern string, handler func(c *context) error) {
 	s.routes[pattern][http.MethodDelete] = handler
 }
 
+// match looks up the route for path. Routes without parameters are matched first,
+// then the patterns with {name} segments, which match any non-empty path segment.
+// It must be called with routesLock held.
+func (s *server) match(path string) (string, map[string]func(*context) error, map[string]string, bool) {
+	if route, ok := s.routes[path]; ok {
+		return path, route, nil, true
+	}
+	segments := strings.Split(path, "/")
+	for pattern, route := range s.routes {
+		if !strings.Contains(pattern, "{") {
+			continue
+		}
+		if params, ok := matchSegments(strings.Split(pattern, "/"), segments); ok {
+			return pattern, route, params, true
+		}
+	}
+	return "", nil, nil, false
+}
+
+func matchSegments(patternSegments, segments []string) (map[string]string, bool) {
+	if len(patternSegments) != len(segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, patternSegment := range patternSegments {
+		if strings.HasPrefix(patternSegment, "{") && strings.HasSuffix(patternSegment, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[strings.TrimSuffix(strings.TrimPrefix(patternSegment, "{"), "}")] = segments[i]
+			continue
+		}
+		if patternSegment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
 func (s *server) Handler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		status := http.StatusOK
@@ -99,7 +145,7 @@ func (s *server) Handler() http.Handler {
 		}()
 
 		s.routesLock.RLock()
-		route, ok := s.routes[r.URL.Path]
+		_, route, params, ok := s.match(r.URL.Path)
 		if !ok {
 			s.routesLock.RUnlock()
 			status = http.StatusNotFound
@@ -126,6 +172,7 @@ func (s *server) Handler() http.Handler {
 			requestBody: requestBody,
 			headers:     make(map[string]string),
 			url:         r.URL,
+			params:      params,
 		}
 		if err := handler(c); err != nil {
 			status = http.StatusInternalServerError
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/api/client"
+	"github.com/pborman/uuid"
+)
+
+const (
+	startOperation = "start"
+	stopOperation  = "stop"
+
+	// operationTTL is how long finished operations can still be queried
+	operationTTL = time.Hour
+)
+
+var errOperationNotCancellable = errors.New("only running start operations can be cancelled")
+
+// operations keeps track of the long running requests which are handled in
+// the background, clients poll them with GET /operations/{id}
+type operations struct {
+	lock       sync.Mutex
+	operations map[string]*operation
+	newID      func() string
+	now        func() time.Time
+	running    sync.WaitGroup
+}
+
+type operation struct {
+	client.Operation
+	cancel   func() error
+	finished time.Time
+}
+
+func newOperations() *operations {
+	return &operations{
+		operations: map[string]*operation{},
+		newID:      uuid.New,
+		now:        time.Now,
+	}
+}
+
+// prune removes the operations which finished more than operationTTL ago, it
+// must be called with the lock held
+func (o *operations) prune() {
+	for id, op := range o.operations {
+		if !op.finished.IsZero() && o.now().Sub(op.finished) > operationTTL {
+			delete(o.operations, id)
+		}
+	}
+}
+
+// run starts fn in a goroutine and returns the operation tracking it. cancel
+// is used by Cancel to abort fn, it may be nil if fn cannot be cancelled.
+func (o *operations) run(operationType string, fn func() (*client.StartResult, error), cancel func() error) client.Operation {
+	op := &operation{
+		Operation: client.Operation{
+			ID:    o.newID(),
+			Type:  operationType,
+			State: client.OperationRunning,
+		},
+		cancel: cancel,
+	}
+
+	started := op.Operation
+
+	o.lock.Lock()
+	o.prune()
+	o.operations[op.ID] = op
+	o.lock.Unlock()
+
+	o.running.Add(1)
+	go func() {
+		defer o.running.Done()
+		result, err := fn()
+
+		o.lock.Lock()
+		defer o.lock.Unlock()
+		if op.finished.IsZero() {
+			op.finished = o.now()
+		}
+		switch {
+		case op.State == client.OperationCancelled:
+		case err != nil:
+			op.State = client.OperationFailed
+			op.Error = err.Error()
+		default:
+			op.State = client.OperationSucceeded
+			op.StartResult = result
+		}
+	}()
+
+	return started
+}
+
+// wait blocks until all the operations are complete
+func (o *operations) wait() {
+	o.running.Wait()
+}
+
+func (o *operations) Get(id string) (client.Operation, bool) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.prune()
+	op, ok := o.operations[id]
+	if !ok {
+		return client.Operation{}, false
+	}
+	return op.Operation, true
+}
+
+func (o *operations) Cancel(id string) (client.Operation, bool, error) {
+	o.lock.Lock()
+	op, ok := o.operations[id]
+	if !ok {
+		o.lock.Unlock()
+		return client.Operation{}, false, nil
+	}
+	if op.State != client.OperationRunning || op.cancel == nil {
+		o.lock.Unlock()
+		return op.Operation, true, errOperationNotCancellable
+	}
+	cancel := op.cancel
+	o.lock.Unlock()
+
+	err := cancel()
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if err != nil {
+		return op.Operation, true, err
+	}
+	op.State = client.OperationCancelled
+	op.Error = ""
+	op.finished = o.now()
+	return op.Operation, true, nil
+}
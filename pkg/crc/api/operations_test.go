@@ -0,0 +1,108 @@
+package api
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/api/client"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingMachine is a fake machine whose start only ends when cancelled
+type blockingMachine struct {
+	*fakemachine.Client
+	started chan struct{}
+}
+
+func (m *blockingMachine) Start(ctx gocontext.Context, _ types.StartConfig) (*types.StartResult, error) {
+	m.started <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func sendOperationRequest(t *testing.T, handler http.Handler, req request, expectedStatus int) client.Operation {
+	resp := sendRequest(handler, &req)
+	require.Equal(t, expectedStatus, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var op client.Operation
+	require.NoError(t, json.Unmarshal(body, &op))
+	return op
+}
+
+func TestStartOperation(t *testing.T) {
+	server := newMockServer("")
+
+	op := sendOperationRequest(t, server.Handler(), post("start"), http.StatusAccepted)
+	assert.Equal(t, "start", op.Type)
+	assert.Equal(t, client.OperationRunning, op.State)
+
+	assert.Eventually(t, func() bool {
+		op = sendOperationRequest(t, server.Handler(), get("operations/"+op.ID), http.StatusOK)
+		return op.State != client.OperationRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, client.OperationSucceeded, op.State)
+	require.NotNil(t, op.StartResult)
+	assert.True(t, op.StartResult.KubeletStarted)
+}
+
+func TestCancelStartOperation(t *testing.T) {
+	fakeMachine := &blockingMachine{
+		Client:  fakemachine.NewClient(),
+		started: make(chan struct{}, 1),
+	}
+	handler := NewHandler(setupNewInMemoryConfig(), machine.NewSynchronizedMachine(fakeMachine), &mockLogger{}, &mockTelemetry{})
+	server := newServerWithRoutes(handler).Handler()
+
+	op := sendOperationRequest(t, server, post("start"), http.StatusAccepted)
+	<-fakeMachine.started
+
+	cancelled := sendOperationRequest(t, server, deleteRequest("operations/"+op.ID), http.StatusOK)
+	assert.Equal(t, client.OperationCancelled, cancelled.State)
+	assert.Equal(t, op.ID, cancelled.ID)
+
+	assert.Equal(t, cancelled, sendOperationRequest(t, server, get("operations/"+op.ID), http.StatusOK))
+
+	resp := sendRequest(server, &request{httpMethod: http.MethodDelete, resource: "operations/" + op.ID})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestPruneFinishedOperations(t *testing.T) {
+	ops := newOperations()
+	now := time.Now()
+	ops.now = func() time.Time {
+		return now
+	}
+
+	finished := ops.run(stopOperation, func() (*client.StartResult, error) {
+		return nil, nil
+	}, nil)
+	ops.wait()
+
+	unblock := make(chan struct{})
+	running := ops.run(stopOperation, func() (*client.StartResult, error) {
+		<-unblock
+		return nil, nil
+	}, nil)
+	defer func() {
+		close(unblock)
+		ops.wait()
+	}()
+
+	_, ok := ops.Get(finished.ID)
+	assert.True(t, ok)
+
+	now = now.Add(operationTTL + time.Second)
+	_, ok = ops.Get(finished.ID)
+	assert.False(t, ok)
+	_, ok = ops.Get(running.ID)
+	assert.True(t, ok)
+}
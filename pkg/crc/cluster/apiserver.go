@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+const customAPIServerCertSecret = "crc-custom-apiserver-cert"
+
+// SetAPIServerCertificate makes the API server present certPEM/keyPEM to the
+// clients connecting to hostname, the kube-apiserver pods are then redeployed
+func SetAPIServerCertificate(ctx context.Context, ocConfig oc.Config, sshRunner *ssh.Runner, hostname string, certPEM, keyPEM []byte) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "apiserver.config.openshift.io"); err != nil {
+		return err
+	}
+	if err := applyTLSSecret(sshRunner, ocConfig, customAPIServerCertSecret, "openshift-config", certPEM, keyPEM); err != nil {
+		return fmt.Errorf("failed to add API server certificate: %w", err)
+	}
+	apiServerPatch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"servingCerts": map[string]interface{}{
+				"namedCertificates": []map[string]interface{}{
+					{
+						"names":              []string{hostname},
+						"servingCertificate": map[string]string{"name": customAPIServerCertSecret},
+					},
+				},
+			},
+		},
+	}
+	if err := patchResource(ocConfig, apiServerPatch, "apiserver.config.openshift.io", "cluster"); err != nil {
+		return fmt.Errorf("failed to update the API server certificate: %w", err)
+	}
+	return nil
+}
+
+// ResetAPIServerCertificate reverts the changes done by SetAPIServerCertificate
+func ResetAPIServerCertificate(ctx context.Context, ocConfig oc.Config) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "apiserver.config.openshift.io"); err != nil {
+		return err
+	}
+	apiServerPatch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"servingCerts": nil,
+		},
+	}
+	if err := patchResource(ocConfig, apiServerPatch, "apiserver.config.openshift.io", "cluster"); err != nil {
+		return fmt.Errorf("failed to reset the API server certificate: %w", err)
+	}
+	deleteSecret(ocConfig, customAPIServerCertSecret, "openshift-config")
+	return nil
+}
@@ -17,7 +17,6 @@ import (
 
 	"go.podman.io/common/pkg/strongunits"
 
-	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/errors"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
 	"github.com/crc-org/crc/v2/pkg/crc/network/httpproxy"
@@ -218,7 +217,7 @@ func EnsurePullSecretPresentInTheCluster(ctx context.Context, ocConfig oc.Config
 	return nil
 }
 
-func EnsureGeneratedClientCAPresentInTheCluster(ctx context.Context, ocConfig oc.Config, sshRunner *ssh.Runner, selfSignedCACert *x509.Certificate, adminCert string) error {
+func EnsureGeneratedClientCAPresentInTheCluster(ctx context.Context, ocConfig oc.Config, sshRunner *ssh.Runner, selfSignedCACert *x509.Certificate, adminCert string, kubeconfigFilePath string) error {
 	selfSignedCAPem := crctls.CertToPem(selfSignedCACert)
 	if err := WaitForOpenshiftResource(ctx, ocConfig, "configmaps"); err != nil {
 		return err
@@ -228,7 +227,7 @@ func EnsureGeneratedClientCAPresentInTheCluster(ctx context.Context, ocConfig oc
 		return fmt.Errorf("failed to get config map: %s: %w", stderr, err)
 	}
 
-	ok, err := crctls.VerifyCertificateAgainstRootCA(clusterClientCA, adminCert)
+	ok, err := crctls.VerifyCertificateAgainstRootCA(clusterClientCA, adminCert, x509.ExtKeyUsageClientAuth)
 	if err != nil {
 		return err
 	}
@@ -244,7 +243,7 @@ func EnsureGeneratedClientCAPresentInTheCluster(ctx context.Context, ocConfig oc
 	if err != nil {
 		return fmt.Errorf("failed to patch admin-kubeconfig-client-ca config map with new CA: %s: %w", stderr, err)
 	}
-	if err := sshRunner.CopyFile(constants.KubeconfigFilePath, ocConfig.KubeconfigPath, 0644); err != nil {
+	if err := sshRunner.CopyFile(kubeconfigFilePath, ocConfig.KubeconfigPath, 0644); err != nil {
 		return fmt.Errorf("failed to copy generated kubeconfig file to VM: %w", err)
 	}
 
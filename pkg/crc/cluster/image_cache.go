@@ -0,0 +1,46 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+const imageCacheMirrorSet = "crc-image-cache"
+
+// ApplyImageDigestMirrorSet makes the cluster pull the images referenced by
+// digest of the registries from the location returned by mirror.
+// The registries are still used when their mirror is unreachable.
+func ApplyImageDigestMirrorSet(sshRunner *ssh.Runner, ocConfig oc.Config, registries []string, mirror func(registry string) string) error {
+	var imageDigestMirrors []map[string]interface{}
+	for _, registry := range registries {
+		imageDigestMirrors = append(imageDigestMirrors, map[string]interface{}{
+			"source":             registry,
+			"mirrors":            []string{mirror(registry)},
+			"mirrorSourcePolicy": "AllowContactingSource",
+		})
+	}
+	mirrorSet := map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "ImageDigestMirrorSet",
+		"metadata": map[string]string{
+			"name": imageCacheMirrorSet,
+		},
+		"spec": map[string]interface{}{
+			"imageDigestMirrors": imageDigestMirrors,
+		},
+	}
+	if err := applyJSON(sshRunner, ocConfig, mirrorSet, "/tmp/crc-image-cache.json"); err != nil {
+		return fmt.Errorf("failed to apply the image digest mirror set: %w", err)
+	}
+	return nil
+}
+
+// DeleteImageDigestMirrorSet removes the mirror set created by ApplyImageDigestMirrorSet
+func DeleteImageDigestMirrorSet(ocConfig oc.Config) error {
+	if _, stderr, err := ocConfig.RunOcCommand("delete", "imagedigestmirrorset", imageCacheMirrorSet, "--ignore-not-found"); err != nil {
+		return fmt.Errorf("failed to delete the image digest mirror set: %s: %w", stderr, err)
+	}
+	return nil
+}
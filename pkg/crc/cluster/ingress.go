@@ -0,0 +1,163 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+const customIngressCertSecret = "crc-custom-ingress-cert"
+
+type componentRoute struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Hostname  string `json:"hostname"`
+}
+
+// componentRoutes returns the routes of the cluster components which are
+// moved to appsDomain, they keep their default host name prefix
+func componentRoutes(appsDomain string) []componentRoute {
+	return []componentRoute{
+		{
+			Name:      "console",
+			Namespace: "openshift-console",
+			Hostname:  fmt.Sprintf("console-openshift-console.%s", appsDomain),
+		},
+		{
+			Name:      "downloads",
+			Namespace: "openshift-console",
+			Hostname:  fmt.Sprintf("downloads-openshift-console.%s", appsDomain),
+		},
+		{
+			Name:      "oauth-openshift",
+			Namespace: "openshift-authentication",
+			Hostname:  fmt.Sprintf("oauth-openshift.%s", appsDomain),
+		},
+	}
+}
+
+// SetAppsDomain moves the console, downloads and oauth routes under
+// appsDomain, which is also used for the routes created without a host
+func SetAppsDomain(ctx context.Context, ocConfig oc.Config, appsDomain string) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "ingress.config.openshift.io"); err != nil {
+		return err
+	}
+	ingressPatch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"appsDomain":      appsDomain,
+			"componentRoutes": componentRoutes(appsDomain),
+		},
+	}
+	if err := patchResource(ocConfig, ingressPatch, "ingress.config.openshift.io", "cluster"); err != nil {
+		return fmt.Errorf("failed to update the apps domain: %w", err)
+	}
+	return nil
+}
+
+// ResetAppsDomain reverts the changes done by SetAppsDomain
+func ResetAppsDomain(ctx context.Context, ocConfig oc.Config) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "ingress.config.openshift.io"); err != nil {
+		return err
+	}
+	ingressPatch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"appsDomain":      nil,
+			"componentRoutes": nil,
+		},
+	}
+	if err := patchResource(ocConfig, ingressPatch, "ingress.config.openshift.io", "cluster"); err != nil {
+		return fmt.Errorf("failed to reset the apps domain: %w", err)
+	}
+	return nil
+}
+
+// SetDefaultIngressCertificate makes the default router serve certPEM/keyPEM
+func SetDefaultIngressCertificate(ctx context.Context, ocConfig oc.Config, sshRunner *ssh.Runner, certPEM, keyPEM []byte) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "ingresscontroller"); err != nil {
+		return err
+	}
+	if err := applyTLSSecret(sshRunner, ocConfig, customIngressCertSecret, "openshift-ingress", certPEM, keyPEM); err != nil {
+		return fmt.Errorf("failed to add router certificate: %w", err)
+	}
+	ingressControllerPatch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"defaultCertificate": map[string]string{"name": customIngressCertSecret},
+		},
+	}
+	if err := patchResource(ocConfig, ingressControllerPatch, "ingresscontroller", "default", "-n", "openshift-ingress-operator"); err != nil {
+		return fmt.Errorf("failed to update the default router certificate: %w", err)
+	}
+	return nil
+}
+
+// ResetDefaultIngressCertificate makes the default router serve the certificate generated by the cluster
+func ResetDefaultIngressCertificate(ctx context.Context, ocConfig oc.Config) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "ingresscontroller"); err != nil {
+		return err
+	}
+	ingressControllerPatch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"defaultCertificate": nil,
+		},
+	}
+	if err := patchResource(ocConfig, ingressControllerPatch, "ingresscontroller", "default", "-n", "openshift-ingress-operator"); err != nil {
+		return fmt.Errorf("failed to reset the default router certificate: %w", err)
+	}
+	deleteSecret(ocConfig, customIngressCertSecret, "openshift-ingress")
+	return nil
+}
+
+func applyTLSSecret(sshRunner *ssh.Runner, ocConfig oc.Config, name, namespace string, certPEM, keyPEM []byte) error {
+	secret := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "kubernetes.io/tls",
+		"metadata": map[string]string{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"data": map[string][]byte{
+			"tls.crt": certPEM,
+			"tls.key": keyPEM,
+		},
+	}
+	return applyJSON(sshRunner, ocConfig, secret, fmt.Sprintf("/tmp/%s.json", name))
+}
+
+func deleteSecret(ocConfig oc.Config, name, namespace string) {
+	if _, stderr, err := ocConfig.RunOcCommand("delete", "secret", name, "-n", namespace, "--ignore-not-found"); err != nil {
+		logging.Debugf("Failed to delete %s secret: %s: %v", name, stderr, err)
+	}
+}
+
+func applyJSON(sshRunner *ssh.Runner, ocConfig oc.Config, resource interface{}, fileName string) error {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return fmt.Errorf("failed to encode to json: %w", err)
+	}
+	if err := sshRunner.CopyDataPrivileged(data, fileName, 0600); err != nil {
+		return err
+	}
+	if _, stderr, err := ocConfig.RunOcCommandPrivate("apply", "-f", fileName); err != nil {
+		return fmt.Errorf("%s: %w", stderr, err)
+	}
+	return nil
+}
+
+func patchResource(ocConfig oc.Config, patch interface{}, kind, name string, args ...string) error {
+	patchEncode, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to encode to json: %w", err)
+	}
+	logging.Debugf("Patch string %s", string(patchEncode))
+
+	cmdArgs := append([]string{"patch", kind, name, "-p", fmt.Sprintf("'%s'", string(patchEncode)), "--type", "merge"}, args...)
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("%s: %w", stderr, err)
+	}
+	return nil
+}
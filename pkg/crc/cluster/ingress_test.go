@@ -0,0 +1,19 @@
+package cluster
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentRoutes(t *testing.T) {
+	routes, err := json.Marshal(componentRoutes("apps.dev.example.com"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+  {"name": "console", "namespace": "openshift-console", "hostname": "console-openshift-console.apps.dev.example.com"},
+  {"name": "downloads", "namespace": "openshift-console", "hostname": "downloads-openshift-console.apps.dev.example.com"},
+  {"name": "oauth-openshift", "namespace": "openshift-authentication", "hostname": "oauth-openshift.apps.dev.example.com"}
+]`, string(routes))
+}
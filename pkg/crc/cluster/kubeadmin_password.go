@@ -27,9 +27,9 @@ func GenerateUserPassword(passwordFile string, user string) error {
 	return os.WriteFile(passwordFile, []byte(password), 0600)
 }
 
-// UpdateUserPasswords updates the htpasswd secret
-func UpdateUserPasswords(ctx context.Context, ocConfig oc.Config, newKubeAdminPassword string, newDeveloperPassword string) error {
-	credentials, err := resolveUserPasswords(newKubeAdminPassword, newDeveloperPassword, constants.GetKubeAdminPasswordPath(), constants.GetDeveloperPasswordPath())
+// UpdateUserPasswords updates the htpasswd secret of the instance called name
+func UpdateUserPasswords(ctx context.Context, ocConfig oc.Config, name string, newKubeAdminPassword string, newDeveloperPassword string) error {
+	credentials, err := resolveUserPasswords(newKubeAdminPassword, newDeveloperPassword, constants.GetKubeAdminPasswordPath(name), constants.GetDeveloperPasswordPath(name))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,111 @@
+package cluster
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+const (
+	// namespace of the global operator group of OpenShift
+	globalOperatorsNamespace = "openshift-operators"
+	marketplaceNamespace     = "openshift-marketplace"
+)
+
+// CreateNamespace creates the namespace called name if it doesn't exist
+func CreateNamespace(sshRunner *ssh.Runner, ocConfig oc.Config, name string) error {
+	namespace := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata": map[string]string{
+			"name": name,
+		},
+	}
+	if err := applyJSON(sshRunner, ocConfig, namespace, fmt.Sprintf("/tmp/namespace-%s.json", name)); err != nil {
+		return fmt.Errorf("failed to create namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+// ApplyManifest runs 'oc apply' with manifest, which is a local file or an
+// http(s) URL, and extra args. It returns the output of the command.
+func ApplyManifest(sshRunner *ssh.Runner, ocConfig oc.Config, manifest string, args ...string) (string, error) {
+	source := manifest
+	if !strings.HasPrefix(manifest, "http://") && !strings.HasPrefix(manifest, "https://") {
+		source = fmt.Sprintf("/tmp/manifest-%s", filepath.Base(manifest))
+		if err := sshRunner.CopyFile(manifest, source, 0600); err != nil {
+			return "", fmt.Errorf("failed to copy %s to the instance: %w", manifest, err)
+		}
+	}
+	stdout, stderr, err := ocConfig.RunOcCommandPrivate(append([]string{"apply", "-f", source}, args...)...)
+	if err != nil {
+		return stdout, fmt.Errorf("failed to apply %s: %s: %w", manifest, stderr, err)
+	}
+	return stdout, nil
+}
+
+// SubscribeToOperator installs the operator called name from the catalog source.
+// The namespace is created with an operator group when it's not the global one.
+func SubscribeToOperator(sshRunner *ssh.Runner, ocConfig oc.Config, name, channel, source, namespace string) error {
+	if namespace != globalOperatorsNamespace {
+		if err := CreateNamespace(sshRunner, ocConfig, namespace); err != nil {
+			return err
+		}
+		if err := ensureOperatorGroup(sshRunner, ocConfig, namespace); err != nil {
+			return err
+		}
+	}
+	spec := map[string]string{
+		"name":                name,
+		"source":              source,
+		"sourceNamespace":     marketplaceNamespace,
+		"installPlanApproval": "Automatic",
+	}
+	if channel != "" {
+		spec["channel"] = channel
+	}
+	subscription := map[string]interface{}{
+		"apiVersion": "operators.coreos.com/v1alpha1",
+		"kind":       "Subscription",
+		"metadata": map[string]string{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	}
+	if err := applyJSON(sshRunner, ocConfig, subscription, fmt.Sprintf("/tmp/subscription-%s.json", name)); err != nil {
+		return fmt.Errorf("failed to subscribe to operator %s: %w", name, err)
+	}
+	return nil
+}
+
+// ensureOperatorGroup creates an operator group watching namespace when it has none
+func ensureOperatorGroup(sshRunner *ssh.Runner, ocConfig oc.Config, namespace string) error {
+	stdout, stderr, err := ocConfig.RunOcCommand("get", "operatorgroup", "-n", namespace, "-o", "name")
+	if err != nil {
+		return fmt.Errorf("failed to list the operator groups of %s: %s: %w", namespace, stderr, err)
+	}
+	if strings.TrimSpace(stdout) != "" {
+		logging.Debugf("Using the existing operator group of %s", namespace)
+		return nil
+	}
+	operatorGroup := map[string]interface{}{
+		"apiVersion": "operators.coreos.com/v1",
+		"kind":       "OperatorGroup",
+		"metadata": map[string]string{
+			"name":      namespace,
+			"namespace": namespace,
+		},
+		"spec": map[string][]string{
+			"targetNamespaces": {namespace},
+		},
+	}
+	if err := applyJSON(sshRunner, ocConfig, operatorGroup, fmt.Sprintf("/tmp/operatorgroup-%s.json", namespace)); err != nil {
+		return fmt.Errorf("failed to create an operator group in %s: %w", namespace, err)
+	}
+	return nil
+}
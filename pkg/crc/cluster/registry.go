@@ -0,0 +1,146 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+const (
+	// ImageRegistryNodePort is the node port of the internal registry of OpenShift
+	ImageRegistryNodePort = 30500
+	// ImageRegistryClusterAddress is the address of the internal registry of OpenShift in the cluster
+	ImageRegistryClusterAddress = "image-registry.openshift-image-registry.svc:5000"
+
+	// LocalRegistryPort is the port of the registry deployed on MicroShift, on the host network of the node
+	LocalRegistryPort = 5000
+
+	imageRegistryNamespace   = "openshift-image-registry"
+	imageRegistryNodePortSvc = "crc-image-registry"
+
+	localRegistryNamespace = "crc-registry"
+	localRegistryImage     = "docker.io/library/registry:2"
+	localRegistryDataDir   = "/var/lib/crc-registry"
+)
+
+// ExposeImageRegistry makes the internal registry of OpenShift reachable on
+// ImageRegistryNodePort of the node
+func ExposeImageRegistry(sshRunner *ssh.Runner, ocConfig oc.Config) error {
+	stdout, stderr, err := ocConfig.RunOcCommand("get", "configs.imageregistry.operator.openshift.io/cluster", "-o", "jsonpath={.spec.managementState}")
+	if err != nil {
+		return fmt.Errorf("failed to get the configuration of the image registry: %s: %w", stderr, err)
+	}
+	if state := strings.TrimSpace(stdout); state == "Removed" {
+		return fmt.Errorf("the image registry of the cluster is disabled (managementState: %s)", state)
+	}
+	service := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata": map[string]string{
+			"name":      imageRegistryNodePortSvc,
+			"namespace": imageRegistryNamespace,
+		},
+		"spec": map[string]interface{}{
+			"type": "NodePort",
+			"selector": map[string]string{
+				"docker-registry": "default",
+			},
+			"ports": []map[string]interface{}{
+				{
+					"name":       "5000-tcp",
+					"port":       5000,
+					"targetPort": 5000,
+					"nodePort":   ImageRegistryNodePort,
+				},
+			},
+		},
+	}
+	if err := applyJSON(sshRunner, ocConfig, service, "/tmp/crc-image-registry.json"); err != nil {
+		return fmt.Errorf("failed to expose the image registry: %w", err)
+	}
+	return nil
+}
+
+// UnexposeImageRegistry removes the node port of the internal registry of OpenShift
+func UnexposeImageRegistry(ocConfig oc.Config) error {
+	if _, stderr, err := ocConfig.RunOcCommand("delete", "service", imageRegistryNodePortSvc, "-n", imageRegistryNamespace, "--ignore-not-found"); err != nil {
+		return fmt.Errorf("failed to remove the node port of the image registry: %s: %w", stderr, err)
+	}
+	return nil
+}
+
+// DeployLocalRegistry runs a registry listening on LocalRegistryPort of the
+// node, its images are stored on the disk of the instance
+func DeployLocalRegistry(sshRunner *ssh.Runner, ocConfig oc.Config) error {
+	namespace := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata": map[string]interface{}{
+			"name": localRegistryNamespace,
+			"labels": map[string]string{
+				"pod-security.kubernetes.io/enforce": "privileged",
+			},
+		},
+	}
+	if err := applyJSON(sshRunner, ocConfig, namespace, "/tmp/crc-registry-namespace.json"); err != nil {
+		return fmt.Errorf("failed to create namespace %s: %w", localRegistryNamespace, err)
+	}
+	labels := map[string]string{"app": "crc-registry"}
+	deployment := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]string{
+			"name":      "crc-registry",
+			"namespace": localRegistryNamespace,
+		},
+		"spec": map[string]interface{}{
+			"replicas": 1,
+			"strategy": map[string]string{"type": "Recreate"},
+			"selector": map[string]interface{}{"matchLabels": labels},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": labels},
+				"spec": map[string]interface{}{
+					"hostNetwork": true,
+					"containers": []map[string]interface{}{
+						{
+							"name":  "registry",
+							"image": localRegistryImage,
+							"env": []map[string]string{
+								{"name": "REGISTRY_HTTP_ADDR", "value": fmt.Sprintf(":%d", LocalRegistryPort)},
+								{"name": "REGISTRY_STORAGE_DELETE_ENABLED", "value": "true"},
+							},
+							"securityContext": map[string]bool{"privileged": true},
+							"volumeMounts": []map[string]string{
+								{"name": "data", "mountPath": "/var/lib/registry"},
+							},
+						},
+					},
+					"volumes": []map[string]interface{}{
+						{
+							"name": "data",
+							"hostPath": map[string]string{
+								"path": localRegistryDataDir,
+								"type": "DirectoryOrCreate",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := applyJSON(sshRunner, ocConfig, deployment, "/tmp/crc-registry-deployment.json"); err != nil {
+		return fmt.Errorf("failed to deploy the registry: %w", err)
+	}
+	return nil
+}
+
+// RemoveLocalRegistry removes the registry deployed by DeployLocalRegistry,
+// the images stay on the disk of the instance
+func RemoveLocalRegistry(ocConfig oc.Config) error {
+	if _, stderr, err := ocConfig.RunOcCommand("delete", "namespace", localRegistryNamespace, "--ignore-not-found"); err != nil {
+		return fmt.Errorf("failed to remove the registry: %s: %w", stderr, err)
+	}
+	return nil
+}
@@ -15,6 +15,12 @@ func RequiresRestartMsg(key string, _ interface{}) string {
 		"stop the CRC instance with 'crc stop' and restart it with 'crc start'.", key)
 }
 
+func RequiresDaemonRestartMsg(key string, _ interface{}) string {
+	return fmt.Sprintf("Changes to configuration property '%s' are only applied when the CRC daemon is started.\n"+
+		"If you already have a running CRC instance, then for this configuration change to take effect, "+
+		"stop the CRC instance with 'crc stop', restart the CRC daemon and start the instance with 'crc start'.", key)
+}
+
 func RequiresDeleteMsg(key string, _ interface{}) string {
 	return fmt.Sprintf("Changes to configuration property '%s' are only applied when the CRC instance is created.\n"+
 		"If you already have a running CRC instance, then for this configuration change to take effect, "+
@@ -47,6 +53,14 @@ func RequiresCleanupAndSetupMsg(key string, _ interface{}) string {
 		"Please run 'crc cleanup' followed by 'crc setup' for this configuration to take effect.", key)
 }
 
+func RequiresRestartWithPodmanTCPWarning(key string, value interface{}) string {
+	if cast.ToString(value) == "" {
+		return RequiresRestartMsg(key, value)
+	}
+	return fmt.Sprintf("WARNING: the podman socket of the instance will be available without authentication on %s, "+
+		"any process of the host which can connect to it gets root access to the instance.\n%s", value, RequiresRestartMsg(key, value))
+}
+
 func RequiresHTTPPortChangeWarning(key string, value interface{}) string {
 	return fmt.Sprintf("Changes to configuration property '%s' will break OpenShift HTTP routes.\n"+
 		"In order to access OpenShift applications through HTTP URLs "+
@@ -68,6 +68,14 @@ func (c *Config) AddSetting(name string, defValue interface{}, validationFn Vali
 	}
 }
 
+// Validate checks value with the validation function of the setting called key
+func (c *Config) Validate(key string, value interface{}) error {
+	if _, ok := c.settingsByName[key]; !ok {
+		return fmt.Errorf(configPropDoesntExistMsg, key)
+	}
+	return c.validate(key, value)
+}
+
 func (c *Config) validate(key string, value interface{}) error {
 	ok, expectedValue := c.settingsByName[key].validationFn(value)
 	if !ok {
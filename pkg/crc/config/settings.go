@@ -2,12 +2,18 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/crc-org/crc/v2/pkg/crc/version"
+	"github.com/spf13/cast"
 )
 
 const (
@@ -38,6 +44,43 @@ const (
 	EmergencyLogin           = "enable-emergency-login"
 	PersistentVolumeSize     = "persistent-volume-size"
 	EnableBundleQuayFallback = "enable-bundle-quay-fallback"
+	BundleMirror             = "bundle-mirror"
+	PortForwards             = "port-forwards"
+	LibvirtNetworkBridge     = "libvirt-network-bridge"
+	LibvirtNetworkSubnet     = "libvirt-network-subnet"
+	LibvirtNetworkGateway    = "libvirt-network-gateway"
+	LibvirtNetworkDHCPRange  = "libvirt-network-dhcp-range"
+	UserNetworkSubnet        = "user-network-subnet"
+	UserNetworkGatewayMAC    = "user-network-gateway-mac"
+	UserNetworkDNSRecords    = "user-network-dns-records"
+	AppsDomain               = "apps-domain"
+	IngressTLSCertFile       = "ingress-tls-cert-file"
+	IngressTLSKeyFile        = "ingress-tls-key-file"
+	APITLSCertFile           = "api-tls-cert-file"
+	APITLSKeyFile            = "api-tls-key-file"
+	TLSCAFile                = "tls-ca-file"
+	TrustClusterCA           = "trust-cluster-ca"
+	PodmanSocketMode         = "podman-socket-mode"
+	HostPodmanSocket         = "host-podman-socket"
+	PodmanTCPAddress         = "podman-tcp-address"
+	PostStartHooksDir        = "post-start-hooks-dir"
+	PostStartHookTimeout     = "post-start-hook-timeout"
+	EnableLocalRegistry      = "enable-local-registry"
+	LocalRegistryPort        = "local-registry-port"
+	EnableImageCache         = "enable-image-cache"
+	ImageCacheRegistries     = "image-cache-registries"
+	ImageCacheMaxSize        = "image-cache-max-size"
+)
+
+const defaultPostStartHookTimeout = 300
+
+const defaultImageCacheRegistries = "quay.io,registry.redhat.io,registry.access.redhat.com"
+
+const defaultImageCacheMaxSize = 50
+
+const (
+	PodmanSocketRootful  = "rootful"
+	PodmanSocketRootless = "rootless"
 )
 
 func RegisterSettings(cfg *Config) {
@@ -50,6 +93,24 @@ func RegisterSettings(cfg *Config) {
 		return ValidateBool(value)
 	}
 
+	validatePortForwards := func(value interface{}) (bool, string) {
+		mode := GetNetworkMode(cfg)
+		if mode != network.UserNetworkingMode && cast.ToString(value) != "" {
+			return false, fmt.Sprintf("%s can only be used with %s set to '%s'",
+				PortForwards, NetworkMode, network.UserNetworkingMode)
+		}
+		return network.ValidatePortForwards(value, reservedHostPorts(cfg))
+	}
+
+	validateEnableImageCache := func(value interface{}) (bool, string) {
+		mode := GetNetworkMode(cfg)
+		if mode != network.UserNetworkingMode && cast.ToBool(value) {
+			return false, fmt.Sprintf("%s can only be used with %s set to '%s'",
+				EnableImageCache, NetworkMode, network.UserNetworkingMode)
+		}
+		return ValidateBool(value)
+	}
+
 	validCPUs := func(value interface{}) (bool, string) {
 		return validateCPUs(value, GetPreset(cfg))
 	}
@@ -95,6 +156,26 @@ func RegisterSettings(cfg *Config) {
 
 	cfg.AddSetting(HostNetworkAccess, false, validateHostNetworkAccess, RequiresCleanupAndSetupMsg,
 		"Allow TCP/IP connections from the CRC VM to services running on the host (true/false, default: false)")
+	cfg.AddSetting(PortForwards, "", validatePortForwards, RequiresRestartMsg,
+		"Extra ports forwarded from the host to the CRC VM with user mode networking (string, comma-separated list such as '8080:30080,5353:53/udp')")
+	// user mode network configuration, applied by the daemon
+	cfg.AddSetting(UserNetworkSubnet, network.DefaultUserNetworkSubnet, network.ValidateUserNetworkSubnet, RequiresDaemonRestartMsg,
+		fmt.Sprintf("Subnet of the user mode network, the gateway uses its first address, the CRC VM the second one and the host the last one (string, default: '%s')", network.DefaultUserNetworkSubnet))
+	cfg.AddSetting(UserNetworkGatewayMAC, network.DefaultUserNetworkGatewayMAC, network.ValidateMACAddress, RequiresDaemonRestartMsg,
+		fmt.Sprintf("MAC address of the gateway of the user mode network (string, default: '%s')", network.DefaultUserNetworkGatewayMAC))
+	cfg.AddSetting(UserNetworkDNSRecords, "", network.ValidateDNSRecords, RequiresDaemonRestartMsg,
+		"Extra DNS records served to the CRC VM with user mode networking, the parent domain of each name is then only resolved by CRC "+
+			"(string, comma-separated list of 'name=IP' such as 'registry.corp.local=host,*.dev.test=10.0.0.5', 'host' is the address of the host)")
+	// libvirt network configuration, only used with the system network mode on Linux
+	cfg.AddSetting(LibvirtNetworkBridge, network.DefaultLibvirtBridge, network.ValidateLibvirtBridge, RequiresCRCSetup,
+		fmt.Sprintf("Name of the bridge of the libvirt network used with system networking on Linux (string, default: '%s')", network.DefaultLibvirtBridge))
+	cfg.AddSetting(LibvirtNetworkSubnet, network.DefaultLibvirtSubnet, network.ValidateLibvirtSubnet, RequiresCRCSetup,
+		fmt.Sprintf("Subnet of the libvirt network used with system networking on Linux (string, default: '%s')", network.DefaultLibvirtSubnet))
+	cfg.AddSetting(LibvirtNetworkGateway, "", network.ValidateLibvirtGateway, RequiresCRCSetup,
+		"Gateway address of the libvirt network used with system networking on Linux (string, default: first address of the subnet)")
+	cfg.AddSetting(LibvirtNetworkDHCPRange, "", network.ValidateLibvirtDHCPRange, RequiresCRCSetup,
+		"DHCP range of the libvirt network used with system networking on Linux, the instance gets the first address of the range "+
+			"(string, like '192.168.130.11-192.168.130.254', default: only the instance address, at offset 11 in the subnet)")
 	// Proxy Configuration
 	cfg.AddSetting(HTTPProxy, "", validateHTTPProxy, SuccessfullyApplied,
 		"HTTP proxy URL (string, like 'http://my-proxy.com:8443')")
@@ -123,9 +204,57 @@ func RegisterSettings(cfg *Config) {
 		fmt.Sprintf("HTTP port to use for OpenShift ingress/routes on the host (1024-65535, default: %d)", constants.OpenShiftIngressHTTPPort))
 	cfg.AddSetting(IngressHTTPSPort, constants.OpenShiftIngressHTTPSPort, validatePort, RequiresHTTPSPortChangeWarning,
 		fmt.Sprintf("HTTPS port to use for OpenShift ingress/routes on the host (1024-65535, default: %d)", constants.OpenShiftIngressHTTPSPort))
+	cfg.AddSetting(AppsDomain, "", validateAppsDomain, RequiresDaemonRestartMsg,
+		fmt.Sprintf("Domain of the OpenShift console, oauth server and new routes, they use a certificate signed by the CA in the instance directory. "+
+			"The base domain of the cluster cannot be changed (string, like 'apps.dev.example.com', default: '%s')", strings.TrimPrefix(constants.AppsDomain, ".")))
+	// user provided serving certificates, installed by 'crc start'
+	cfg.AddSetting(IngressTLSCertFile, Path(""), validatePath, RequiresRestartMsg,
+		"Path to the certificate served by the OpenShift router, it must be valid for the apps domain and signed by the CA of 'tls-ca-file'")
+	cfg.AddSetting(IngressTLSKeyFile, Path(""), validatePath, RequiresRestartMsg,
+		"Path to the private key of the certificate served by the OpenShift router")
+	cfg.AddSetting(APITLSCertFile, Path(""), validatePath, RequiresRestartMsg,
+		"Path to the certificate served by the OpenShift API server, it must be valid for its hostname and signed by the CA of 'tls-ca-file'")
+	cfg.AddSetting(APITLSKeyFile, Path(""), validatePath, RequiresRestartMsg,
+		"Path to the private key of the certificate served by the OpenShift API server")
+	cfg.AddSetting(TLSCAFile, Path(""), validatePath, RequiresRestartMsg,
+		"Path to the CA which signed the router and API server certificates, it's added to the kubeconfig")
+	cfg.AddSetting(TrustClusterCA, false, ValidateBool, RequiresCRCSetup,
+		"Sign the OpenShift router certificate with a CA of the instance limited to the cluster domains, 'crc setup' adds it to the system trust store. "+
+			"The CA is generated by 'crc start', run 'crc setup' again after it. Only supported on Linux (true/false, default: false)")
+
+	// podman socket of the instance forwarded to the host with the user network mode
+	cfg.AddSetting(PodmanSocketMode, PodmanSocketRootful, validatePodmanSocketMode, RequiresRestartMsg,
+		fmt.Sprintf("Podman socket of the instance forwarded to the host (%s/%s, default: %s)", PodmanSocketRootful, PodmanSocketRootless, PodmanSocketRootful))
+	cfg.AddSetting(HostPodmanSocket, "", validateHostPodmanSocket, RequiresRestartMsg,
+		"Unix socket or named pipe where the podman socket of the instance is available on the host (string, default: docker.sock in the instance directory, or '"+constants.DefaultPodmanNamedPipe+"' on Windows)")
+	cfg.AddSetting(PodmanTCPAddress, "", validatePodmanTCPAddress, RequiresRestartWithPodmanTCPWarning,
+		fmt.Sprintf("Loopback TCP address of the host where the podman socket of the instance is also available when %s is '%s', "+
+			"the access is not authenticated (string, like '127.0.0.1:2375', default: disabled)", NetworkMode, network.UserNetworkingMode))
+
+	// manifests and executables run at the end of 'crc start'
+	cfg.AddSetting(PostStartHooksDir, Path(""), validatePath, SuccessfullyApplied,
+		fmt.Sprintf("Directory of the *.yaml manifests and the executables run at the end of 'crc start' (string, default: '%s')", constants.PostStartHooksDir))
+	cfg.AddSetting(PostStartHookTimeout, defaultPostStartHookTimeout, validatePostStartHookTimeout, SuccessfullyApplied,
+		fmt.Sprintf("Timeout in seconds of each post-start hook (integer, default: %d)", defaultPostStartHookTimeout))
+
+	// registry of the cluster reachable from the host, see 'crc registry enable'
+	cfg.AddSetting(EnableLocalRegistry, false, ValidateBool, RequiresRestartMsg,
+		"Make the registry of the cluster reachable from the host, set by 'crc registry enable' (true/false, default: false)")
+	cfg.AddSetting(LocalRegistryPort, constants.LocalRegistryHostPort, validatePort, RequiresRestartMsg,
+		fmt.Sprintf("Host port of the registry of the cluster with the user network mode (integer, default: %d)", constants.LocalRegistryHostPort))
+
+	// pull-through cache of the daemon used as a mirror by the instance
+	cfg.AddSetting(EnableImageCache, false, validateEnableImageCache, RequiresDaemonRestartMsg,
+		fmt.Sprintf("Cache the images pulled by the instance in '%s', the cache is kept after 'crc delete', only supported with the user network mode (true/false, default: false)", constants.ImageCacheDir))
+	cfg.AddSetting(ImageCacheRegistries, defaultImageCacheRegistries, validateImageCacheRegistries, RequiresDaemonRestartMsg,
+		fmt.Sprintf("Comma separated list of the registries whose images are cached (string, default: '%s')", defaultImageCacheRegistries))
+	cfg.AddSetting(ImageCacheMaxSize, defaultImageCacheMaxSize, validateImageCacheMaxSize, RequiresDaemonRestartMsg,
+		fmt.Sprintf("Maximum size in GiB of the image cache, the least recently used layers are removed above it (integer, default: %d)", defaultImageCacheMaxSize))
 
 	cfg.AddSetting(EnableBundleQuayFallback, false, ValidateBool, SuccessfullyApplied,
 		"If bundle download from the default location fails, fallback to quay.io (true/false, default: false)")
+	cfg.AddSetting(BundleMirror, "", validateBundleMirror, SuccessfullyApplied,
+		"Location of a mirror of the default bundles, http or https URL or local directory (string, like 'https://mirror.example.com/crc' or '/mnt/crc-mirror')")
 
 	if err := cfg.RegisterNotifier(Preset, presetChanged); err != nil {
 		logging.Debugf("Failed to register notifier for Preset: %v", err)
@@ -163,6 +292,136 @@ func GetNetworkMode(config Storage) network.Mode {
 	return network.ParseMode(config.Get(NetworkMode).AsString())
 }
 
+// reservedHostPorts returns the host ports of the forwards set up by crc
+// when using the user mode network
+func reservedHostPorts(config Storage) map[uint]string {
+	reserved := map[uint]string{
+		constants.VsockSSHPort:                "the SSH forward",
+		constants.OpenShiftAPIPort:            "the API server forward",
+		config.Get(IngressHTTPPort).AsUInt():  "the ingress HTTP forward",
+		config.Get(IngressHTTPSPort).AsUInt(): "the ingress HTTPS forward",
+	}
+	if _, port, err := net.SplitHostPort(config.Get(PodmanTCPAddress).AsString()); err == nil {
+		if p, err := strconv.ParseUint(port, 10, 16); err == nil {
+			reserved[uint(p)] = "the podman TCP forward"
+		}
+	}
+	if config.Get(EnableLocalRegistry).AsBool() {
+		reserved[config.Get(LocalRegistryPort).AsUInt()] = "the local registry forward"
+	}
+	return reserved
+}
+
+// GetPodmanSocket returns the path of the podman socket of the instance which is forwarded to the host
+func GetPodmanSocket(config Storage) string {
+	if config.Get(PodmanSocketMode).AsString() == PodmanSocketRootless {
+		return constants.RootlessPodmanSocket
+	}
+	return constants.RootfulPodmanSocket
+}
+
+// GetHostPodmanSocket returns the unix socket, or the named pipe on Windows,
+// where the podman socket of the instance called name is available on the host
+func GetHostPodmanSocket(config Storage, name string) string {
+	if socket := config.Get(HostPodmanSocket).AsString(); socket != "" {
+		return socket
+	}
+	if runtime.GOOS == "windows" {
+		return constants.DefaultPodmanNamedPipe
+	}
+	return constants.GetHostDockerSocketPath(name)
+}
+
+// GetPostStartHooksDir returns the directory of the post-start hooks
+func GetPostStartHooksDir(config Storage) string {
+	if dir := config.Get(PostStartHooksDir).AsString(); dir != "" {
+		return dir
+	}
+	return constants.PostStartHooksDir
+}
+
+// GetPortForwards returns the extra port forwards stored in the configuration
+func GetPortForwards(config Storage) []network.PortForward {
+	portForwards, err := network.ParsePortForwards(config.Get(PortForwards).AsString())
+	if err != nil {
+		logging.Errorf("invalid %s value: %v", PortForwards, err)
+		return nil
+	}
+	return portForwards
+}
+
+// GetUserNetwork returns the user mode network described by the configuration
+func GetUserNetwork(config Storage) (*network.UserNetwork, error) {
+	userNetwork, err := network.ParseUserNetwork(
+		config.Get(UserNetworkSubnet).AsString(),
+		config.Get(UserNetworkGatewayMAC).AsString(),
+		config.Get(UserNetworkDNSRecords).AsString(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user network configuration: %w", err)
+	}
+	return userNetwork, nil
+}
+
+// userNetworkSettings configure the user mode network of the daemon, which is
+// shared by all the instances
+var userNetworkSettings = []string{UserNetworkSubnet, UserNetworkGatewayMAC, UserNetworkDNSRecords}
+
+// imageCacheSettings configure the image cache of the daemon, which is shared
+// by all the instances
+var imageCacheSettings = []string{EnableImageCache, ImageCacheRegistries, ImageCacheMaxSize}
+
+// ValidateInstanceSetting returns an error when key cannot be set in the
+// configuration of the instance called name. The user mode network and image
+// cache settings are only read from the configuration of the default instance.
+func ValidateInstanceSetting(name, key string) error {
+	switch {
+	case name == constants.DefaultName:
+		return nil
+	case slices.Contains(userNetworkSettings, key):
+		return fmt.Errorf("'%s' can only be set for the '%s' instance, the user mode network is shared by all the instances", key, constants.DefaultName)
+	case slices.Contains(imageCacheSettings, key):
+		return fmt.Errorf("'%s' can only be set for the '%s' instance, the image cache is shared by all the instances", key, constants.DefaultName)
+	}
+	return nil
+}
+
+// LoadDefaultInstanceConfig returns the configuration of the default
+// instance, which describes what the daemon shares with all the instances
+func LoadDefaultInstanceConfig() (Storage, error) {
+	storage, err := NewViperStorage(constants.ConfigPath, constants.CrcEnvPrefix)
+	if err != nil {
+		return nil, err
+	}
+	cfg := New(storage, NewEmptyInMemorySecretStorage())
+	RegisterSettings(cfg)
+	return cfg, nil
+}
+
+// GetSharedUserNetwork returns the user mode network of the daemon, it is
+// described by the configuration of the default instance
+func GetSharedUserNetwork() (*network.UserNetwork, error) {
+	cfg, err := LoadDefaultInstanceConfig()
+	if err != nil {
+		return nil, err
+	}
+	return GetUserNetwork(cfg)
+}
+
+// GetLibvirtNetwork returns the libvirt network described by the configuration
+func GetLibvirtNetwork(config Storage) (*network.LibvirtNetwork, error) {
+	libvirtNetwork, err := network.ParseLibvirtNetwork(
+		config.Get(LibvirtNetworkBridge).AsString(),
+		config.Get(LibvirtNetworkSubnet).AsString(),
+		config.Get(LibvirtNetworkGateway).AsString(),
+		config.Get(LibvirtNetworkDHCPRange).AsString(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid libvirt network configuration: %w", err)
+	}
+	return libvirtNetwork, nil
+}
+
 func revalidateSettingsValue(cfg *Config, key string) error {
 	if err := cfg.validate(key, cfg.Get(key).Value); err != nil {
 		logging.Debugf("'%s' value is invalid: %v", key, err)
@@ -193,3 +452,15 @@ func BundleHelpMsg(cfg *Config) string {
 	return fmt.Sprintf("Bundle path/URI - absolute or local path, http, https or docker URI (string, like 'https://foo.com/%s', 'docker://quay.io/myorg/%s:%s' default '%s' )",
 		constants.GetDefaultBundle(GetPreset(cfg)), constants.GetDefaultBundle(GetPreset(cfg)), version.GetCRCVersion(), defaultBundlePath(cfg))
 }
+
+// GetImageCacheRegistries returns the registries whose images are cached when
+// enable-image-cache is true
+func GetImageCacheRegistries(config Storage) []string {
+	var registries []string
+	for _, registry := range strings.Split(config.Get(ImageCacheRegistries).AsString(), ",") {
+		if registry = strings.TrimSpace(registry); registry != "" {
+			registries = append(registries, registry)
+		}
+	}
+	return registries
+}
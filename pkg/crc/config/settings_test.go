@@ -377,3 +377,36 @@ func TestSetProvidedValuesOverrideDefaultValuesInConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateInstanceSetting(t *testing.T) {
+	assert.NoError(t, ValidateInstanceSetting(constants.DefaultName, UserNetworkSubnet))
+	assert.NoError(t, ValidateInstanceSetting("other", CPUs))
+	for _, key := range []string{UserNetworkSubnet, UserNetworkGatewayMAC, UserNetworkDNSRecords} {
+		assert.EqualError(t, ValidateInstanceSetting("other", key),
+			fmt.Sprintf("'%s' can only be set for the 'crc' instance, the user mode network is shared by all the instances", key))
+	}
+	for _, key := range []string{EnableImageCache, ImageCacheRegistries, ImageCacheMaxSize} {
+		assert.EqualError(t, ValidateInstanceSetting("other", key),
+			fmt.Sprintf("'%s' can only be set for the 'crc' instance, the image cache is shared by all the instances", key))
+	}
+}
+
+func TestPortForwardsReservedPorts(t *testing.T) {
+	cfg, err := newInMemoryConfig()
+	require.NoError(t, err)
+
+	_, err = cfg.Set(PortForwards, "2375:2375")
+	assert.NoError(t, err)
+
+	_, err = cfg.Set(PodmanTCPAddress, "127.0.0.1:2376")
+	require.NoError(t, err)
+	_, err = cfg.Set(PortForwards, "2376:2376")
+	assert.ErrorContains(t, err, "host port 2376/tcp is already used by the podman TCP forward")
+	_, err = cfg.Set(PortForwards, "6443:6443")
+	assert.ErrorContains(t, err, "host port 6443/tcp is already used by the API server forward")
+
+	_, err = cfg.Set(EnableLocalRegistry, true)
+	require.NoError(t, err)
+	_, err = cfg.Set(PortForwards, "5000:5000")
+	assert.ErrorContains(t, err, "host port 5000/tcp is already used by the local registry forward")
+}
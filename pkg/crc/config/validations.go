@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -92,6 +94,23 @@ func validateBundlePath(value interface{}, preset crcpreset.Preset) (bool, strin
 	return true, ""
 }
 
+// validateBundleMirror checks if the provided bundle mirror location is valid
+func validateBundleMirror(value interface{}) (bool, string) {
+	if err := validation.ValidateBundleMirror(cast.ToString(value)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func validateAppsDomain(value interface{}) (bool, string) {
+	if domain := cast.ToString(value); domain != "" {
+		if err := validation.ValidateAppsDomain(domain); err != nil {
+			return false, err.Error()
+		}
+	}
+	return true, ""
+}
+
 // validateIP checks if provided IP is valid
 func validateIPAddress(value interface{}) (bool, string) {
 	if err := validation.ValidateIPAddress(cast.ToString(value)); err != nil {
@@ -164,3 +183,79 @@ func validatePort(value interface{}) (bool, string) {
 	}
 	return true, ""
 }
+
+func validatePodmanSocketMode(value interface{}) (bool, string) {
+	switch cast.ToString(value) {
+	case PodmanSocketRootful, PodmanSocketRootless:
+		return true, ""
+	default:
+		return false, fmt.Sprintf("must be %s or %s", PodmanSocketRootful, PodmanSocketRootless)
+	}
+}
+
+// validateHostPodmanSocket checks the socket is a named pipe on Windows, or
+// an absolute path in an existing directory on other platforms
+func validateHostPodmanSocket(value interface{}) (bool, string) {
+	socket := cast.ToString(value)
+	if socket == "" {
+		return true, ""
+	}
+	if runtime.GOOS == "windows" {
+		if !strings.HasPrefix(socket, `\\.\pipe\`) {
+			return false, fmt.Sprintf("'%s' is not a named pipe, it must start with '\\\\.\\pipe\\'", socket)
+		}
+		return true, ""
+	}
+	if !filepath.IsAbs(socket) {
+		return false, fmt.Sprintf("'%s' is not an absolute path", socket)
+	}
+	if err := validation.ValidatePath(filepath.Dir(socket)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func validatePodmanTCPAddress(value interface{}) (bool, string) {
+	address := cast.ToString(value)
+	if address == "" {
+		return true, ""
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return false, fmt.Sprintf("invalid address '%s', expected 'IP:port'", address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false, fmt.Sprintf("invalid IP address '%s'", host)
+	}
+	// the access to the podman socket is not authenticated
+	if !ip.IsLoopback() {
+		return false, fmt.Sprintf("'%s' is not a loopback address, the podman socket can only be exposed on localhost", host)
+	}
+	return validatePort(port)
+}
+
+func validatePostStartHookTimeout(value interface{}) (bool, string) {
+	timeout, err := cast.ToUintE(value)
+	if err != nil || timeout == 0 {
+		return false, "requires a number of seconds greater than 0"
+	}
+	return true, ""
+}
+
+func validateImageCacheMaxSize(value interface{}) (bool, string) {
+	size, err := cast.ToUintE(value)
+	if err != nil || size == 0 {
+		return false, "requires a number of GiB greater than 0"
+	}
+	return true, ""
+}
+
+func validateImageCacheRegistries(value interface{}) (bool, string) {
+	for _, registry := range strings.Split(cast.ToString(value), ",") {
+		if err := validation.ValidateRegistry(strings.TrimSpace(registry)); err != nil {
+			return false, err.Error()
+		}
+	}
+	return true, ""
+}
@@ -158,3 +158,122 @@ func TestValidatePersistentVolumeSize(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAppsDomain(t *testing.T) {
+	tests := []struct {
+		name                     string
+		appsDomain               string
+		expectedValidationResult bool
+	}{
+		{"empty domain", "", true},
+		{"valid domain", "apps.dev.example.com", true},
+		{"single label", "apps", false},
+		{"upper case", "Apps.example.com", false},
+		{"empty label", "apps..example.com", false},
+		{"wildcard", "*.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualValidationResult, _ := validateAppsDomain(tt.appsDomain)
+			if actualValidationResult != tt.expectedValidationResult {
+				t.Errorf("validateAppsDomain(%s) : got %v, want %v", tt.appsDomain, actualValidationResult, tt.expectedValidationResult)
+			}
+		})
+	}
+}
+
+func TestValidatePodmanTCPAddress(t *testing.T) {
+	tests := []struct {
+		name                     string
+		address                  string
+		expectedValidationResult bool
+	}{
+		{"empty address", "", true},
+		{"valid address", "127.0.0.1:2375", true},
+		{"IPv6 loopback", "[::1]:2375", true},
+		{"any address", "0.0.0.0:2375", false},
+		{"non loopback address", "192.168.1.10:2375", false},
+		{"missing port", "127.0.0.1", false},
+		{"host name", "localhost:2375", false},
+		{"privileged port", "127.0.0.1:375", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualValidationResult, _ := validatePodmanTCPAddress(tt.address)
+			if actualValidationResult != tt.expectedValidationResult {
+				t.Errorf("validatePodmanTCPAddress(%s) : got %v, want %v", tt.address, actualValidationResult, tt.expectedValidationResult)
+			}
+		})
+	}
+}
+
+func TestValidatePostStartHookTimeout(t *testing.T) {
+	tests := []struct {
+		name                     string
+		timeout                  interface{}
+		expectedValidationResult bool
+	}{
+		{"valid timeout", 300, true},
+		{"string timeout", "60", true},
+		{"zero timeout", 0, false},
+		{"negative timeout", -1, false},
+		{"not a number", "five minutes", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualValidationResult, _ := validatePostStartHookTimeout(tt.timeout)
+			if actualValidationResult != tt.expectedValidationResult {
+				t.Errorf("validatePostStartHookTimeout(%v) : got %v, want %v", tt.timeout, actualValidationResult, tt.expectedValidationResult)
+			}
+		})
+	}
+}
+
+func TestValidateImageCacheRegistries(t *testing.T) {
+	tests := []struct {
+		name                     string
+		registries               string
+		expectedValidationResult bool
+	}{
+		{"default registries", "quay.io,registry.redhat.io,registry.access.redhat.com", true},
+		{"registry with port", "quay.io, registry.example.com:5000", true},
+		{"not a domain", "localhost", false},
+		{"invalid port", "registry.example.com:registry", false},
+		{"repository", "quay.io/crcont", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualValidationResult, _ := validateImageCacheRegistries(tt.registries)
+			if actualValidationResult != tt.expectedValidationResult {
+				t.Errorf("validateImageCacheRegistries(%s) : got %v, want %v", tt.registries, actualValidationResult, tt.expectedValidationResult)
+			}
+		})
+	}
+}
+
+func TestValidateImageCacheMaxSize(t *testing.T) {
+	tests := []struct {
+		name                     string
+		size                     interface{}
+		expectedValidationResult bool
+	}{
+		{"valid size", 50, true},
+		{"string size", "20", true},
+		{"zero size", 0, false},
+		{"negative size", -1, false},
+		{"not a number", "fifty", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualValidationResult, _ := validateImageCacheMaxSize(tt.size)
+			if actualValidationResult != tt.expectedValidationResult {
+				t.Errorf("validateImageCacheMaxSize(%v) : got %v, want %v", tt.size, actualValidationResult, tt.expectedValidationResult)
+			}
+		})
+	}
+}
@@ -105,6 +105,13 @@ func (c *ViperStorage) Unset(key string) error {
 	return atomicWrite(bin, c.configFile)
 }
 
+// SetConfigFile changes the file in which the configuration is stored
+func (c *ViperStorage) SetConfigFile(configFile string) {
+	c.storeLock.Lock()
+	defer c.storeLock.Unlock()
+	c.configFile = configFile
+}
+
 // BindFlagset binds a flagset to their respective config properties
 func (c *ViperStorage) BindFlagSet(flagSet *pflag.FlagSet) error {
 	c.storeLock.Lock()
@@ -40,7 +40,6 @@ const (
 	RootlessPodmanSocket      = "/run/user/1000/podman/podman.sock"
 	RootfulPodmanSocket       = "/run/podman/podman.sock"
 
-	VSockGateway    = "192.168.127.1"
 	VsockSSHPort    = 2222
 	LocalIP         = "127.0.0.1"
 	VsockMacAddress = "5a:94:ef:e4:0c:ee"
@@ -54,6 +53,13 @@ const (
 
 	OpenShiftIngressHTTPPort  = 80
 	OpenShiftIngressHTTPSPort = 443
+	OpenShiftAPIPort          = 6443
+
+	// LocalRegistryHostPort is the default host port of the registry enabled by 'crc registry enable'
+	LocalRegistryHostPort = 5000
+
+	// ImageCachePort is the port of the image cache of the daemon on host.crc.testing
+	ImageCachePort = 5100
 
 	BackgroundLauncherExecutable = "crc-background-launcher.exe"
 
@@ -149,8 +155,9 @@ var (
 	MachineInstanceDir     = filepath.Join(MachineBaseDir, "machines")
 	SocketBaseDir          = filepath.Join(CrcBaseDir, "sockets")
 	DaemonSocketPath       = filepath.Join(SocketBaseDir, "crc.sock")
-	KubeconfigFilePath     = filepath.Join(MachineInstanceDir, DefaultName, "kubeconfig")
-	PasswdFilePath         = filepath.Join(MachineInstanceDir, DefaultName, "passwd")
+	InstanceConfigDir      = filepath.Join(CrcBaseDir, "instances")
+	PostStartHooksDir      = filepath.Join(CrcBaseDir, "hooks")
+	ImageCacheDir          = filepath.Join(CrcBaseDir, "image-cache")
 )
 
 func GetDefaultBundlePath(preset crcpreset.Preset) string {
@@ -235,29 +242,96 @@ func EnsureAdminHelperLogFileExists() error {
 	return nil
 }
 
-func GetPublicKeyPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "id_ed25519.pub")
+// GetInstanceDir returns the directory holding the state of the instance called name
+func GetInstanceDir(name string) string {
+	return filepath.Join(MachineInstanceDir, name)
+}
+
+// GetInstanceConfigPath returns the path of the configuration file for the instance called name.
+// The default instance keeps using ~/.crc/crc.json for backward compatibility.
+func GetInstanceConfigPath(name string) string {
+	if name == DefaultName {
+		return ConfigPath
+	}
+	return filepath.Join(InstanceConfigDir, name, ConfigFile)
+}
+
+func GetKubeconfigFilePath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "kubeconfig")
+}
+
+// GetRegistriesConfDropInPath returns the containers-registries.conf(5) drop-in
+// of the user allowing insecure access to the registry of the instance
+func GetRegistriesConfDropInPath(name string) string {
+	return filepath.Join(GetHomeDir(), ".config", "containers", "registries.conf.d", fmt.Sprintf("%s-registry.conf", name))
+}
+
+// GetPostStartHookLogPath returns the log file of the last run of the post-start hook called hook
+func GetPostStartHookLogPath(name, hook string) string {
+	return filepath.Join(GetInstanceDir(name), "hooks", fmt.Sprintf("%s.log", hook))
+}
+
+func GetPasswdFilePath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "passwd")
+}
+
+func GetPublicKeyPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "id_ed25519.pub")
 }
 
-func GetPrivateKeyPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "id_ed25519")
+func GetPrivateKeyPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "id_ed25519")
 }
 
-func GetHostDockerSocketPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "docker.sock")
+// GetKnownHostsPath returns the file pinning the SSH host key of the VM
+func GetKnownHostsPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "known_hosts")
+}
+
+func GetHostDockerSocketPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "docker.sock")
 }
 
 // For backward compatibility to v 2.40.0
-func GetECDSAPrivateKeyPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "id_ecdsa")
+func GetECDSAPrivateKeyPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "id_ecdsa")
+}
+
+func GetKubeAdminPasswordPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "kubeadmin-password")
+}
+
+func GetDeveloperPasswordPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "developer-password")
+}
+
+// GetAppsDomainPath returns the path of the file recording the custom apps domain configured in the cluster
+func GetAppsDomainPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "apps-domain")
+}
+
+// GetIngressCACertPath returns the path of the CA signing the router certificate for a custom apps domain
+func GetIngressCACertPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "ingress-ca.crt")
+}
+
+func GetIngressCAKeyPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "ingress-ca.key")
+}
+
+// GetIngressCertPath returns the path of the certificate installed by crc for the router
+func GetIngressCertPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "ingress.crt")
 }
 
-func GetKubeAdminPasswordPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "kubeadmin-password")
+// GetAPICertPath returns the path of the user provided certificate served by the API server
+func GetAPICertPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "api.crt")
 }
 
-func GetDeveloperPasswordPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "developer-password")
+// GetCustomCACertPath returns the path of the CA of the user provided serving certificates
+func GetCustomCACertPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "custom-ca.crt")
 }
 
 func GetWin32BackgroundLauncherDownloadURL() string {
@@ -7,6 +7,7 @@ import (
 
 	networkclient "github.com/containers/gvisor-tap-vsock/pkg/client"
 	"github.com/crc-org/crc/v2/pkg/crc/api/client"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	crcversion "github.com/crc-org/crc/v2/pkg/crc/version"
 	pkgerrors "github.com/pkg/errors"
 )
@@ -20,6 +21,16 @@ type Client struct {
 }
 
 func New() *Client {
+	return NewForInstance(constants.DefaultName)
+}
+
+// NewForInstance returns a client for the API and events of the instance called name.
+// The network API is shared by all the instances.
+func NewForInstance(name string) *Client {
+	base := "http://unix"
+	if name != constants.DefaultName {
+		base = fmt.Sprintf("http://unix/instances/%s", name)
+	}
 	return &Client{
 		NetworkClient: networkclient.New(&http.Client{
 			Transport: transport(),
@@ -27,8 +38,8 @@ func New() *Client {
 		APIClient: client.New(&http.Client{
 			Timeout:   30 * time.Second,
 			Transport: transport(),
-		}, "http://unix/api"),
-		SSEClient: client.NewSSEClient(transport()),
+		}, base+"/api"),
+		SSEClient: client.NewSSEClient(transport(), base+"/events"),
 	}
 }
 
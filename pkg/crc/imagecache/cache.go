@@ -0,0 +1,374 @@
+// Package imagecache implements a pull-through cache of container registries.
+// It serves the pull part of the registry API v2 for the repositories of a
+// set of upstream registries, the repository 'quay.io/org/image' of the cache
+// being 'org/image' of quay.io.
+package imagecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+)
+
+var (
+	repositoryRegexp = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+	tagRegexp        = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	sha256Regexp     = regexp.MustCompile(`^sha256:([a-f0-9]{64})$`)
+)
+
+// Cache is an http.Handler serving the images of the upstream registries,
+// blobs and manifests referenced by digest are kept in its directory
+type Cache struct {
+	dir        string
+	maxSize    int64
+	registries map[string]bool
+	pullSecret func() (string, error)
+	client     *http.Client
+	// scheme of the upstream registries, only changed by the tests
+	scheme string
+
+	lock   sync.Mutex
+	auths  map[string]string
+	tokens map[string]token
+
+	pruneLock sync.Mutex
+}
+
+// New returns a cache storing its content in dir for the registries, such
+// as 'quay.io'. The least recently used blobs are removed when they take more
+// than maxSize bytes. pullSecret returns the credentials used for the
+// upstream registries, in the format of the pull secret of OpenShift.
+func New(dir string, registries []string, maxSize int64, pullSecret func() (string, error)) *Cache {
+	cache := &Cache{
+		dir:        dir,
+		maxSize:    maxSize,
+		registries: map[string]bool{},
+		pullSecret: pullSecret,
+		client:     &http.Client{},
+		scheme:     "https",
+		tokens:     map[string]token{},
+	}
+	for _, registry := range registries {
+		cache.registries[registry] = true
+	}
+	return cache
+}
+
+func (c *Cache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "the image cache is read-only")
+		return
+	}
+	if r.URL.Path == "/v2/" || r.URL.Path == "/v2" {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+		return
+	}
+
+	registry, path, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/")
+	if !c.registries[registry] {
+		writeError(w, http.StatusNotFound, "NAME_UNKNOWN", fmt.Sprintf("registry %s is not cached", registry))
+		return
+	}
+	if i := strings.LastIndex(path, "/manifests/"); i > 0 {
+		c.serveManifest(w, r, registry, path[:i], path[i+len("/manifests/"):])
+		return
+	}
+	if i := strings.LastIndex(path, "/blobs/"); i > 0 {
+		c.serveBlob(w, r, registry, path[:i], path[i+len("/blobs/"):])
+		return
+	}
+	writeError(w, http.StatusNotFound, "UNSUPPORTED", "unsupported request")
+}
+
+func (c *Cache) serveManifest(w http.ResponseWriter, r *http.Request, registry, repository, reference string) {
+	if !repositoryRegexp.MatchString(repository) {
+		writeError(w, http.StatusBadRequest, "NAME_INVALID", "invalid repository name")
+		return
+	}
+	digest, byDigest := sha256Hex(reference)
+	if !byDigest && !tagRegexp.MatchString(reference) {
+		writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", "invalid tag or digest")
+		return
+	}
+	if byDigest {
+		if data, mediaType, err := c.readManifest(digest); err == nil {
+			writeManifest(w, reference, mediaType, data)
+			return
+		}
+	}
+
+	resp, err := c.fetch(r, http.MethodGet, registry, repository, "manifests", reference)
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		// tags are resolved upstream, the last known digest is only used when it's unreachable
+		if !byDigest {
+			if data, mediaType, digest, err := c.readTag(registry, repository, reference); err == nil {
+				logging.Debugf("Using the cached manifest of %s/%s:%s", registry, repository, reference)
+				writeManifest(w, "sha256:"+digest, mediaType, data)
+				return
+			}
+		}
+		writeError(w, http.StatusBadGateway, "UNAVAILABLE", fmt.Sprintf("cannot reach %s", registry))
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		relay(w, resp)
+		return
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "UNAVAILABLE", err.Error())
+		return
+	}
+	sum := sha256.Sum256(data)
+	actualDigest := hex.EncodeToString(sum[:])
+	if byDigest && actualDigest != digest {
+		writeError(w, http.StatusBadGateway, "DIGEST_INVALID", "the digest of the upstream manifest doesn't match")
+		return
+	}
+	mediaType := resp.Header.Get("Content-Type")
+	if err := c.writeManifest(actualDigest, mediaType, data); err != nil {
+		logging.Warnf("Cannot cache the manifest of %s/%s@sha256:%s: %v", registry, repository, actualDigest, err)
+	} else if !byDigest {
+		if err := c.writeTag(registry, repository, reference, actualDigest); err != nil {
+			logging.Warnf("Cannot cache the tag %s/%s:%s: %v", registry, repository, reference, err)
+		}
+	}
+	writeManifest(w, "sha256:"+actualDigest, mediaType, data)
+}
+
+func (c *Cache) serveBlob(w http.ResponseWriter, r *http.Request, registry, repository, reference string) {
+	if !repositoryRegexp.MatchString(repository) {
+		writeError(w, http.StatusBadRequest, "NAME_INVALID", "invalid repository name")
+		return
+	}
+	digest, ok := sha256Hex(reference)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", "invalid digest")
+		return
+	}
+	if blob, err := os.Open(c.blobPath(digest)); err == nil {
+		defer blob.Close()
+		// the modification time orders the blobs removed by prune
+		now := time.Now()
+		if err := os.Chtimes(c.blobPath(digest), now, now); err != nil {
+			logging.Debugf("Cannot update the modification time of blob %s: %v", reference, err)
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Docker-Content-Digest", reference)
+		http.ServeContent(w, r, "", time.Time{}, blob)
+		return
+	}
+
+	resp, err := c.fetch(r, r.Method, registry, repository, "blobs", reference)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "UNAVAILABLE", fmt.Sprintf("cannot reach %s", registry))
+		return
+	}
+	defer resp.Body.Close()
+	if r.Method == http.MethodHead || resp.StatusCode != http.StatusOK {
+		relay(w, resp)
+		return
+	}
+
+	tmp, err := c.createTemp()
+	if err != nil {
+		logging.Warnf("Cannot cache blob %s: %v", reference, err)
+		relay(w, resp)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	copyHeaders(w, resp)
+	w.Header().Set("Docker-Content-Digest", reference)
+	w.WriteHeader(resp.StatusCode)
+	hash := sha256.New()
+	if _, err := io.Copy(w, io.TeeReader(resp.Body, io.MultiWriter(tmp, hash))); err != nil {
+		logging.Debugf("Failed to download blob %s: %v", reference, err)
+		return
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != digest {
+		logging.Warnf("Not caching blob %s of %s/%s, its content doesn't match its digest", reference, registry, repository)
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.blobPath(digest)), 0700); err != nil {
+		logging.Warnf("Cannot cache blob %s: %v", reference, err)
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.blobPath(digest)); err != nil {
+		logging.Warnf("Cannot cache blob %s: %v", reference, err)
+		return
+	}
+	if err := c.prune(); err != nil {
+		logging.Warnf("Cannot prune the image cache: %v", err)
+	}
+}
+
+// prune removes the least recently used blobs until they take at most
+// maxSize bytes, the manifests are small and always kept
+func (c *Cache) prune() error {
+	c.pruneLock.Lock()
+	defer c.pruneLock.Unlock()
+
+	entries, err := os.ReadDir(filepath.Join(c.dir, "blobs", "sha256"))
+	if err != nil {
+		return err
+	}
+	var blobs []os.FileInfo
+	var size int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			// removed in the meantime
+			continue
+		}
+		blobs = append(blobs, info)
+		size += info.Size()
+	}
+	if size <= c.maxSize {
+		return nil
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].ModTime().Before(blobs[j].ModTime())
+	})
+	for _, blob := range blobs {
+		if size <= c.maxSize {
+			break
+		}
+		logging.Debugf("Removing blob sha256:%s from the image cache", blob.Name())
+		if err := os.Remove(c.blobPath(blob.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		size -= blob.Size()
+	}
+	return nil
+}
+
+func (c *Cache) blobPath(digest string) string {
+	return filepath.Join(c.dir, "blobs", "sha256", digest)
+}
+
+func (c *Cache) manifestPath(digest string) string {
+	return filepath.Join(c.dir, "manifests", "sha256", digest)
+}
+
+func (c *Cache) tagPath(registry, repository, tag string) string {
+	return filepath.Join(c.dir, "tags", registry, filepath.FromSlash(repository), tag)
+}
+
+func (c *Cache) createTemp() (*os.File, error) {
+	dir := filepath.Join(c.dir, "tmp")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return os.CreateTemp(dir, "blob-")
+}
+
+func (c *Cache) readManifest(digest string) ([]byte, string, error) {
+	data, err := os.ReadFile(c.manifestPath(digest))
+	if err != nil {
+		return nil, "", err
+	}
+	mediaType, err := os.ReadFile(c.manifestPath(digest) + ".type")
+	if err != nil {
+		return nil, "", err
+	}
+	return data, string(mediaType), nil
+}
+
+func (c *Cache) writeManifest(digest, mediaType string, data []byte) error {
+	path := c.manifestPath(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// the media type is written first, a manifest file is only read with it
+	if err := os.WriteFile(path+".type", []byte(mediaType), 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func (c *Cache) readTag(registry, repository, tag string) ([]byte, string, string, error) {
+	digest, err := os.ReadFile(c.tagPath(registry, repository, tag))
+	if err != nil {
+		return nil, "", "", err
+	}
+	data, mediaType, err := c.readManifest(string(digest))
+	return data, mediaType, string(digest), err
+}
+
+func (c *Cache) writeTag(registry, repository, tag, digest string) error {
+	path := c.tagPath(registry, repository, tag)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(digest), 0600)
+}
+
+// sha256Hex returns the hex part of a sha256 digest, other digests aren't cached
+func sha256Hex(reference string) (string, bool) {
+	matches := sha256Regexp.FindStringSubmatch(reference)
+	if matches == nil {
+		return "", false
+	}
+	return matches[1], true
+}
+
+func writeManifest(w http.ResponseWriter, digest, mediaType string, data []byte) {
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	_, _ = w.Write(data)
+}
+
+var relayedHeaders = []string{"Content-Type", "Content-Length", "Docker-Content-Digest"}
+
+func copyHeaders(w http.ResponseWriter, resp *http.Response) {
+	for _, header := range relayedHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+}
+
+// relay writes the upstream response as is
+func relay(w http.ResponseWriter, resp *http.Response) {
+	copyHeaders(w, resp)
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil && !errors.Is(err, http.ErrBodyNotAllowed) {
+		logging.Debugf("Failed to relay the response of %s: %v", resp.Request.URL, err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{
+			{"code": code, "message": message},
+		},
+	})
+}
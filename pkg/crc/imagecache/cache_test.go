@@ -0,0 +1,191 @@
+package imagecache
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	manifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	testToken         = "secret-token"
+)
+
+type upstream struct {
+	server   *httptest.Server
+	requests atomic.Int32
+	blobs    map[string]string
+	manifest string
+}
+
+func digestOf(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// newUpstream starts a registry requiring a bearer token obtained with the credentials user:password
+func newUpstream(t *testing.T) *upstream {
+	u := &upstream{
+		blobs:    map[string]string{},
+		manifest: `{"schemaVersion":2}`,
+	}
+	layer := "layer content"
+	u.blobs[digestOf(layer)] = layer
+	u.blobs[digestOf("expected content")] = "tampered content"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		if username != "user" || password != "password" || r.URL.Query().Get("scope") != "repository:org/image:pull" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": testToken})
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		u.requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, u.server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/v2/org/image/manifests/latest" || r.URL.Path == "/v2/org/image/manifests/"+digestOf(u.manifest):
+			w.Header().Set("Content-Type", manifestMediaType)
+			_, _ = w.Write([]byte(u.manifest))
+		case strings.HasPrefix(r.URL.Path, "/v2/org/image/blobs/"):
+			blob, ok := u.blobs[strings.TrimPrefix(r.URL.Path, "/v2/org/image/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(blob))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	u.server = httptest.NewServer(mux)
+	t.Cleanup(u.server.Close)
+	return u
+}
+
+func (u *upstream) registry() string {
+	return strings.TrimPrefix(u.server.URL, "http://")
+}
+
+func newTestCache(t *testing.T, u *upstream) *Cache {
+	pullSecret := fmt.Sprintf(`{"auths":{"%s":{"auth":"%s"}}}`, u.registry(), base64.StdEncoding.EncodeToString([]byte("user:password")))
+	cache := New(t.TempDir(), []string{u.registry()}, 1<<20, func() (string, error) {
+		return pullSecret, nil
+	})
+	cache.scheme = "http"
+	return cache
+}
+
+func get(t *testing.T, cache *Cache, path string) *http.Response {
+	recorder := httptest.NewRecorder()
+	cache.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder.Result()
+}
+
+func body(t *testing.T, resp *http.Response) string {
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCacheBlob(t *testing.T) {
+	u := newUpstream(t)
+	cache := newTestCache(t, u)
+	path := fmt.Sprintf("/v2/%s/org/image/blobs/%s", u.registry(), digestOf("layer content"))
+
+	resp := get(t, cache, path)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "layer content", body(t, resp))
+	requests := u.requests.Load()
+
+	resp = get(t, cache, path)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "layer content", body(t, resp))
+	assert.Equal(t, digestOf("layer content"), resp.Header.Get("Docker-Content-Digest"))
+	assert.Equal(t, requests, u.requests.Load())
+}
+
+func TestCacheBlobDigestMismatch(t *testing.T) {
+	u := newUpstream(t)
+	cache := newTestCache(t, u)
+	path := fmt.Sprintf("/v2/%s/org/image/blobs/%s", u.registry(), digestOf("expected content"))
+
+	_ = get(t, cache, path)
+	requests := u.requests.Load()
+	_ = get(t, cache, path)
+	assert.Greater(t, u.requests.Load(), requests)
+}
+
+func TestCacheManifestByTag(t *testing.T) {
+	u := newUpstream(t)
+	cache := newTestCache(t, u)
+	path := fmt.Sprintf("/v2/%s/org/image/manifests/latest", u.registry())
+
+	resp := get(t, cache, path)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, u.manifest, body(t, resp))
+	assert.Equal(t, manifestMediaType, resp.Header.Get("Content-Type"))
+	assert.Equal(t, digestOf(u.manifest), resp.Header.Get("Docker-Content-Digest"))
+
+	// the manifest is stored by digest, and the tag is used when the upstream registry is unreachable
+	u.server.Close()
+	resp = get(t, cache, fmt.Sprintf("/v2/%s/org/image/manifests/%s", u.registry(), digestOf(u.manifest)))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, u.manifest, body(t, resp))
+	resp = get(t, cache, path)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, u.manifest, body(t, resp))
+}
+
+func TestCacheRejectedRequests(t *testing.T) {
+	u := newUpstream(t)
+	cache := newTestCache(t, u)
+
+	assert.Equal(t, http.StatusOK, get(t, cache, "/v2/").StatusCode)
+	assert.Equal(t, http.StatusNotFound, get(t, cache, "/v2/example.com/org/image/manifests/latest").StatusCode)
+	assert.Equal(t, http.StatusBadRequest, get(t, cache, fmt.Sprintf("/v2/%s/org/../image/manifests/latest", u.registry())).StatusCode)
+	assert.Equal(t, http.StatusNotFound, get(t, cache, fmt.Sprintf("/v2/%s/org/image/blobs/%s", u.registry(), digestOf("missing"))).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, get(t, cache, fmt.Sprintf("/v2/%s/org/image/blobs/sha512:1234", u.registry())).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, get(t, cache, fmt.Sprintf("/v2/%s/org/image/blobs/..%%2F..%%2Ftoken", u.registry())).StatusCode)
+
+	recorder := httptest.NewRecorder()
+	cache.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, fmt.Sprintf("/v2/%s/org/image/manifests/latest", u.registry()), nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestCachePrune(t *testing.T) {
+	u := newUpstream(t)
+	u.blobs[digestOf("other layer")] = "other layer"
+	cache := newTestCache(t, u)
+	cache.maxSize = int64(len("layer content") + len("other layer") - 1)
+	first := fmt.Sprintf("/v2/%s/org/image/blobs/%s", u.registry(), digestOf("layer content"))
+	second := fmt.Sprintf("/v2/%s/org/image/blobs/%s", u.registry(), digestOf("other layer"))
+
+	assert.Equal(t, http.StatusOK, get(t, cache, first).StatusCode)
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(cache.blobPath(strings.TrimPrefix(digestOf("layer content"), "sha256:")), old, old))
+	assert.Equal(t, http.StatusOK, get(t, cache, second).StatusCode)
+
+	// the least recently used blob is removed
+	assert.NoFileExists(t, cache.blobPath(strings.TrimPrefix(digestOf("layer content"), "sha256:")))
+	assert.FileExists(t, cache.blobPath(strings.TrimPrefix(digestOf("other layer"), "sha256:")))
+}
@@ -0,0 +1,184 @@
+package imagecache
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+)
+
+const defaultTokenLifetime = 60 * time.Second
+
+var challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+type token struct {
+	value   string
+	expires time.Time
+}
+
+// upstreamHost returns the host serving the registry API of registry
+func upstreamHost(registry string) string {
+	if registry == "docker.io" {
+		return "registry-1.docker.io"
+	}
+	return registry
+}
+
+// fetch sends the request for the manifest or the blob of repository to the
+// upstream registry, it authenticates when the registry requests it
+func (c *Cache) fetch(r *http.Request, method, registry, repository, kind, reference string) (*http.Response, error) {
+	upstreamURL := fmt.Sprintf("%s://%s/v2/%s/%s/%s", c.scheme, upstreamHost(registry), repository, kind, reference)
+	newRequest := func(authorization string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(r.Context(), method, upstreamURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept := r.Header.Values("Accept"); len(accept) > 0 {
+			req.Header["Accept"] = accept
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		return req, nil
+	}
+
+	req, err := newRequest(c.cachedToken(registry, repository))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	authorization, err := c.authorize(r, registry, repository, challenge)
+	if err != nil {
+		return nil, err
+	}
+	req, err = newRequest(authorization)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.Do(req)
+}
+
+// authorize answers the challenge of the upstream registry with the
+// credentials of the pull secret, or anonymously
+func (c *Cache) authorize(r *http.Request, registry, repository, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	credentials := c.credentials(registry)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if credentials == "" {
+			return "", fmt.Errorf("%s requires credentials", registry)
+		}
+		return "Basic " + credentials, nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported authentication scheme '%s' of %s", scheme, registry)
+	}
+
+	values := map[string]string{}
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(params, -1) {
+		values[match[1]] = match[2]
+	}
+	if values["realm"] == "" {
+		return "", fmt.Errorf("missing realm in the authentication challenge of %s", registry)
+	}
+	if values["scope"] == "" {
+		values["scope"] = fmt.Sprintf("repository:%s:pull", repository)
+	}
+	query := url.Values{}
+	query.Set("scope", values["scope"])
+	if values["service"] != "" {
+		query.Set("service", values["service"])
+	}
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, values["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if credentials != "" {
+		req.Header.Set("Authorization", "Basic "+credentials)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get a token for %s/%s: %s", registry, repository, resp.Status)
+	}
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("invalid token response of %s: %w", registry, err)
+	}
+	value := tokenResponse.Token
+	if value == "" {
+		value = tokenResponse.AccessToken
+	}
+	lifetime := defaultTokenLifetime
+	if tokenResponse.ExpiresIn > 0 {
+		lifetime = time.Duration(tokenResponse.ExpiresIn) * time.Second
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.tokens[registry+"/"+repository] = token{
+		value: value,
+		// renewed a bit before it expires
+		expires: time.Now().Add(lifetime * 9 / 10),
+	}
+	return "Bearer " + value, nil
+}
+
+func (c *Cache) cachedToken(registry, repository string) string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	token, ok := c.tokens[registry+"/"+repository]
+	if !ok || time.Now().After(token.expires) {
+		return ""
+	}
+	return "Bearer " + token.value
+}
+
+// credentials returns the base64 encoded 'user:password' of registry in the
+// pull secret, or an empty string for anonymous access
+func (c *Cache) credentials(registry string) string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.auths == nil {
+		pullSecret, err := c.pullSecret()
+		if err != nil {
+			logging.Debugf("Using anonymous access to the upstream registries: %v", err)
+			return ""
+		}
+		var config struct {
+			Auths map[string]struct {
+				Auth string `json:"auth"`
+			} `json:"auths"`
+		}
+		if err := json.Unmarshal([]byte(pullSecret), &config); err != nil {
+			logging.Warnf("Invalid pull secret: %v", err)
+			return ""
+		}
+		c.auths = map[string]string{}
+		for name, auth := range config.Auths {
+			c.auths[name] = auth.Auth
+		}
+	}
+	if registry == "docker.io" && c.auths[registry] == "" {
+		return c.auths["https://index.docker.io/v1/"]
+	}
+	return c.auths[registry]
+}
@@ -0,0 +1,214 @@
+package machine
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
+)
+
+// the router certificate is renewed by 'crc start' when it expires in less than this
+const ingressCertRenewalDelay = 30 * 24 * time.Hour
+
+// customAppsDomain returns the apps domain configured in the cluster of the
+// instance called name by a previous start, or an empty string if the cluster
+// uses the apps domain of the bundle
+func customAppsDomain(name string) string {
+	data, err := os.ReadFile(constants.GetAppsDomainPath(name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// appsDomain returns the apps domain used by the console and oauth routes of the instance called name
+func appsDomain(name string, bundleInfo *bundle.CrcBundleInfo) string {
+	if domain := customAppsDomain(name); domain != "" {
+		return domain
+	}
+	return bundleInfo.ClusterInfo.AppsDomain
+}
+
+// validateAppsDomain rejects the apps domains containing the domain of the
+// cluster: only the apps domain can be changed, the base domain of the
+// cluster and the API server hostname are the ones of the bundle
+func validateAppsDomain(appsDomain string, bundleInfo *bundle.CrcBundleInfo) error {
+	if appsDomain == "" {
+		return nil
+	}
+	clusterDomain := fmt.Sprintf("%s.%s", bundleInfo.ClusterInfo.ClusterName, bundleInfo.ClusterInfo.BaseDomain)
+	if appsDomain == clusterDomain || strings.HasSuffix(clusterDomain, "."+appsDomain) {
+		return fmt.Errorf("the apps domain %s cannot contain the domain %s of the cluster, changing the base domain of the cluster is not supported", appsDomain, clusterDomain)
+	}
+	return nil
+}
+
+// ingressConfigured returns true if a previous start changed the apps domain or the router certificate
+func ingressConfigured(name string) bool {
+	_, err := os.Stat(constants.GetIngressCertPath(name))
+	return err == nil || customAppsDomain(name) != ""
+}
+
+// configureIngress moves the console, downloads and oauth routes under
+// appsDomain and makes the router serve userCert. When userCert is nil and
+// appsDomain is not the one of the bundle, or instanceCA is true, the router
+// serves a certificate signed by a CA kept in the instance directory. An empty
+// appsDomain, a nil userCert and a false instanceCA restore the configuration
+// of the bundle.
+func configureIngress(ctx context.Context, name string, ocConfig oc.Config, sshRunner *crcssh.Runner, appsDomain string, userCert *servingCertificate, instanceCA bool, bundleInfo *bundle.CrcBundleInfo) error {
+	if appsDomain == bundleInfo.ClusterInfo.AppsDomain {
+		appsDomain = ""
+	}
+	currentAppsDomain := customAppsDomain(name)
+	installedCertPEM, err := os.ReadFile(constants.GetIngressCertPath(name))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if appsDomain != currentAppsDomain {
+		if appsDomain == "" {
+			logging.Infof("Restoring the %s apps domain...", bundleInfo.ClusterInfo.AppsDomain)
+			if err := cluster.ResetAppsDomain(ctx, ocConfig); err != nil {
+				return err
+			}
+			if err := os.Remove(constants.GetAppsDomainPath(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		} else {
+			logging.Infof("Configuring the %s apps domain...", appsDomain)
+			if err := cluster.SetAppsDomain(ctx, ocConfig, appsDomain); err != nil {
+				return err
+			}
+			if err := os.WriteFile(constants.GetAppsDomainPath(name), []byte(appsDomain), 0600); err != nil {
+				return err
+			}
+		}
+	}
+
+	var certPEM, keyPEM []byte
+	switch {
+	case userCert != nil:
+		if bytes.Equal(installedCertPEM, userCert.certPEM) {
+			return nil
+		}
+		certPEM, keyPEM = userCert.certPEM, userCert.keyPEM
+	case appsDomain != "" || instanceCA:
+		// the routes which are not moved, such as the canary one, stay in the domain of the bundle
+		domains := []string{bundleInfo.ClusterInfo.AppsDomain}
+		if appsDomain != "" {
+			domains = []string{appsDomain, bundleInfo.ClusterInfo.AppsDomain}
+		}
+		if generatedIngressCertificateValid(name, installedCertPEM, domains) {
+			return nil
+		}
+		caKey, caCert, err := ingressCA(name, domains)
+		if err != nil {
+			return fmt.Errorf("cannot get the router certificate authority: %w", err)
+		}
+		var dnsNames []string
+		for _, domain := range domains {
+			dnsNames = append(dnsNames, fmt.Sprintf("*.%s", domain))
+		}
+		keyPEM, certPEM, err = crctls.GenerateServingCertificate(caKey, caCert, dnsNames...)
+		if err != nil {
+			return fmt.Errorf("cannot generate the router certificate: %w", err)
+		}
+	default:
+		if installedCertPEM == nil {
+			return nil
+		}
+		logging.Info("Restoring the router certificate of the cluster...")
+		if err := cluster.ResetDefaultIngressCertificate(ctx, ocConfig); err != nil {
+			return err
+		}
+		return os.Remove(constants.GetIngressCertPath(name))
+	}
+
+	logging.Info("Updating the router certificate...")
+	if err := cluster.SetDefaultIngressCertificate(ctx, ocConfig, sshRunner, certPEM, keyPEM); err != nil {
+		return err
+	}
+	return os.WriteFile(constants.GetIngressCertPath(name), certPEM, 0600)
+}
+
+// generatedIngressCertificateValid returns true if certPEM was generated by
+// configureIngress for domains and does not expire soon
+func generatedIngressCertificateValid(name string, certPEM []byte, domains []string) bool {
+	if certPEM == nil {
+		return false
+	}
+	caPEM, err := os.ReadFile(constants.GetIngressCACertPath(name))
+	if err != nil {
+		return false
+	}
+	caCert, err := crctls.PemToCert(caPEM)
+	if err != nil || !slices.Equal(caCert.PermittedDNSDomains, domains) {
+		return false
+	}
+	if ok, err := crctls.VerifyCertificateAgainstRootCA(string(caPEM), string(certPEM), x509.ExtKeyUsageServerAuth); err != nil || !ok {
+		return false
+	}
+	cert, err := crctls.PemToCert(certPEM)
+	if err != nil {
+		return false
+	}
+	for _, domain := range domains {
+		if err := cert.VerifyHostname(fmt.Sprintf("console-openshift-console.%s", domain)); err != nil {
+			return false
+		}
+	}
+	return time.Now().Add(ingressCertRenewalDelay).Before(cert.NotAfter)
+}
+
+// ingressCA loads the CA signing the router certificate for domains. It's
+// generated on first use, and generated again when the domains change as its
+// name constraints only allow it to sign certificates for these domains.
+func ingressCA(name string, domains []string) (*rsa.PrivateKey, *x509.Certificate, error) {
+	keyPEM, err := os.ReadFile(constants.GetIngressCAKeyPath(name))
+	if err == nil {
+		certPEM, err := os.ReadFile(constants.GetIngressCACertPath(name))
+		if err != nil {
+			return nil, nil, err
+		}
+		key, err := crctls.PemToPrivateKey(keyPEM)
+		if err != nil {
+			return nil, nil, err
+		}
+		cert, err := crctls.PemToCert(certPEM)
+		if err != nil {
+			return nil, nil, err
+		}
+		if slices.Equal(cert.PermittedDNSDomains, domains) {
+			return key, cert, nil
+		}
+		logging.Debugf("Generating a new router CA for %s", strings.Join(domains, ", "))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, err
+	}
+
+	key, cert, err := crctls.GetSelfSignedIngressCA(domains)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(constants.GetIngressCAKeyPath(name), crctls.PrivateKeyToPem(key), 0600); err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(constants.GetIngressCACertPath(name), crctls.CertToPem(cert), 0644); err != nil {
+		return nil, nil, err
+	}
+	return key, cert, nil
+}
@@ -0,0 +1,24 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAppsDomain(t *testing.T) {
+	bundleInfo := &bundle.CrcBundleInfo{
+		ClusterInfo: bundle.ClusterInfo{
+			ClusterName: "crc",
+			BaseDomain:  "testing",
+			AppsDomain:  "apps-crc.testing",
+		},
+	}
+	assert.NoError(t, validateAppsDomain("", bundleInfo))
+	assert.NoError(t, validateAppsDomain("apps.dev.example.com", bundleInfo))
+	assert.NoError(t, validateAppsDomain("apps.crc.testing", bundleInfo))
+	assert.EqualError(t, validateAppsDomain("crc.testing", bundleInfo),
+		"the apps domain crc.testing cannot contain the domain crc.testing of the cluster, changing the base domain of the cluster is not supported")
+	assert.Error(t, validateAppsDomain("testing", bundleInfo))
+}
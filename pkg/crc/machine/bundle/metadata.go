@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -179,6 +178,12 @@ func (bundle *CrcBundleInfo) GetSSHKeyPath() string {
 	return bundle.resolvePath(bundle.ClusterInfo.SSHPrivateKeyFile)
 }
 
+// GetKnownHostsPath returns the file recording the SSH host key of the bundle,
+// which VMs have until their own host keys are generated
+func (bundle *CrcBundleInfo) GetKnownHostsPath() string {
+	return bundle.resolvePath("known_hosts")
+}
+
 func (bundle *CrcBundleInfo) GetBundleBuildTime() (time.Time, error) {
 	return time.Parse(time.RFC3339, strings.TrimSpace(bundle.BuildInfo.BuildTime))
 }
@@ -195,6 +200,25 @@ func (bundle *CrcBundleInfo) GetBundleNameWithoutExtension() string {
 	return GetBundleNameWithoutExtension(bundle.GetBundleName())
 }
 
+// GetDiskUsage returns the size in bytes of the extracted bundle in the cache directory
+func (bundle *CrcBundleInfo) GetDiskUsage() (int64, error) {
+	var size int64
+	err := filepath.WalkDir(bundle.cachedPath, func(_ string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
 func (bundle *CrcBundleInfo) GetBundleType() crcPreset.Preset {
 	bundleType := strings.TrimSuffix(bundle.Type, "_custom")
 	if bundleType == "snc" {
@@ -326,33 +350,32 @@ func GetBundleInfoFromName(bundleName string) (*FilenameInfo, error) {
 	return &filenameInfo, nil
 }
 
-func getBundleDownloadInfo(preset crcPreset.Preset) (*download.RemoteFile, error) {
-	sha256sum, err := getDefaultBundleVerifiedHash(preset)
+func getBundleDownloadInfo(preset crcPreset.Preset, mirror string) (*download.RemoteFile, error) {
+	sha256sum, err := getDefaultBundleVerifiedHash(preset, mirror)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get verified hash for default bundle: %w", err)
 	}
-	downloadInfo := download.NewRemoteFile(constants.GetDefaultBundleDownloadURL(preset), sha256sum)
+	downloadInfo := download.NewRemoteFile(defaultBundleLocation(mirror, preset, constants.GetDefaultBundle(preset)), sha256sum)
 	return downloadInfo, nil
 }
 
-// getDefaultBundleVerifiedHash downloads the sha256sum.txt.sig file from mirror.openshift.com
+// getDefaultBundleVerifiedHash downloads the sha256sum.txt.sig file from mirror.openshift.com,
+// or from the bundle mirror when one is configured,
 // then verifies it is signed by redhat release key, if signature is valid it returns the hash
 // for the default bundle of preset from the file
-func getDefaultBundleVerifiedHash(preset crcPreset.Preset) (string, error) {
-	return getVerifiedHash(constants.GetDefaultBundleSignedHashURL(preset), constants.GetDefaultBundle(preset))
+func getDefaultBundleVerifiedHash(preset crcPreset.Preset, mirror string) (string, error) {
+	return getVerifiedHash(defaultBundleLocation(mirror, preset, signedHashFilename), constants.GetDefaultBundle(preset))
 }
 
 func getVerifiedHash(url string, file string) (string, error) {
-	res, err := download.InMemory(url)
-	if err != nil {
-		return "", err
-	}
-	defer res.Close()
-	signedHashes, err := io.ReadAll(res)
+	signedHashes, err := readLocation(url)
 	if err != nil {
 		return "", err
 	}
+	return verifiedHash(signedHashes, file)
+}
 
+func verifiedHash(signedHashes []byte, file string) (string, error) {
 	verifiedHashes, err := gpg.GetVerifiedClearsignedMsgV3(constants.RedHatReleaseKey, string(signedHashes))
 	if err != nil {
 		return "", fmt.Errorf("Invalid signature: %w", err)
@@ -370,15 +393,20 @@ func getVerifiedHash(url string, file string) (string, error) {
 	return "", fmt.Errorf("%s hash is missing or shasums are malformed", file)
 }
 
-func downloadDefault(ctx context.Context, preset crcPreset.Preset) (string, error) {
-	downloadInfo, err := getBundleDownloadInfo(preset)
+func downloadDefault(ctx context.Context, preset crcPreset.Preset, mirror string) (string, error) {
+	if mirror != "" && !isHTTPLocation(mirror) {
+		return useLocalMirror(mirror, preset)
+	}
+	downloadInfo, err := getBundleDownloadInfo(preset, mirror)
 	if err != nil {
 		return "", err
 	}
 	return downloadInfo.Download(ctx, constants.GetDefaultBundlePath(preset), 0664)
 }
 
-func Download(ctx context.Context, preset crcPreset.Preset, bundleURI string, enableBundleQuayFallback bool) (string, error) {
+// Download fetches the bundle at bundleURI and returns its local path. The default
+// bundles are fetched from mirror instead of the default location when it is not empty.
+func Download(ctx context.Context, preset crcPreset.Preset, bundleURI string, mirror string, enableBundleQuayFallback bool) (string, error) {
 	// If we are asked to download
 	// ~/.crc/cache/crc_podman_libvirt_4.1.1.crcbundle, this means we want
 	// are downloading the default bundle for this release. This uses a
@@ -387,7 +415,7 @@ func Download(ctx context.Context, preset crcPreset.Preset, bundleURI string, en
 	if bundleURI == constants.GetDefaultBundlePath(preset) {
 		switch preset {
 		case crcPreset.OpenShift, crcPreset.Microshift:
-			downloadedBundlePath, err := downloadDefault(ctx, preset)
+			downloadedBundlePath, err := downloadDefault(ctx, preset, mirror)
 			if err != nil && enableBundleQuayFallback {
 				logging.Info("Unable to download bundle from mirror, falling back to quay")
 				return image.PullBundle(ctx, constants.GetDefaultBundleImageRegistry(preset))
@@ -420,15 +448,18 @@ type ReleaseInfo struct {
 	Links   map[string]string `json:"links"`
 }
 
-func FetchLatestReleaseInfo() (*ReleaseInfo, error) {
+func releaseInfoLocation(mirror string) string {
 	const releaseInfoLink = "https://developers.redhat.com/content-gateway/rest/mirror/pub/openshift-v4/clients/crc/latest/release-info.json"
-	response, err := download.InMemory(releaseInfoLink)
-	if err != nil {
-		return nil, err
+	if mirror == "" {
+		return releaseInfoLink
 	}
-	defer response.Close()
+	return mirrorLocation(mirror, releaseInfoFilename)
+}
 
-	releaseMetaData, err := io.ReadAll(response)
+// FetchLatestReleaseInfo returns the information about the latest CRC release,
+// it is read from mirror when it is not empty
+func FetchLatestReleaseInfo(mirror string) (*ReleaseInfo, error) {
+	releaseMetaData, err := readLocation(releaseInfoLocation(mirror))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,154 @@
+package bundle
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/version"
+	"github.com/crc-org/crc/v2/pkg/download"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+)
+
+// A bundle mirror is either an http(s) URL or a local directory with the same
+// layout as the default download location:
+//
+//	<mirror>/release-info.json
+//	<mirror>/<preset>/<bundle version>/<bundle>.crcbundle
+//	<mirror>/<preset>/<bundle version>/sha256sum.txt.sig
+const (
+	releaseInfoFilename = "release-info.json"
+	signedHashFilename  = "sha256sum.txt.sig"
+)
+
+// MirroredPresets are the presets whose default bundle can be fetched from a mirror,
+// their sha256sum is signed with the Red Hat release key
+var MirroredPresets = []crcPreset.Preset{crcPreset.OpenShift, crcPreset.Microshift}
+
+func isHTTPLocation(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+func localMirrorPath(mirror string) string {
+	return strings.TrimPrefix(mirror, "file://")
+}
+
+// mirrorLocation returns the location of the file with the elem path in mirror
+func mirrorLocation(mirror string, elem ...string) string {
+	if isHTTPLocation(mirror) {
+		return strings.TrimSuffix(mirror, "/") + "/" + path.Join(elem...)
+	}
+	return filepath.Join(append([]string{localMirrorPath(mirror)}, elem...)...)
+}
+
+// defaultBundleLocation returns the location of file next to the default bundle
+// for preset, either in mirror or at the default download location
+func defaultBundleLocation(mirror string, preset crcPreset.Preset, file string) string {
+	if mirror == "" {
+		return fmt.Sprintf(constants.DefaultBundleURLBase, preset.String(), version.GetBundleVersion(preset), file)
+	}
+	return mirrorLocation(mirror, preset.String(), version.GetBundleVersion(preset), file)
+}
+
+// openLocation returns the content of an http(s) URL or of a local file
+func openLocation(location string) (io.ReadCloser, error) {
+	if isHTTPLocation(location) || strings.HasPrefix(location, "file://") {
+		return download.InMemory(location)
+	}
+	return os.Open(location)
+}
+
+func readLocation(location string) ([]byte, error) {
+	res, err := openLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+	return io.ReadAll(res)
+}
+
+// useLocalMirror checks the default bundle for preset in the local mirror against its signed
+// sha256sum and returns its path, it is used from the mirror without copying it to the cache
+func useLocalMirror(mirror string, preset crcPreset.Preset) (string, error) {
+	sha256sum, err := getVerifiedHash(defaultBundleLocation(mirror, preset, signedHashFilename), constants.GetDefaultBundle(preset))
+	if err != nil {
+		return "", fmt.Errorf("unable to get verified hash for default bundle: %w", err)
+	}
+	bundlePath := defaultBundleLocation(mirror, preset, constants.GetDefaultBundle(preset))
+	logging.Infof("Verifying %s", bundlePath)
+	actual, err := CalculateBundleSha256Sum(bundlePath)
+	if err != nil {
+		return "", err
+	}
+	if actual != sha256sum {
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", bundlePath, sha256sum, actual)
+	}
+	return bundlePath, nil
+}
+
+// Mirror copies the default bundles found in the cache directory to the destDir
+// directory, which can then be used as a bundle mirror. The signed sha256sums and
+// the release information are fetched from source, which is a bundle mirror or
+// the default download location when empty.
+// It returns the paths of the bundles which were copied.
+func Mirror(source string, destDir string) ([]string, error) {
+	var mirrored []string
+	for _, preset := range MirroredPresets {
+		bundlePath := constants.GetDefaultBundlePath(preset)
+		if _, err := os.Stat(bundlePath); err != nil {
+			logging.Debugf("Skipping %s bundle: %v", preset, err)
+			continue
+		}
+		if err := mirrorDefaultBundle(source, destDir, preset); err != nil {
+			return mirrored, err
+		}
+		mirrored = append(mirrored, defaultBundleLocation(destDir, preset, constants.GetDefaultBundle(preset)))
+	}
+	if len(mirrored) == 0 {
+		return nil, fmt.Errorf("no default bundle found in %s, run 'crc setup' to download it", constants.MachineCacheDir)
+	}
+
+	releaseInfo, err := readLocation(releaseInfoLocation(source))
+	if err != nil {
+		logging.Warnf("Cannot fetch the release information, update checks will fail when using the mirror: %v", err)
+		return mirrored, nil
+	}
+	return mirrored, os.WriteFile(mirrorLocation(destDir, releaseInfoFilename), releaseInfo, 0644) // #nosec G306
+}
+
+func mirrorDefaultBundle(source string, destDir string, preset crcPreset.Preset) error {
+	bundleName := constants.GetDefaultBundle(preset)
+	signedHashes, err := readLocation(defaultBundleLocation(source, preset, signedHashFilename))
+	if err != nil {
+		return err
+	}
+	sha256sum, err := verifiedHash(signedHashes, bundleName)
+	if err != nil {
+		return err
+	}
+	bundlePath := constants.GetDefaultBundlePath(preset)
+	logging.Infof("Verifying %s", bundlePath)
+	actual, err := CalculateBundleSha256Sum(bundlePath)
+	if err != nil {
+		return err
+	}
+	if actual != sha256sum {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", bundlePath, sha256sum, actual)
+	}
+
+	dir := filepath.Dir(defaultBundleLocation(destDir, preset, bundleName))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	logging.Infof("Copying %s to %s", bundlePath, dir)
+	if err := crcos.CopyFile(bundlePath, filepath.Join(dir, bundleName)); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, signedHashFilename), signedHashes, 0644) // #nosec G306
+}
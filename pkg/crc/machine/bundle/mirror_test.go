@@ -0,0 +1,48 @@
+package bundle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultBundleLocation(t *testing.T) {
+	bundleVersion := version.GetBundleVersion(preset.OpenShift)
+	assert.Equal(t, "https://mirror.openshift.com/pub/openshift-v4/clients/crc/bundles/openshift/"+bundleVersion+"/sha256sum.txt.sig",
+		defaultBundleLocation("", preset.OpenShift, signedHashFilename))
+	assert.Equal(t, "http://mirror.example.com/crc/openshift/"+bundleVersion+"/sha256sum.txt.sig",
+		defaultBundleLocation("http://mirror.example.com/crc/", preset.OpenShift, signedHashFilename))
+	assert.Equal(t, filepath.Join("/mnt", "mirror", "microshift", version.GetBundleVersion(preset.Microshift), "sha256sum.txt.sig"),
+		defaultBundleLocation("file:///mnt/mirror", preset.Microshift, signedHashFilename))
+}
+
+func TestVerifiedHashFromLocalMirror(t *testing.T) {
+	sha256sum, err := getVerifiedHash(filepath.Join("testdata", "sha256sum_correct_4.13.0.txt.sig"), "crc_libvirt_4.13.0_amd64.crcbundle")
+	require.NoError(t, err)
+	require.Equal(t, "6aad57019aaab95b670378f569b3f4a16398da0358dd1057996453a8d6d92212", sha256sum)
+}
+
+const releaseInfo = `{"version":{"crcVersion":"2.50.0","gitSha":"abcdef","openshiftVersion":"4.18.2"},"links":{"linux":"https://example.com/crc-linux-amd64.tar.xz"}}`
+
+func TestFetchLatestReleaseInfoFromMirror(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, releaseInfoFilename), []byte(releaseInfo), 0600))
+
+	info, err := FetchLatestReleaseInfo(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "2.50.0", info.Version.CrcVersion.String())
+	assert.Equal(t, "https://example.com/crc-linux-amd64.tar.xz", info.Links["linux"])
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+	info, err = FetchLatestReleaseInfo(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "4.18.2", info.Version.OpenshiftVersion)
+}
@@ -173,6 +173,25 @@ func (repo *Repository) List() ([]CrcBundleInfo, error) {
 	return ret, nil
 }
 
+// Remove deletes the extracted bundle and its archive from the cache directory
+func (repo *Repository) Remove(bundleName string) error {
+	if bundleName != filepath.Base(bundleName) || bundleName == "." || bundleName == ".." {
+		return fmt.Errorf("invalid bundle name: %s", bundleName)
+	}
+	bundleDir := filepath.Join(repo.CacheDir, GetBundleNameWithoutExtension(bundleName))
+	if _, err := os.Stat(bundleDir); err != nil {
+		return errors.Wrapf(err, "could not find cached bundle in %s", bundleDir)
+	}
+	if err := os.RemoveAll(bundleDir); err != nil {
+		return err
+	}
+	archive := filepath.Join(repo.CacheDir, GetBundleNameWithExtension(bundleName))
+	if err := os.Remove(archive); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 func (repo *Repository) CalculateBundleSha256Sum(bundlePath string) (string, error) {
 	return sha256sum(bundlePath)
 }
@@ -204,3 +223,7 @@ func Extract(ctx context.Context, path string) (*CrcBundleInfo, error) {
 func List() ([]CrcBundleInfo, error) {
 	return defaultRepo.List()
 }
+
+func Remove(bundleName string) error {
+	return defaultRepo.Remove(bundleName)
+}
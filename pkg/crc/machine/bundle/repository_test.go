@@ -120,6 +120,46 @@ func TestListBundles(t *testing.T) {
 	}, names)
 }
 
+func TestRemoveBundle(t *testing.T) {
+	dir := t.TempDir()
+
+	createDummyBundleContent(t, dir, "crc_libvirt_4.6.1", "1.0")
+	createDummyBundleContent(t, dir, "crc_libvirt_4.7.0", "1.0")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "crc_libvirt_4.6.1.crcbundle"), []byte("archive"), 0600))
+
+	repo := &Repository{
+		CacheDir: dir,
+	}
+
+	assert.NoError(t, repo.Remove("crc_libvirt_4.6.1.crcbundle"))
+	assert.NoDirExists(t, filepath.Join(dir, "crc_libvirt_4.6.1"))
+	assert.NoFileExists(t, filepath.Join(dir, "crc_libvirt_4.6.1.crcbundle"))
+	assert.DirExists(t, filepath.Join(dir, "crc_libvirt_4.7.0"))
+
+	assert.Error(t, repo.Remove("crc_libvirt_4.6.1"))
+	assert.Error(t, repo.Remove(".."))
+	assert.Error(t, repo.Remove("../crc_libvirt_4.7.0"))
+	assert.DirExists(t, filepath.Join(dir, "crc_libvirt_4.7.0"))
+}
+
+func TestBundleDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+
+	createDummyBundleContent(t, dir, "crc_libvirt_4.6.1", "1.0")
+
+	repo := &Repository{
+		CacheDir: dir,
+	}
+
+	bundle, err := repo.Get("crc_libvirt_4.6.1")
+	assert.NoError(t, err)
+	metadata, err := os.Stat(filepath.Join(dir, "crc_libvirt_4.6.1", metadataFilename))
+	assert.NoError(t, err)
+	size, err := bundle.GetDiskUsage()
+	assert.NoError(t, err)
+	assert.Equal(t, metadata.Size()+int64(len("openshift-client")+len("kubeadmin-password")+len("kubeconfig")+len("id_ecdsa_crc")+len("crc.qcow2")), size)
+}
+
 func createDummyBundleContent(t *testing.T, dir, name, version string) {
 	bundleDir := filepath.Join(dir, name)
 	assert.NoError(t, os.MkdirAll(bundleDir, 0755))
@@ -0,0 +1,148 @@
+package machine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+)
+
+// ListBundles returns the bundles extracted in the cache directory with the instances using them
+func ListBundles() ([]types.CachedBundle, error) {
+	bundles, err := bundle.List()
+	if err != nil {
+		return nil, err
+	}
+	instances, err := List()
+	if err != nil {
+		return nil, err
+	}
+	inUse, err := bundlesInUse(instances)
+	if err != nil {
+		logging.Warn(err.Error())
+	}
+	cachedBundles := []types.CachedBundle{}
+	for i := range bundles {
+		size, err := bundles[i].GetDiskUsage()
+		if err != nil {
+			return nil, err
+		}
+		cachedBundles = append(cachedBundles, types.CachedBundle{
+			Name:    bundles[i].GetBundleName(),
+			Version: bundles[i].GetVersion(),
+			Preset:  bundles[i].GetBundleType(),
+			Size:    size,
+			UsedBy:  inUse[bundles[i].GetBundleName()],
+		})
+	}
+	return cachedBundles, nil
+}
+
+// BundlesInUse returns the names of the instances using each bundle, indexed by bundle name.
+// An error is returned when the bundle of an instance cannot be determined, as any
+// of the cached bundles could then be in use.
+func BundlesInUse() (map[string][]string, error) {
+	instances, err := List()
+	if err != nil {
+		return nil, err
+	}
+	return bundlesInUse(instances)
+}
+
+func bundlesInUse(instances []types.InstanceInfo) (map[string][]string, error) {
+	inUse := make(map[string][]string)
+	var unknown []string
+	for _, instance := range instances {
+		if instance.BundleName == "" {
+			unknown = append(unknown, instance.Name)
+			continue
+		}
+		name := bundle.GetBundleNameWithoutExtension(instance.BundleName)
+		inUse[name] = append(inUse[name], instance.Name)
+	}
+	if len(unknown) > 0 {
+		return inUse, fmt.Errorf("Cannot determine the bundle used by the %s instance(s), check them with 'crc list'", strings.Join(unknown, ","))
+	}
+	return inUse, nil
+}
+
+// busyBundles counts the downloads and the instance starts of this process using
+// each bundle, indexed by bundle name. Such bundles may not be in use by an
+// instance yet, but must not be removed either.
+var busyBundles = struct {
+	sync.Mutex
+	names map[string]int
+}{names: make(map[string]int)}
+
+// markBundleBusy prevents the removal of a bundle until the returned function is called
+func markBundleBusy(bundleName string) func() {
+	name := bundle.GetBundleNameWithoutExtension(bundleName)
+	busyBundles.Lock()
+	defer busyBundles.Unlock()
+	busyBundles.names[name]++
+	return func() {
+		busyBundles.Lock()
+		defer busyBundles.Unlock()
+		busyBundles.names[name]--
+		if busyBundles.names[name] == 0 {
+			delete(busyBundles.names, name)
+		}
+	}
+}
+
+// RemoveBundle removes a bundle from the cache directory unless an instance is using it
+func RemoveBundle(bundleName string) error {
+	name := bundle.GetBundleNameWithoutExtension(bundleName)
+	busyBundles.Lock()
+	defer busyBundles.Unlock()
+	if busyBundles.names[name] > 0 {
+		return fmt.Errorf("bundle %s is being downloaded or used by a starting instance", name)
+	}
+	inUse, err := BundlesInUse()
+	if err != nil {
+		return err
+	}
+	if instances, ok := inUse[name]; ok {
+		return fmt.Errorf("bundle %s is used by the %s instance(s), delete them first with 'crc delete'", name, strings.Join(instances, ","))
+	}
+	return bundle.Remove(name)
+}
+
+func (client *client) ListBundles() ([]types.CachedBundle, error) {
+	return ListBundles()
+}
+
+func (client *client) GetBundle(bundleName string) (*bundle.CrcBundleInfo, error) {
+	return bundle.Get(bundleName)
+}
+
+func (client *client) RemoveBundle(bundleName string) error {
+	return RemoveBundle(bundleName)
+}
+
+// DownloadBundle downloads and extracts the bundle at bundleURI, or the configured bundle when bundleURI is empty.
+// Nothing is downloaded when the bundle is already extracted in the cache directory.
+func (client *client) DownloadBundle(ctx context.Context, bundleURI string) (*bundle.CrcBundleInfo, error) {
+	if bundleURI == "" {
+		bundleURI = client.config.Get(crcConfig.Bundle).AsString()
+	}
+	bundleName, err := bundle.GetBundleNameFromURI(bundleURI)
+	if err != nil {
+		return nil, err
+	}
+	defer markBundleBusy(bundleName)()
+	if bundleInfo, err := bundle.Get(bundleName); err == nil {
+		return bundleInfo, nil
+	}
+	bundlePath, err := bundle.Download(ctx, client.GetPreset(), bundleURI, client.config.Get(crcConfig.BundleMirror).AsString(),
+		client.config.Get(crcConfig.EnableBundleQuayFallback).AsBool())
+	if err != nil {
+		return nil, err
+	}
+	return bundle.Extract(ctx, bundlePath)
+}
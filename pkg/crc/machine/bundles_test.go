@@ -0,0 +1,37 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundlesInUse(t *testing.T) {
+	inUse, err := bundlesInUse([]types.InstanceInfo{
+		{Name: "crc", BundleName: "crc_libvirt_4.18.1_amd64.crcbundle"},
+		{Name: "dev", BundleName: "crc_libvirt_4.18.1_amd64.crcbundle"},
+		{Name: "edge", BundleName: "crc_microshift_libvirt_4.18.1_amd64.crcbundle"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"crc_libvirt_4.18.1_amd64":            {"crc", "dev"},
+		"crc_microshift_libvirt_4.18.1_amd64": {"edge"},
+	}, inUse)
+}
+
+func TestBundlesInUseWithUnknownBundle(t *testing.T) {
+	inUse, err := bundlesInUse([]types.InstanceInfo{
+		{Name: "crc", BundleName: "crc_libvirt_4.18.1_amd64.crcbundle"},
+		{Name: "broken"},
+	})
+	assert.EqualError(t, err, "Cannot determine the bundle used by the broken instance(s), check them with 'crc list'")
+	assert.Equal(t, map[string][]string{"crc_libvirt_4.18.1_amd64": {"crc"}}, inUse)
+}
+
+func TestRemoveBusyBundle(t *testing.T) {
+	done := markBundleBusy("crc_libvirt_4.18.1_amd64.crcbundle")
+	assert.EqualError(t, RemoveBundle("crc_libvirt_4.18.1_amd64"), "bundle crc_libvirt_4.18.1_amd64 is being downloaded or used by a starting instance")
+	done()
+	assert.Empty(t, busyBundles.names)
+}
@@ -5,10 +5,13 @@ import (
 	"time"
 
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
 	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
 	"github.com/kofalt/go-memoize"
 )
 
@@ -27,6 +30,25 @@ type Client interface {
 	IsRunning() (bool, error)
 	GenerateBundle(forceStop bool) error
 	GetPreset() crcPreset.Preset
+
+	CreateSnapshot(name string) (*types.Snapshot, error)
+	ListSnapshots() ([]types.Snapshot, error)
+	RestoreSnapshot(name string) error
+	DeleteSnapshot(name string) error
+
+	ListBundles() ([]types.CachedBundle, error)
+	GetBundle(bundleName string) (*bundle.CrcBundleInfo, error)
+	DownloadBundle(ctx context.Context, bundleURI string) (*bundle.CrcBundleInfo, error)
+	RemoveBundle(bundleName string) error
+
+	ListPortForwards() ([]network.PortForward, error)
+	AddPortForward(portForward network.PortForward) error
+	RemovePortForward(portForward network.PortForward) error
+
+	EnableRegistry() (*types.Registry, error)
+	DisableRegistry() error
+
+	Diagnose(ctx context.Context, archivePath string) (*types.Diagnostics, error)
 }
 
 type client struct {
@@ -36,6 +58,8 @@ type client struct {
 
 	diskDetails *memoize.Memoizer
 	ramDetails  *memoize.Memoizer
+	// SSH connection to the VM, shared by the calls of the client
+	sshConnections *ssh.Pool
 }
 
 func NewClient(name string, debug bool, config crcConfig.Storage) Client {
@@ -45,6 +69,8 @@ func NewClient(name string, debug bool, config crcConfig.Storage) Client {
 		config:      config,
 		diskDetails: memoize.NewMemoizer(time.Minute, 5*time.Minute),
 		ramDetails:  memoize.NewMemoizer(30*time.Second, 2*time.Minute),
+		// in the daemon, the status is polled every few seconds
+		sshConnections: ssh.NewPool(),
 	}
 }
 
@@ -56,6 +82,16 @@ func (client *client) GetPreset() crcPreset.Preset {
 	return crcConfig.GetPreset(client.config)
 }
 
+// loadVirtualMachine loads the VM of the client, its runners use the SSH
+// connection of the client
+func (client *client) loadVirtualMachine() (*virtualMachine, error) {
+	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	if vm != nil {
+		vm.sshConnections = client.sshConnections
+	}
+	return vm, err
+}
+
 func (client *client) useVSock() bool {
 	return client.networkMode() == network.UserNetworkingMode
 }
@@ -64,6 +100,15 @@ func (client *client) networkMode() network.Mode {
 	return crcConfig.GetNetworkMode(client.config)
 }
 
+// userNetwork returns the user mode network provided by the daemon, it is
+// configured by the settings of the default instance
+func (client *client) userNetwork() (*network.UserNetwork, error) {
+	if client.name == constants.DefaultName {
+		return crcConfig.GetUserNetwork(client.config)
+	}
+	return crcConfig.GetSharedUserNetwork()
+}
+
 func (client *client) modifyHostsFile() bool {
 	return client.config.Get(crcConfig.ModifyHostsFile).AsBool()
 }
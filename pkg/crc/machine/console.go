@@ -10,7 +10,7 @@ func (client *client) GetConsoleURL() (*types.ConsoleResult, error) {
 	// Here we are only checking if the VM exist and not the status of the VM.
 	// We might need to improve and use crc status logic, only
 	// return if the Openshift is running as part of status.
-	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	vm, err := client.loadVirtualMachine()
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot load machine")
 	}
@@ -21,7 +21,7 @@ func (client *client) GetConsoleURL() (*types.ConsoleResult, error) {
 		return nil, errors.Wrap(err, "Error getting the state for virtual machine")
 	}
 
-	clusterConfig, err := getClusterConfig(vm.bundle)
+	clusterConfig, err := getClusterConfig(client.name, vm.bundle)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error loading cluster configuration")
 	}
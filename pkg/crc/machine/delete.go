@@ -2,34 +2,82 @@ package machine
 
 import (
 	"os"
+	"path/filepath"
 
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/services/dns"
 	"github.com/crc-org/crc/v2/pkg/crc/ssh"
 	"github.com/pkg/errors"
 )
 
 func (client *client) Delete() error {
-	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	vm, err := client.loadVirtualMachine()
 	if err != nil && !errors.Is(err, errInvalidBundleMetadata) {
 		return errors.Wrap(err, "Cannot load machine")
 	}
 	defer vm.Close()
 
+	vmState, err := vm.State()
+	if err != nil {
+		logging.Debugf("Cannot get VM status before deleting it: %v", err)
+	}
+	knownHostsEntry := client.knownHostsEntry(vm)
+	// recorded in the instance directory, which is removed with the VM
+	appsDomain := customAppsDomain(client.name)
+
 	if err := vm.Remove(); err != nil {
 		return errors.Wrap(err, "Cannot remove machine")
 	}
 
-	// In case usermode networking make sure all the port bind on host should be released
-	if client.useVSock() {
-		if err := unexposePorts(); err != nil {
+	// In case usermode networking make sure all the port bind on host should be released,
+	// they were only exposed when the instance is running
+	if client.useVSock() && vmState == state.Running {
+		userNetwork, err := client.userNetwork()
+		if err != nil {
+			return err
+		}
+		if err := unexposePorts(userNetwork.InstanceIP.String()); err != nil {
 			return err
 		}
 	}
 
-	if err := cleanKubeconfig(getGlobalKubeConfigPath(), getGlobalKubeConfigPath()); err != nil {
+	if appsDomain != "" && client.modifyHostsFile() {
+		if err := dns.RemoveCustomAppsDomainHosts(appsDomain); err != nil {
+			logging.Warnf("Failed to remove the hosts file records of the %s apps domain: %v", appsDomain, err)
+		}
+	}
+
+	if err := os.Remove(constants.GetRegistriesConfDropInPath(client.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logging.Warnf("Failed to remove the registries.conf drop-in of the registry: %v", err)
+	}
+
+	// the default instance keeps its configuration in ~/.crc/crc.json
+	if client.name != constants.DefaultName {
+		if err := os.RemoveAll(filepath.Dir(constants.GetInstanceConfigPath(client.name))); err != nil {
+			logging.Warnf("Failed to remove the configuration of the instance: %v", err)
+		}
+	}
+
+	if err := cleanGlobalKubeconfig(client.name); err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
 			logging.Warnf("Failed to remove crc contexts from kubeconfig: %v", err)
 		}
 	}
-	return ssh.RemoveCRCHostEntriesFromKnownHosts()
+	if knownHostsEntry == "" {
+		return nil
+	}
+	return ssh.RemoveCRCHostEntriesFromKnownHosts(knownHostsEntry)
+}
+
+// knownHostsEntry returns the host of the instance in the known_hosts file of
+// the user, or an empty string when its address is not known
+func (client *client) knownHostsEntry(vm *virtualMachine) string {
+	ip, err := vm.IP()
+	if err != nil {
+		logging.Debugf("Cannot get the IP of the instance: %v", err)
+		return ""
+	}
+	return ssh.KnownHostsEntry(ip, vm.SSHPort())
 }
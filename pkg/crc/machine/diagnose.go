@@ -0,0 +1,271 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/compress"
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/pkg/errors"
+)
+
+const (
+	diagnosticsArchiveExtension = ".tar.zst"
+	redactedValue               = "<redacted>"
+	// the most recent lines of the journal of each unit are collected
+	journalMaxLines = 100000
+)
+
+// settings which are not secrets in the configuration but must not leave the host
+var redactedSettings = []string{
+	crcConfig.KubeAdminPassword,
+	crcConfig.DeveloperPassword,
+	crcConfig.SharedDirPassword,
+}
+
+// DiagnosticsArchiveName returns the default file name of the archive created by 'crc diagnose'
+func DiagnosticsArchiveName(name string, now time.Time) string {
+	return fmt.Sprintf("%s-diagnose-%s%s", name, now.Format("20060102-150405"), diagnosticsArchiveExtension)
+}
+
+type diagnosticsCollector struct {
+	dir      string
+	failures []string
+}
+
+// collect writes the data returned by fn to the file name of the archive,
+// or records why it could not be collected
+func (d *diagnosticsCollector) collect(name string, fn func() ([]byte, error)) {
+	data, err := fn()
+	if err == nil {
+		err = os.WriteFile(filepath.Join(d.dir, name), data, 0600)
+	}
+	if err != nil {
+		d.fail(name, err)
+	}
+}
+
+// collectStream writes what fn writes to the file name of the archive, the
+// partial content is kept when fn fails
+func (d *diagnosticsCollector) collectStream(name string, fn func(io.Writer) error) {
+	file, err := os.OpenFile(filepath.Join(d.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		d.fail(name, err)
+		return
+	}
+	err = fn(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		d.fail(name, err)
+	}
+}
+
+func (d *diagnosticsCollector) fail(name string, err error) {
+	logging.Debugf("Cannot collect %s: %v", name, err)
+	d.failures = append(d.failures, fmt.Sprintf("%s: %v", name, err))
+}
+
+// Diagnose collects the logs of crc, its configuration and the state of the
+// instance in the archive at archivePath. What cannot be collected, like the
+// journal of a stopped instance, is reported in the result and in the
+// failures.txt file of the archive.
+func (client *client) Diagnose(ctx context.Context, archivePath string) (*types.Diagnostics, error) {
+	tmpDir, err := os.MkdirTemp("", "crc-diagnose")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// the archive contains a single directory named after it
+	d := &diagnosticsCollector{
+		dir: filepath.Join(tmpDir, strings.TrimSuffix(filepath.Base(archivePath), diagnosticsArchiveExtension)),
+	}
+	if err := os.Mkdir(d.dir, 0700); err != nil {
+		return nil, err
+	}
+
+	for _, logFile := range hostLogFiles(constants.LogFilePath, constants.DaemonLogFilePath) {
+		name := filepath.Base(logFile)
+		d.collect(name, func() ([]byte, error) {
+			return os.ReadFile(logFile)
+		})
+	}
+	d.collect("config.txt", func() ([]byte, error) {
+		return redactedConfig(client.config.AllConfigs()), nil
+	})
+	client.collectInstanceDiagnostics(ctx, d)
+
+	if len(d.failures) > 0 {
+		d.collect("failures.txt", func() ([]byte, error) {
+			return []byte(strings.Join(d.failures, "\n") + "\n"), nil
+		})
+	}
+	if err := compress.Compress(d.dir, archivePath); err != nil {
+		return nil, errors.Wrap(err, "Cannot create the diagnostics archive")
+	}
+	return &types.Diagnostics{
+		Path:     archivePath,
+		Failures: d.failures,
+	}, nil
+}
+
+func (client *client) collectInstanceDiagnostics(ctx context.Context, d *diagnosticsCollector) {
+	if exists, err := client.Exists(); err == nil && !exists {
+		d.fail("instance", fmt.Errorf("the instance '%s' does not exist", client.name))
+		return
+	}
+	vm, err := client.loadVirtualMachine()
+	if err != nil && !errors.Is(err, errInvalidBundleMetadata) {
+		d.fail("instance", err)
+		return
+	}
+	defer vm.Close()
+
+	preset := client.GetPreset()
+	d.collect("bundle-info.json", func() ([]byte, error) {
+		if vm.bundle == nil {
+			return nil, errInvalidBundleMetadata
+		}
+		return json.MarshalIndent(vm.bundle, "", "  ")
+	})
+	if vm.bundle != nil {
+		preset = vm.bundle.GetBundleType()
+	}
+
+	vmState, err := vm.State()
+	if err != nil {
+		d.fail("instance", err)
+		return
+	}
+	if vmState != state.Running {
+		d.fail("instance", fmt.Errorf("the instance is %s, its journal and the cluster status cannot be collected", strings.ToLower(string(vmState))))
+		return
+	}
+
+	sshRunner, err := vm.SSHRunner()
+	if err != nil {
+		d.fail("journal", errors.Wrap(err, "Error creating the ssh client"))
+	} else {
+		defer sshRunner.Close()
+		for _, unit := range journalUnits(preset) {
+			d.collectStream(fmt.Sprintf("journal-%s.log", unit), func(w io.Writer) error {
+				return writeJournal(sshRunner, unit, w)
+			})
+		}
+	}
+
+	ip, err := vm.IP()
+	if err != nil {
+		d.fail("cluster", errors.Wrap(err, "Error getting the IP"))
+		return
+	}
+	kubeconfigFilePath := constants.GetKubeconfigFilePath(client.name)
+	if preset != crcPreset.Microshift {
+		d.collect("cluster-operators.json", func() ([]byte, error) {
+			status, err := cluster.GetClusterOperatorsStatus(ctx, ip, kubeconfigFilePath)
+			if err != nil {
+				return nil, err
+			}
+			return json.MarshalIndent(clusterStatusDiagnostics{Status: status, Message: status.String()}, "", "  ")
+		})
+	}
+	d.collect("node-status.json", func() ([]byte, error) {
+		status, err := cluster.GetClusterNodeStatus(ctx, ip, kubeconfigFilePath)
+		if err != nil {
+			return nil, err
+		}
+		return json.MarshalIndent(clusterStatusDiagnostics{Status: status}, "", "  ")
+	})
+}
+
+// writeJournal writes the most recent lines of the journal of unit to w
+func writeJournal(sshRunner *crcssh.Runner, unit string, w io.Writer) error {
+	stdout, _, err := sshRunner.RunPrivileged(fmt.Sprintf("get the journal of %s", unit), "journalctl", "--no-pager", "--boot", "--lines", strconv.Itoa(journalMaxLines), "--unit", unit)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, stdout)
+	return err
+}
+
+type clusterStatusDiagnostics struct {
+	*cluster.Status
+	Message string `json:",omitempty"`
+}
+
+// journalUnits returns the systemd units running the cluster of preset
+func journalUnits(preset crcPreset.Preset) []string {
+	if preset == crcPreset.Microshift {
+		return []string{"microshift", "crio"}
+	}
+	return []string{"kubelet", "crio"}
+}
+
+// hostLogFiles returns the existing log files among logFiles, and their backups
+// made by the log rotation, such as crc-2006-01-02T15-04-05.000.log for crc.log
+func hostLogFiles(logFiles ...string) []string {
+	var files []string
+	for _, logFile := range logFiles {
+		ext := filepath.Ext(logFile)
+		backups, _ := filepath.Glob(strings.TrimSuffix(logFile, ext) + "-*" + ext)
+		sort.Strings(backups)
+		for _, file := range append(backups, logFile) {
+			if _, err := os.Stat(file); err == nil {
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
+
+// redactedConfig returns the settings which differ from their default value
+// in the format of 'crc config view', without the passwords and secrets
+func redactedConfig(cfg map[string]crcConfig.SettingValue) []byte {
+	var lines []string
+	for key, value := range cfg {
+		if value.IsDefault {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %-38s: %s", key, redactedSettingValue(key, value)))
+	}
+	sort.Strings(lines)
+	var config strings.Builder
+	for _, line := range lines {
+		fmt.Fprintln(&config, line)
+	}
+	return []byte(config.String())
+}
+
+func redactedSettingValue(key string, value crcConfig.SettingValue) string {
+	if value.IsSecret || slices.Contains(redactedSettings, key) {
+		return redactedValue
+	}
+	if key == crcConfig.HTTPProxy || key == crcConfig.HTTPSProxy {
+		// the proxy URLs can contain credentials
+		proxyURL, err := url.Parse(value.AsString())
+		if err != nil {
+			return redactedValue
+		}
+		return proxyURL.Redacted()
+	}
+	return value.AsString()
+}
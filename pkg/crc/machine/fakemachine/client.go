@@ -3,9 +3,14 @@ package fakemachine
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"time"
 
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/network/httpproxy"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
 )
@@ -136,5 +141,152 @@ func (c *Client) GetPreset() preset.Preset {
 }
 
 func (c *Client) GetClusterLoad() (*types.ClusterLoadResult, error) {
-	return nil, errors.New("not implemented")
+	if c.Failing {
+		return nil, errors.New("broken")
+	}
+	return &types.ClusterLoadResult{
+		RAMUse:  1_000,
+		RAMSize: 2_000,
+		CPUUse:  []int64{10, 20},
+	}, nil
+}
+
+var DummySnapshot = types.Snapshot{
+	Name:             "base",
+	BundleName:       "crc_libvirt_4.5.1_amd64.crcbundle",
+	OpenshiftVersion: "4.5.1",
+	CreationTime:     time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC),
+}
+
+func (c *Client) CreateSnapshot(name string) (*types.Snapshot, error) {
+	if c.Failing {
+		return nil, errors.New("snapshot creation failed")
+	}
+	snapshot := DummySnapshot
+	snapshot.Name = name
+	return &snapshot, nil
+}
+
+func (c *Client) ListSnapshots() ([]types.Snapshot, error) {
+	if c.Failing {
+		return nil, errors.New("snapshot listing failed")
+	}
+	return []types.Snapshot{DummySnapshot}, nil
+}
+
+func (c *Client) RestoreSnapshot(_ string) error {
+	if c.Failing {
+		return errors.New("snapshot restore failed")
+	}
+	return nil
+}
+
+func (c *Client) DeleteSnapshot(_ string) error {
+	if c.Failing {
+		return errors.New("snapshot deletion failed")
+	}
+	return nil
+}
+
+var DummyBundle = types.CachedBundle{
+	Name:    "crc_libvirt_4.5.1_amd64",
+	Version: "4.5.1",
+	Preset:  preset.OpenShift,
+	Size:    1_000_000_000,
+	UsedBy:  []string{"crc"},
+}
+
+func (c *Client) ListBundles() ([]types.CachedBundle, error) {
+	if c.Failing {
+		return nil, errors.New("bundle listing failed")
+	}
+	return []types.CachedBundle{DummyBundle}, nil
+}
+
+var DummyBundleInfo = bundle.CrcBundleInfo{
+	Version: "1.0",
+	Type:    "snc",
+	Name:    DummyBundle.Name,
+}
+
+func (c *Client) GetBundle(bundleName string) (*bundle.CrcBundleInfo, error) {
+	if c.Failing {
+		return nil, errors.New("bundle metadata is invalid")
+	}
+	if bundleName != DummyBundle.Name {
+		return nil, fmt.Errorf("bundle %s not found: %w", bundleName, os.ErrNotExist)
+	}
+	bundleInfo := DummyBundleInfo
+	return &bundleInfo, nil
+}
+
+// DownloadBundle does not depend on Failing as the API runs it in the background
+func (c *Client) DownloadBundle(_ context.Context, _ string) (*bundle.CrcBundleInfo, error) {
+	bundleInfo := DummyBundleInfo
+	return &bundleInfo, nil
+}
+
+func (c *Client) RemoveBundle(_ string) error {
+	if c.Failing {
+		return errors.New("bundle removal failed")
+	}
+	return nil
+}
+
+var DummyPortForward = network.PortForward{
+	Protocol:  network.TCPProtocol,
+	HostPort:  8080,
+	GuestPort: 30080,
+}
+
+func (c *Client) ListPortForwards() ([]network.PortForward, error) {
+	if c.Failing {
+		return nil, errors.New("port forward listing failed")
+	}
+	return []network.PortForward{DummyPortForward}, nil
+}
+
+func (c *Client) AddPortForward(_ network.PortForward) error {
+	if c.Failing {
+		return errors.New("port forward creation failed")
+	}
+	return nil
+}
+
+func (c *Client) RemovePortForward(portForward network.PortForward) error {
+	if c.Failing {
+		return errors.New("port forward removal failed")
+	}
+	if !portForward.Matches(DummyPortForward) {
+		return fmt.Errorf("host port %d/%s is not forwarded", portForward.HostPort, portForward.Protocol)
+	}
+	return nil
+}
+
+func (c *Client) EnableRegistry() (*types.Registry, error) {
+	if c.Failing {
+		return nil, errors.New("registry enabling failed")
+	}
+	return &types.Registry{
+		Address:        "127.0.0.1:5000",
+		ClusterAddress: "image-registry.openshift-image-registry.svc:5000",
+		RequiresLogin:  true,
+	}, nil
+}
+
+func (c *Client) DisableRegistry() error {
+	if c.Failing {
+		return errors.New("registry disabling failed")
+	}
+	return nil
+}
+
+func (c *Client) Diagnose(_ context.Context, archivePath string) (*types.Diagnostics, error) {
+	if c.Failing {
+		return nil, errors.New("diagnostics collection failed")
+	}
+	return &types.Diagnostics{
+		Path:     archivePath,
+		Failures: []string{"instance: the instance is stopped, its journal and the cluster status cannot be collected"},
+	}, nil
 }
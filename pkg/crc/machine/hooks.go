@@ -0,0 +1,172 @@
+package machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+// hookApplyManifest is replaced in the tests
+var hookApplyManifest = cluster.ApplyManifest
+
+// postStartHook is a manifest applied with 'oc apply' or an executable run on
+// the host with the kubeconfig of the instance
+type postStartHook struct {
+	name     string
+	path     string
+	manifest bool
+}
+
+// listPostStartHooks returns the *.yaml manifests and the executables of dir,
+// in the order of their names
+func listPostStartHooks(dir string) ([]postStartHook, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var hooks []postStartHook
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		hook := postStartHook{
+			name: entry.Name(),
+			path: filepath.Join(dir, entry.Name()),
+		}
+		switch ext := strings.ToLower(filepath.Ext(entry.Name())); {
+		case ext == ".yaml" || ext == ".yml":
+			hook.manifest = true
+		case isExecutable(entry, ext):
+		default:
+			logging.Debugf("Ignoring %s, it's neither a manifest nor an executable", hook.path)
+			continue
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
+
+func isExecutable(entry os.DirEntry, ext string) bool {
+	if runtime.GOOS == "windows" {
+		return ext == ".exe" || ext == ".bat" || ext == ".cmd"
+	}
+	info, err := entry.Info()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&0111 != 0
+}
+
+// runPostStartHooks runs the hooks of the configured directory, one after the
+// other. Their failures are reported in the results, they don't stop the start.
+func (client *client) runPostStartHooks(ctx context.Context, progress *startProgress, sshRunner *crcssh.Runner, ocConfig oc.Config) []types.HookResult {
+	hooks, err := listPostStartHooks(crcConfig.GetPostStartHooksDir(client.config))
+	if err != nil {
+		logging.Warnf("Cannot list the post-start hooks: %v", err)
+		return nil
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+	progress.phase(types.StartPhasePostStartHooks)
+	timeout := time.Duration(client.config.Get(crcConfig.PostStartHookTimeout).AsUInt()) * time.Second
+
+	var results []types.HookResult
+	for _, hook := range hooks {
+		logging.Infof("Running post-start hook %s...", hook.name)
+		result := types.HookResult{
+			Name:    hook.name,
+			LogFile: constants.GetPostStartHookLogPath(client.name, hook.name),
+		}
+		if err := runPostStartHook(ctx, client.name, hook, timeout, result.LogFile, sshRunner, ocConfig); err != nil {
+			logging.Warnf("Post-start hook %s failed: %v, see %s", hook.name, err, result.LogFile)
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func runPostStartHook(ctx context.Context, name string, hook postStartHook, timeout time.Duration, logFile string, sshRunner *crcssh.Runner, ocConfig oc.Config) error {
+	if err := os.MkdirAll(filepath.Dir(logFile), 0750); err != nil {
+		return err
+	}
+	log, err := os.Create(logFile)
+	if err != nil {
+		return err
+	}
+	defer log.Close()
+
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if hook.manifest {
+		err := applyManifestHook(hookCtx, hook, timeout, log, sshRunner, ocConfig)
+		if errors.Is(hookCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+		return err
+	}
+
+	cmd := exec.CommandContext(hookCtx, hook.path) // #nosec G204
+	cmd.Dir = filepath.Dir(hook.path)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("KUBECONFIG=%s", constants.GetKubeconfigFilePath(name)),
+		fmt.Sprintf("PATH=%s%c%s", constants.CrcOcBinDir, os.PathListSeparator, os.Getenv("PATH")),
+		fmt.Sprintf("CRC_INSTANCE_NAME=%s", name),
+	)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	err = cmd.Run()
+	if errors.Is(hookCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
+// applyManifestResult is the outcome of the 'oc apply' command of a manifest hook
+type applyManifestResult struct {
+	output string
+	err    error
+}
+
+// applyManifestHook applies the manifest of hook, the oc command on the
+// instance is killed after timeout. The hook is abandoned when ctx is done, the
+// goroutine applying it only owns its result so its late output is dropped
+// instead of being written to the closed log.
+func applyManifestHook(ctx context.Context, hook postStartHook, timeout time.Duration, log io.Writer, sshRunner *crcssh.Runner, ocConfig oc.Config) error {
+	ocConfig.Timeout = fmt.Sprintf("%ds", int(math.Ceil(timeout.Seconds())))
+	done := make(chan applyManifestResult, 1)
+	go func() {
+		output, err := hookApplyManifest(sshRunner, ocConfig, hook.path, fmt.Sprintf("--request-timeout=%s", timeout))
+		done <- applyManifestResult{output: output, err: err}
+	}()
+	select {
+	case result := <-done:
+		fmt.Fprint(log, result.output)
+		if result.err != nil {
+			fmt.Fprintln(log, result.err)
+		}
+		return result.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,94 @@
+package machine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPostStartHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executables are detected by their extension on Windows")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20-script.sh"), []byte("#!/bin/sh\n"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "10-imagestreams.yaml"), []byte("kind: List\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("hooks\n"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "30-data"), 0700))
+
+	hooks, err := listPostStartHooks(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []postStartHook{
+		{name: "10-imagestreams.yaml", path: filepath.Join(dir, "10-imagestreams.yaml"), manifest: true},
+		{name: "20-script.sh", path: filepath.Join(dir, "20-script.sh")},
+	}, hooks)
+
+	hooks, err = listPostStartHooks(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, hooks)
+}
+
+func TestRunPostStartHookScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "hook.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"kubeconfig: $KUBECONFIG\"\nexit 3\n"), 0700))
+	logFile := filepath.Join(dir, "logs", "hook.sh.log")
+
+	err := runPostStartHook(context.Background(), "crc", postStartHook{name: "hook.sh", path: script}, time.Minute, logFile, nil, oc.Config{})
+	assert.EqualError(t, err, "exit status 3")
+	log, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(log), "kubeconfig: "+constants.GetKubeconfigFilePath("crc"))
+}
+
+func TestRunPostStartHookTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "hook.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nexec sleep 10\n"), 0700))
+
+	err := runPostStartHook(context.Background(), "crc", postStartHook{name: "hook.sh", path: script}, 100*time.Millisecond, filepath.Join(dir, "hook.sh.log"), nil, oc.Config{})
+	assert.EqualError(t, err, "timed out after 100ms")
+}
+
+func TestRunPostStartHookManifestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	ocTimeout := make(chan string, 1)
+	hookApplyManifest = func(_ *ssh.Runner, ocConfig oc.Config, _ string, _ ...string) (string, error) {
+		defer close(finished)
+		ocTimeout <- ocConfig.Timeout
+		<-release
+		return "late output", nil
+	}
+	defer func() { hookApplyManifest = cluster.ApplyManifest }()
+
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "hook.yaml.log")
+	hook := postStartHook{name: "hook.yaml", path: filepath.Join(dir, "hook.yaml"), manifest: true}
+	err := runPostStartHook(context.Background(), "crc", hook, 100*time.Millisecond, logFile, nil, oc.Config{Timeout: "30s"})
+	assert.EqualError(t, err, "timed out after 100ms")
+	assert.Equal(t, "1s", <-ocTimeout)
+
+	// the output of the abandoned hook is dropped
+	close(release)
+	<-finished
+	log, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	assert.Empty(t, log)
+}
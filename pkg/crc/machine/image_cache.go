@@ -0,0 +1,84 @@
+package machine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/crc-org/crc/v2/pkg/crc/systemd"
+)
+
+// drop-in making CRI-O pull the images of the cached registries from the image cache of the daemon
+const instanceImageCacheConf = "/etc/containers/registries.conf.d/crc-image-cache.conf"
+
+// imageCacheRegistries returns the registries cached by the daemon, nil when
+// the cache is disabled. The cache is configured by the default instance.
+func (client *client) imageCacheRegistries() []string {
+	if !client.useVSock() {
+		return nil
+	}
+	config := client.config
+	if client.name != constants.DefaultName {
+		var err error
+		if config, err = crcConfig.LoadDefaultInstanceConfig(); err != nil {
+			logging.Warnf("Cannot read the image cache configuration of the %s instance: %v", constants.DefaultName, err)
+			return nil
+		}
+	}
+	if !config.Get(crcConfig.EnableImageCache).AsBool() {
+		return nil
+	}
+	return crcConfig.GetImageCacheRegistries(config)
+}
+
+// imageCacheMirror returns the location of registry in the image cache of the daemon
+func imageCacheMirror(registry string) string {
+	return fmt.Sprintf("host%s:%d/%s", constants.ClusterDomain, constants.ImageCachePort, registry)
+}
+
+func imageCacheRegistriesConf(registries []string) string {
+	var conf strings.Builder
+	conf.WriteString("# written by crc, see the enable-image-cache setting\n")
+	for _, registry := range registries {
+		fmt.Fprintf(&conf, "\n[[registry]]\nprefix = %q\nlocation = %q\n\n[[registry.mirror]]\nlocation = %q\ninsecure = true\n",
+			registry, registry, imageCacheMirror(registry))
+	}
+	return conf.String()
+}
+
+// configureImageCache adds the image cache as a mirror of the cached
+// registries in the configuration of CRI-O, or removes it when the cache is
+// disabled. It returns false when there was nothing to do.
+func (client *client) configureImageCache(sshRunner *crcssh.Runner) (bool, error) {
+	if registries := client.imageCacheRegistries(); registries != nil {
+		conf := imageCacheRegistriesConf(registries)
+		if err := sshRunner.CopyDataPrivileged([]byte(conf), instanceImageCacheConf, 0644); err != nil {
+			return false, err
+		}
+	} else {
+		if _, _, err := sshRunner.Run("test", "-f", instanceImageCacheConf); err != nil {
+			// never enabled
+			return false, nil
+		}
+		logging.Debugf("Removing %s", instanceImageCacheConf)
+		if _, _, err := sshRunner.RunPrivileged("remove the mirrors of the image cache", "rm", "-f", instanceImageCacheConf); err != nil {
+			return false, err
+		}
+	}
+	return true, systemd.NewInstanceSystemdCommander(sshRunner).Reload("crio")
+}
+
+// configureImageDigestMirrorSet declares the mirrors of the image cache in the
+// configuration of OpenShift, or removes them when the cache is disabled
+func (client *client) configureImageDigestMirrorSet(sshRunner *crcssh.Runner, ocConfig oc.Config) error {
+	registries := client.imageCacheRegistries()
+	if registries == nil {
+		return cluster.DeleteImageDigestMirrorSet(ocConfig)
+	}
+	return cluster.ApplyImageDigestMirrorSet(sshRunner, ocConfig, registries, imageCacheMirror)
+}
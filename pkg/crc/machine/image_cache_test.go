@@ -0,0 +1,28 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageCacheRegistriesConf(t *testing.T) {
+	assert.Equal(t, `# written by crc, see the enable-image-cache setting
+
+[[registry]]
+prefix = "quay.io"
+location = "quay.io"
+
+[[registry.mirror]]
+location = "host.crc.testing:5100/quay.io"
+insecure = true
+
+[[registry]]
+prefix = "registry.redhat.io"
+location = "registry.redhat.io"
+
+[[registry.mirror]]
+location = "host.crc.testing:5100/registry.redhat.io"
+insecure = true
+`, imageCacheRegistriesConf([]string{"quay.io", "registry.redhat.io"}))
+}
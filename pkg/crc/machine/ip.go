@@ -7,7 +7,7 @@ import (
 )
 
 func (client *client) ConnectionDetails() (*types.ConnectionDetails, error) {
-	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	vm, err := client.loadVirtualMachine()
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot load machine")
 	}
@@ -18,9 +18,11 @@ func (client *client) ConnectionDetails() (*types.ConnectionDetails, error) {
 		return nil, errors.Wrap(err, "Cannot get IP")
 	}
 	return &types.ConnectionDetails{
-		IP:          ip,
-		SSHPort:     vm.SSHPort(),
-		SSHUsername: constants.DefaultSSHUser,
-		SSHKeys:     []string{constants.GetPrivateKeyPath(), constants.GetECDSAPrivateKeyPath(), vm.bundle.GetSSHKeyPath()},
+		IP:                   ip,
+		SSHPort:              vm.SSHPort(),
+		SSHUsername:          constants.DefaultSSHUser,
+		SSHKeys:              []string{constants.GetPrivateKeyPath(client.name), constants.GetECDSAPrivateKeyPath(client.name), vm.bundle.GetSSHKeyPath()},
+		KnownHostsFile:       constants.GetKnownHostsPath(client.name),
+		BundleKnownHostsFile: vm.bundle.GetKnownHostsPath(),
 	}, nil
 }
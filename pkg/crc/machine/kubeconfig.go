@@ -26,10 +26,31 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
-const (
-	adminContext     = "crc-admin"
-	developerContext = "crc-developer"
-)
+// adminContext returns the name of the kubeadmin context for the instance called name
+func adminContext(name string) string {
+	return fmt.Sprintf("%s-admin", name)
+}
+
+// developerContext returns the name of the developer context for the instance called name
+func developerContext(name string) string {
+	return fmt.Sprintf("%s-developer", name)
+}
+
+// microshiftContext returns the name of the context of the MicroShift instance called name
+func microshiftContext(name string) string {
+	return kubeconfigEntryName(name, "microshift")
+}
+
+// kubeconfigEntryName returns the name of the cluster or user entry of the
+// kubeconfig for the instance called name. The instances share the same API
+// server URL, the entries of the instances other than the default one are
+// prefixed with their name so that they don't replace each other.
+func kubeconfigEntryName(name, entry string) string {
+	if name == constants.DefaultName {
+		return entry
+	}
+	return fmt.Sprintf("%s-%s", name, entry)
+}
 
 func updateClientCrtAndKeyToKubeconfig(clientKey, clientCrt []byte, srcKubeconfigPath, destKubeconfigPath string) error {
 	cfg, err := clientcmd.LoadFromFile(srcKubeconfigPath)
@@ -50,7 +71,7 @@ func updateClientCrtAndKeyToKubeconfig(clientKey, clientCrt []byte, srcKubeconfi
 	return clientcmd.WriteToFile(*cfg, destKubeconfigPath)
 }
 
-func writeKubeconfig(ip string, clusterConfig *types.ClusterConfig, ingressHTTPSPort uint) error {
+func writeKubeconfig(name string, ip string, clusterConfig *types.ClusterConfig, appsDomain string, ingressHTTPSPort uint) error {
 	kubeconfig, cfg, err := GetGlobalKubeConfig()
 	if err != nil {
 		return err
@@ -59,34 +80,32 @@ func writeKubeconfig(ip string, clusterConfig *types.ClusterConfig, ingressHTTPS
 	if err != nil {
 		return err
 	}
+	ca = append(ca, additionalCAs(name)...)
 	host, err := hostname(clusterConfig.ClusterAPI)
 	if err != nil {
 		return err
 	}
+	cluster := kubeconfigEntryName(name, host)
 
-	cfg.Clusters[host] = &api.Cluster{
+	cfg.Clusters[cluster] = &api.Cluster{
 		Server:                   clusterConfig.ClusterAPI,
 		CertificateAuthorityData: ca,
 	}
 
-	kubeadminToken, err := getTokenForUser("kubeadmin", clusterConfig.KubeAdminPass, ip, ca, clusterConfig, ingressHTTPSPort)
+	kubeadminToken, err := getTokenForUser("kubeadmin", clusterConfig.KubeAdminPass, ip, ca, clusterConfig, appsDomain, ingressHTTPSPort)
 	if err != nil {
 		return err
 	}
-	if err := addContext(cfg, clusterConfig.ClusterAPI, adminContext, "kubeadmin", kubeadminToken, "default"); err != nil {
-		return err
-	}
+	addContext(cfg, cluster, adminContext(name), "kubeadmin", kubeadminToken, "default")
 
-	developerToken, err := getTokenForUser("developer", clusterConfig.DeveloperPass, ip, ca, clusterConfig, ingressHTTPSPort)
+	developerToken, err := getTokenForUser("developer", clusterConfig.DeveloperPass, ip, ca, clusterConfig, appsDomain, ingressHTTPSPort)
 	if err != nil {
 		return err
 	}
-	if err := addContext(cfg, clusterConfig.ClusterAPI, developerContext, "developer", developerToken, ""); err != nil {
-		return err
-	}
+	addContext(cfg, cluster, developerContext(name), "developer", developerToken, "")
 
 	if cfg.CurrentContext == "" {
-		cfg.CurrentContext = adminContext
+		cfg.CurrentContext = adminContext(name)
 	}
 
 	return clientcmd.WriteToFile(*cfg, kubeconfig)
@@ -143,30 +162,23 @@ func hostname(clusterAPI string) (string, error) {
 	return strings.ReplaceAll(h, ".", "-"), nil
 }
 
-func addContext(cfg *api.Config, clusterAPI, context, username, token, namespace string) error {
-	host, err := hostname(clusterAPI)
-	if err != nil {
-		return err
-	}
-
+// addContext adds context using the cluster entry of the kubeconfig called
+// cluster, the user is named after the cluster
+func addContext(cfg *api.Config, cluster, context, username, token, namespace string) {
 	// append /clustername to AuthInfo
-	clusterUser, err := appendClusternameToUser(username, clusterAPI)
-	if err != nil {
-		return err
-	}
+	clusterUser := fmt.Sprintf("%s/%s", username, cluster)
 
 	cfg.AuthInfos[clusterUser] = &api.AuthInfo{
 		Token: token,
 	}
 	cfg.Contexts[context] = &api.Context{
-		Cluster:   host,
+		Cluster:   cluster,
 		AuthInfo:  clusterUser,
 		Namespace: namespace,
 	}
-	return nil
 }
 
-func getTokenForUser(username, password, ip string, ca []byte, clusterConfig *types.ClusterConfig, ingressHTTPSPort uint) (string, error) {
+func getTokenForUser(username, password, ip string, ca []byte, clusterConfig *types.ClusterConfig, appsDomain string, ingressHTTPSPort uint) (string, error) {
 	roots := x509.NewCertPool()
 	ok := roots.AppendCertsFromPEM(ca)
 	if !ok {
@@ -186,7 +198,7 @@ func getTokenForUser(username, password, ip string, ca []byte, clusterConfig *ty
 				if err != nil {
 					return nil, err
 				}
-				if strings.HasSuffix(hostname, constants.AppsDomain) {
+				if strings.HasSuffix(hostname, constants.AppsDomain) || strings.HasSuffix(hostname, "."+appsDomain) {
 					port = strconv.FormatUint(uint64(ingressHTTPSPort), 10)
 				}
 				dialer := net.Dialer{
@@ -228,29 +240,41 @@ func getGlobalKubeConfigPath() string {
 	return filepath.Join(constants.GetHomeDir(), ".kube", "config")
 }
 
-func cleanKubeconfig(input, output string) error {
+// cleanKubeconfig removes the contexts of the instance called name, and the
+// clusters of the API server URL of the instances unless the contexts of one
+// of otherInstances still use them.
+func cleanKubeconfig(input, output string, name string, otherInstances []string) error {
 	cfg, err := clientcmd.LoadFromFile(input)
 	if err != nil {
 		return err
 	}
 
+	clustersInUse := make(map[string]bool)
+	for _, other := range otherInstances {
+		for _, context := range instanceContexts(other) {
+			if context, ok := cfg.Contexts[context]; ok {
+				clustersInUse[context.Cluster] = true
+			}
+		}
+	}
 	var clusterNames []string
-	for name, cluster := range cfg.Clusters {
-		if cluster.Server == fmt.Sprintf("https://api%s:6443", constants.ClusterDomain) {
-			clusterNames = append(clusterNames, name)
+	for clusterName, cluster := range cfg.Clusters {
+		if cluster.Server == fmt.Sprintf("https://api%s:6443", constants.ClusterDomain) && !clustersInUse[clusterName] {
+			clusterNames = append(clusterNames, clusterName)
 		}
 	}
+	instanceContexts := instanceContexts(name)
 	var contextNames []string
 	authNames := make(map[string]struct{})
-	for name, context := range cfg.Contexts {
-		if slices.Contains(clusterNames, context.Cluster) {
-			contextNames = append(contextNames, name)
+	for contextName, context := range cfg.Contexts {
+		if slices.Contains(clusterNames, context.Cluster) || slices.Contains(instanceContexts, contextName) {
+			contextNames = append(contextNames, contextName)
 			authNames[context.AuthInfo] = struct{}{}
 		}
 	}
 	// keep auth if it is shared with other contexts
-	for _, context := range cfg.Contexts {
-		if !slices.Contains(clusterNames, context.Cluster) {
+	for contextName, context := range cfg.Contexts {
+		if !slices.Contains(contextNames, contextName) {
 			delete(authNames, context.AuthInfo)
 		}
 	}
@@ -271,11 +295,31 @@ func cleanKubeconfig(input, output string) error {
 	return clientcmd.WriteToFile(*cfg, output)
 }
 
-func mergeKubeConfigFile(kubeConfigFile string) error {
-	return mergeConfigHelper(kubeConfigFile, getGlobalKubeConfigPath())
+// instanceContexts returns the names of the contexts added for the instance called name
+func instanceContexts(name string) []string {
+	return []string{adminContext(name), developerContext(name), microshiftContext(name)}
 }
 
-func mergeConfigHelper(kubeConfigFile, globalConfigFile string) error {
+// cleanGlobalKubeconfig removes the contexts of the instance called name from
+// the kubeconfig of the user
+func cleanGlobalKubeconfig(name string) error {
+	instances, err := listInstanceNames()
+	if err != nil {
+		return err
+	}
+	otherInstances := slices.DeleteFunc(instances, func(instance string) bool {
+		return instance == name
+	})
+	return cleanKubeconfig(getGlobalKubeConfigPath(), getGlobalKubeConfigPath(), name, otherInstances)
+}
+
+// mergeKubeConfigFile adds the entries of kubeConfigFile, the kubeconfig of the
+// instance called name, to the kubeconfig of the user
+func mergeKubeConfigFile(name, kubeConfigFile string) error {
+	return mergeConfigHelper(name, kubeConfigFile, getGlobalKubeConfigPath())
+}
+
+func mergeConfigHelper(name, kubeConfigFile, globalConfigFile string) error {
 
 	globalConfigPath, globalConf, err := getKubeConfigFromFile(globalConfigFile)
 	if err != nil {
@@ -287,10 +331,11 @@ func mergeConfigHelper(kubeConfigFile, globalConfigFile string) error {
 		return err
 	}
 	// append cluster name to the AuthInfos
-	cfg, err := appendClusterToAuthinfos(currentConf)
+	cfg, err := appendClusterToAuthinfos(currentConf, name)
 	if err != nil {
 		return err
 	}
+	cfg = renameInstanceEntries(cfg, name)
 	// Merge the currentConf to globalConfig
 	for name, cluster := range cfg.Clusters {
 		globalConf.Clusters[name] = cluster
@@ -308,21 +353,40 @@ func mergeConfigHelper(kubeConfigFile, globalConfigFile string) error {
 	return clientcmd.WriteToFile(*globalConf, globalConfigPath)
 }
 
-func appendClusterToAuthinfos(cfg *api.Config) (*api.Config, error) {
+// renameInstanceEntries renames the clusters and contexts of cfg, the
+// kubeconfig of the instance called name, with kubeconfigEntryName
+func renameInstanceEntries(cfg *api.Config, name string) *api.Config {
+	if name == constants.DefaultName {
+		return cfg
+	}
+	renamed := api.NewConfig()
+	renamed.AuthInfos = cfg.AuthInfos
+	for clusterName, cluster := range cfg.Clusters {
+		renamed.Clusters[kubeconfigEntryName(name, clusterName)] = cluster
+	}
+	for contextName, context := range cfg.Contexts {
+		context.Cluster = kubeconfigEntryName(name, context.Cluster)
+		renamed.Contexts[kubeconfigEntryName(name, contextName)] = context
+	}
+	renamed.CurrentContext = kubeconfigEntryName(name, cfg.CurrentContext)
+	return renamed
+}
+
+func appendClusterToAuthinfos(cfg *api.Config, name string) (*api.Config, error) {
 	var clusterAPI string
 	for _, cluster := range cfg.Clusters {
 		clusterAPI = cluster.Server
 	}
-	for name, authInfo := range cfg.AuthInfos {
-		delete(cfg.AuthInfos, name)
-		username, err := appendClusternameToUser(name, clusterAPI)
+	for userName, authInfo := range cfg.AuthInfos {
+		delete(cfg.AuthInfos, userName)
+		username, err := appendClusternameToUser(userName, clusterAPI, name)
 		if err != nil {
 			return cfg, err
 		}
 		cfg.AuthInfos[username] = authInfo
 	}
 	for _, ctx := range cfg.Contexts {
-		username, err := appendClusternameToUser(ctx.AuthInfo, clusterAPI)
+		username, err := appendClusternameToUser(ctx.AuthInfo, clusterAPI, name)
 		if err != nil {
 			return cfg, err
 		}
@@ -331,12 +395,14 @@ func appendClusterToAuthinfos(cfg *api.Config) (*api.Config, error) {
 	return cfg, nil
 }
 
-func appendClusternameToUser(username, clusterAPI string) (string, error) {
+// appendClusternameToUser returns the name of username of the instance called
+// name in the kubeconfig of the user
+func appendClusternameToUser(username, clusterAPI string, name string) (string, error) {
 	url, err := url.Parse(clusterAPI)
 	if err != nil {
 		return "", err
 	}
 	clusterURL := strings.ReplaceAll(url.Host, ".", "-")
 
-	return fmt.Sprintf("%s/%s", username, clusterURL), nil
+	return fmt.Sprintf("%s/%s", username, kubeconfigEntryName(name, clusterURL)), nil
 }
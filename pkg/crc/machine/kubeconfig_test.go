@@ -1,10 +1,13 @@
 package machine
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -44,7 +47,7 @@ func TestCertificateAuthority(t *testing.T) {
 func TestCleanKubeconfig(t *testing.T) {
 	dir := t.TempDir()
 
-	assert.NoError(t, cleanKubeconfig(filepath.Join("testdata", "kubeconfig.in"), filepath.Join(dir, "kubeconfig")))
+	assert.NoError(t, cleanKubeconfig(filepath.Join("testdata", "kubeconfig.in"), filepath.Join(dir, "kubeconfig"), "crc", nil))
 	actual, err := os.ReadFile(filepath.Join(dir, "kubeconfig"))
 	assert.NoError(t, err)
 	expected, err := os.ReadFile(filepath.Join("testdata", "kubeconfig.out"))
@@ -56,7 +59,7 @@ func TestCleanKubeConfigIdempotency(t *testing.T) {
 	// Given
 	dir := t.TempDir()
 	// When
-	assert.NoError(t, cleanKubeconfig(filepath.Join("testdata", "kubeconfig.out"), filepath.Join(dir, "kubeconfig")))
+	assert.NoError(t, cleanKubeconfig(filepath.Join("testdata", "kubeconfig.out"), filepath.Join(dir, "kubeconfig"), "crc", nil))
 	actual, err := os.ReadFile(filepath.Join(dir, "kubeconfig"))
 	// Then
 	assert.NoError(t, err)
@@ -69,7 +72,7 @@ func TestCleanKubeConfigShouldDoNothingWhenClusterDomainIsNotEqualToCrcTesting(t
 	// Given
 	dir := t.TempDir()
 	// When
-	assert.NoError(t, cleanKubeconfig(filepath.Join("testdata", "kubeconfig-without-api-crc-testing-cluster-domain"), filepath.Join(dir, "kubeconfig")))
+	assert.NoError(t, cleanKubeconfig(filepath.Join("testdata", "kubeconfig-without-api-crc-testing-cluster-domain"), filepath.Join(dir, "kubeconfig"), "crc", nil))
 	actual, err := os.ReadFile(filepath.Join(dir, "kubeconfig"))
 	// Then
 	assert.NoError(t, err)
@@ -140,7 +143,7 @@ func Test_mergeKubeConfigFile(t *testing.T) {
 	assert.NoError(t, err, "failed to create temporary kubeconfig file")
 	defer os.Remove(secondaryConfigPath)
 
-	err = mergeConfigHelper(secondaryConfigPath, primaryConfigPath)
+	err = mergeConfigHelper(constants.DefaultName, secondaryConfigPath, primaryConfigPath)
 	assert.NoError(t, err, "failed to modify kubeconfig")
 
 	// Load the modified kubeconfig to ensure it was merged correctly
@@ -163,11 +166,11 @@ func Test_mergeKubeConfigFile(t *testing.T) {
 
 func Test_addContext(t *testing.T) {
 	type input struct {
-		clusterAPI string
-		username   string
-		context    string
-		token      string
-		namespace  string
+		cluster   string
+		username  string
+		context   string
+		token     string
+		namespace string
 	}
 
 	type expected struct {
@@ -180,15 +183,15 @@ func Test_addContext(t *testing.T) {
 		expected expected
 	}{
 		{
-			input{"https://abcdd.api.com", "foo", "foo@abcdd", "secretToken", "kube-system"},
+			input{"abcdd-api-com", "foo", "foo@abcdd", "secretToken", "kube-system"},
 			expected{"foo/abcdd-api-com", "kube-system"},
 		},
 		{
-			input{"https://api.crc.testing:6443", "kubeadmin", "kubeadm", "secretToken", "default"},
+			input{"api-crc-testing:6443", "kubeadmin", "kubeadm", "secretToken", "default"},
 			expected{"kubeadmin/api-crc-testing:6443", "default"},
 		},
 		{
-			input{"https://api.crc.testing:6443", "kubeadmin", "kubeadm", "secretToken", ""},
+			input{"api-crc-testing:6443", "kubeadmin", "kubeadm", "secretToken", ""},
 			expected{"kubeadmin/api-crc-testing:6443", ""},
 		},
 	}
@@ -196,11 +199,72 @@ func Test_addContext(t *testing.T) {
 	cfg := api.NewConfig()
 
 	for _, tt := range tests {
-		err := addContext(cfg, tt.in.clusterAPI, tt.in.context, tt.in.username, tt.in.token, tt.in.namespace)
-		assert.NoError(t, err)
+		addContext(cfg, tt.in.cluster, tt.in.context, tt.in.username, tt.in.token, tt.in.namespace)
 		assert.Contains(t, cfg.Contexts, tt.in.context, "Expected context not found")
 		assert.Equal(t, cfg.Contexts[tt.in.context].Namespace, tt.expected.namespace, "Expected namespace not found")
 		assert.Contains(t, cfg.AuthInfos, tt.expected.user, "Expected AuthInfo not found")
 		assert.Contains(t, cfg.AuthInfos[tt.expected.user].Token, tt.in.token, "Expected token not found")
 	}
 }
+
+func TestCleanKubeconfigKeepsClusterOfOtherInstances(t *testing.T) {
+	dir := t.TempDir()
+	cfg := api.NewConfig()
+	cfg.Clusters["api-crc-testing:6443"] = &api.Cluster{Server: "https://api.crc.testing:6443"}
+	cfg.AuthInfos["kubeadmin/api-crc-testing:6443"] = &api.AuthInfo{Token: "kubeadmin-token"}
+	cfg.AuthInfos["developer/api-crc-testing:6443"] = &api.AuthInfo{Token: "developer-token"}
+	cfg.Contexts["crc-admin"] = &api.Context{Cluster: "api-crc-testing:6443", AuthInfo: "kubeadmin/api-crc-testing:6443"}
+	cfg.Contexts["crc-developer"] = &api.Context{Cluster: "api-crc-testing:6443", AuthInfo: "developer/api-crc-testing:6443"}
+	cfg.Contexts["other-admin"] = &api.Context{Cluster: "api-crc-testing:6443", AuthInfo: "kubeadmin/api-crc-testing:6443"}
+	cfg.CurrentContext = "crc-admin"
+	assert.NoError(t, clientcmd.WriteToFile(*cfg, filepath.Join(dir, "kubeconfig.in")))
+
+	assert.NoError(t, cleanKubeconfig(filepath.Join(dir, "kubeconfig.in"), filepath.Join(dir, "kubeconfig"), "crc", []string{"other"}))
+	actual, err := clientcmd.LoadFromFile(filepath.Join(dir, "kubeconfig"))
+	assert.NoError(t, err)
+	assert.Contains(t, actual.Clusters, "api-crc-testing:6443")
+	assert.Equal(t, []string{"other-admin"}, slices.Collect(maps.Keys(actual.Contexts)))
+	assert.Equal(t, []string{"kubeadmin/api-crc-testing:6443"}, slices.Collect(maps.Keys(actual.AuthInfos)))
+	assert.Empty(t, actual.CurrentContext)
+}
+
+func TestMergeKubeConfigFileOfNamedInstance(t *testing.T) {
+	dir := t.TempDir()
+	instanceConfig := api.NewConfig()
+	instanceConfig.Clusters["microshift"] = &api.Cluster{Server: "https://api.crc.testing:6443"}
+	instanceConfig.AuthInfos["user"] = &api.AuthInfo{Token: "token"}
+	instanceConfig.Contexts["microshift"] = &api.Context{Cluster: "microshift", AuthInfo: "user"}
+	instanceConfig.CurrentContext = "microshift"
+	assert.NoError(t, clientcmd.WriteToFile(*instanceConfig, filepath.Join(dir, "instance")))
+	assert.NoError(t, clientcmd.WriteToFile(*instanceConfig, filepath.Join(dir, "other")))
+
+	assert.NoError(t, mergeConfigHelper(constants.DefaultName, filepath.Join(dir, "other"), filepath.Join(dir, "kubeconfig")))
+	assert.NoError(t, mergeConfigHelper("dev", filepath.Join(dir, "instance"), filepath.Join(dir, "kubeconfig")))
+	merged, err := clientcmd.LoadFromFile(filepath.Join(dir, "kubeconfig"))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"microshift", "dev-microshift"}, slices.Collect(maps.Keys(merged.Clusters)))
+	assert.ElementsMatch(t, []string{"user/api-crc-testing:6443", "user/dev-api-crc-testing:6443"}, slices.Collect(maps.Keys(merged.AuthInfos)))
+	assert.Equal(t, "dev-microshift", merged.Contexts["dev-microshift"].Cluster)
+	assert.Equal(t, "user/dev-api-crc-testing:6443", merged.Contexts["dev-microshift"].AuthInfo)
+	assert.Equal(t, "dev-microshift", merged.CurrentContext)
+}
+
+func TestCleanKubeconfigKeepsClustersOfOtherInstances(t *testing.T) {
+	dir := t.TempDir()
+	cfg := api.NewConfig()
+	for _, name := range []string{"crc", "dev"} {
+		cluster := kubeconfigEntryName(name, "api-crc-testing:6443")
+		cfg.Clusters[cluster] = &api.Cluster{Server: "https://api.crc.testing:6443"}
+		addContext(cfg, cluster, adminContext(name), "kubeadmin", name+"-token", "default")
+	}
+	assert.Equal(t, "kubeadmin/dev-api-crc-testing:6443", cfg.Contexts["dev-admin"].AuthInfo)
+	assert.NoError(t, clientcmd.WriteToFile(*cfg, filepath.Join(dir, "kubeconfig.in")))
+
+	assert.NoError(t, cleanKubeconfig(filepath.Join(dir, "kubeconfig.in"), filepath.Join(dir, "kubeconfig"), "dev", []string{"crc"}))
+	actual, err := clientcmd.LoadFromFile(filepath.Join(dir, "kubeconfig"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api-crc-testing:6443"}, slices.Collect(maps.Keys(actual.Clusters)))
+	assert.Equal(t, []string{"crc-admin"}, slices.Collect(maps.Keys(actual.Contexts)))
+	assert.Equal(t, "crc-token", actual.AuthInfos["kubeadmin/api-crc-testing:6443"].Token)
+	assert.Len(t, actual.AuthInfos, 1)
+}
@@ -14,9 +14,8 @@ const (
 	DefaultNetwork     = "crc"
 	DefaultStoragePool = "crc"
 
-	// Static addresses
+	// Static address
 	MACAddress = "52:fd:fc:07:21:82"
-	IPAddress  = "192.168.130.11"
 )
 
 const (
@@ -9,10 +9,13 @@ const (
 		<port start='1024' end='65535'/>
 	  </nat>
 	</forward>
-	<bridge name='crc' stp='on' delay='0'/>
+	<bridge name='{{ .Bridge }}' stp='on' delay='0'/>
 	<mac address='52:54:00:fd:be:d0'/>
-	<ip family='ipv4' address='192.168.130.1' prefix='24'>
+	<ip family='ipv4' address='{{ .Gateway }}' prefix='{{ .Prefix }}'>
 	  <dhcp>
+		{{- if .DHCPStart }}
+		<range start='{{ .DHCPStart }}' end='{{ .DHCPEnd }}'/>
+		{{- end }}
 		<host mac='{{ .MAC }}' ip='{{ .IP }}'/>
 	  </dhcp>
 	</ip>
@@ -21,6 +24,11 @@ const (
 
 type NetworkConfig struct {
 	NetworkName string
+	Bridge      string
+	Gateway     string
+	Prefix      int
+	DHCPStart   string
+	DHCPEnd     string
 	MAC         string
 	IP          string
 }
@@ -0,0 +1,78 @@
+package machine
+
+import (
+	"fmt"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/pkg/errors"
+)
+
+// List returns the CRC instances known to the machine store
+func List() ([]types.InstanceInfo, error) {
+	names, err := listInstanceNames()
+	if err != nil {
+		return nil, err
+	}
+
+	instances := []types.InstanceInfo{}
+	for _, name := range names {
+		instances = append(instances, getInstanceInfo(name))
+	}
+	return instances, nil
+}
+
+// listInstanceNames returns the names of the CRC instances known to the machine store
+func listInstanceNames() ([]string, error) {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	names, err := libMachineAPIClient.List()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot list machines")
+	}
+	return names, nil
+}
+
+func getInstanceInfo(name string) types.InstanceInfo {
+	info := types.InstanceInfo{
+		Name:  name,
+		State: state.Error,
+	}
+	// The network mode is only needed to reach the VM, which is not the case here
+	vm, err := loadVirtualMachine(name, false)
+	if err != nil && !errors.Is(err, errInvalidBundleMetadata) {
+		logging.Debugf("Cannot load '%s' virtual machine: %v", name, err)
+		return info
+	}
+	defer vm.Close()
+
+	if bundleName, err := vm.Driver.GetBundleName(); err == nil {
+		info.BundleName = bundleName
+	}
+	if vm.bundle != nil {
+		info.Preset = vm.bundle.GetBundleType()
+		info.BundleVersion = vm.bundle.GetVersion()
+	}
+	vmState, err := vm.State()
+	if err != nil {
+		logging.Debugf("Cannot get '%s' machine state: %v", name, err)
+	}
+	info.State = vmState
+	return info
+}
+
+// checkNoOtherInstanceRunning returns an error when an instance other than name is running.
+// All the instances share the same host ports and DNS names, so only one of them can run at a time.
+func checkNoOtherInstanceRunning(name string) error {
+	instances, err := List()
+	if err != nil {
+		return err
+	}
+	for _, instance := range instances {
+		if instance.Name != name && instance.State == state.Running {
+			return fmt.Errorf("The CRC instance '%s' is already running, stop it with 'crc stop --name %s' before starting '%s'", instance.Name, instance.Name, name)
+		}
+	}
+	return nil
+}
@@ -13,7 +13,7 @@ import (
 	"github.com/crc-org/machine/libmachine/drivers"
 )
 
-func getClusterConfig(bundleInfo *bundle.CrcBundleInfo) (*types.ClusterConfig, error) {
+func getClusterConfig(name string, bundleInfo *bundle.CrcBundleInfo) (*types.ClusterConfig, error) {
 	if !bundleInfo.IsOpenShift() {
 		return &types.ClusterConfig{
 			ClusterType: bundleInfo.GetBundleType(),
@@ -21,11 +21,11 @@ func getClusterConfig(bundleInfo *bundle.CrcBundleInfo) (*types.ClusterConfig, e
 		}, nil
 	}
 
-	kubeadminPassword, err := cluster.GetUserPassword(constants.GetKubeAdminPasswordPath())
+	kubeadminPassword, err := cluster.GetUserPassword(constants.GetKubeAdminPasswordPath(name))
 	if err != nil {
 		return nil, fmt.Errorf("error reading kubeadmin password from bundle: %w", err)
 	}
-	developerPassword, err := cluster.GetUserPassword(constants.GetDeveloperPasswordPath())
+	developerPassword, err := cluster.GetUserPassword(constants.GetDeveloperPasswordPath(name))
 	if err != nil {
 		return nil, fmt.Errorf("error reading developer password from bundle: %w", err)
 	}
@@ -39,11 +39,11 @@ func getClusterConfig(bundleInfo *bundle.CrcBundleInfo) (*types.ClusterConfig, e
 	}
 	return &types.ClusterConfig{
 		ClusterType:   bundleInfo.GetBundleType(),
-		ClusterCACert: base64.StdEncoding.EncodeToString(clusterCACert),
+		ClusterCACert: base64.StdEncoding.EncodeToString(append(clusterCACert, additionalCAs(name)...)),
 		KubeConfig:    bundleInfo.GetKubeConfigPath(),
 		KubeAdminPass: kubeadminPassword,
 		DeveloperPass: developerPassword,
-		WebConsoleURL: fmt.Sprintf("https://%s", bundleInfo.GetAppHostname("console-openshift-console")),
+		WebConsoleURL: fmt.Sprintf("https://console-openshift-console.%s", appsDomain(name, bundleInfo)),
 		ClusterAPI:    fmt.Sprintf("https://%s:6443", bundleInfo.GetAPIHostname()),
 		ProxyConfig:   proxyConfig,
 	}, nil
@@ -0,0 +1,157 @@
+package machine
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+
+	"github.com/containers/gvisor-tap-vsock/pkg/types"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/crc-org/crc/v2/pkg/crc/systemd"
+	"github.com/pkg/errors"
+)
+
+const (
+	// port of the instance where a systemd socket proxies TCP connections to the podman socket
+	podmanTCPPort = "2375"
+
+	podmanTCPSocketUnit  = "crc-podman-tcp.socket"
+	podmanTCPServiceUnit = "crc-podman-tcp.service"
+
+	podmanTCPSocketUnitTemplate = `[Unit]
+Description=TCP endpoint of the podman socket forwarded to the host by crc
+
+[Socket]
+ListenStream=%s
+FreeBind=true
+
+[Install]
+WantedBy=sockets.target
+`
+	podmanTCPServiceUnitTemplate = `[Unit]
+Description=Proxy from the TCP endpoint to the podman socket forwarded to the host by crc
+Requires=crc-podman-tcp.socket
+After=crc-podman-tcp.socket
+
+[Service]
+ExecStart=/usr/lib/systemd/systemd-socket-proxyd %s
+`
+)
+
+// podmanSocketConfig describes how the podman socket of the instance is available on the host
+type podmanSocketConfig struct {
+	// guestSocket is the rootful or rootless podman socket of the instance
+	guestSocket string
+	// hostSocket is the unix socket or named pipe forwarded to guestSocket
+	hostSocket string
+	// tcpAddress is the optional host TCP address forwarded to guestSocket
+	tcpAddress string
+	// guestIP is the address of the instance on the user mode network, the
+	// only one where guestSocket is served over TCP
+	guestIP string
+}
+
+func getPodmanSocketConfig(name string, config crcConfig.Storage, guestIP string) podmanSocketConfig {
+	podmanSocket := podmanSocketConfig{
+		guestSocket: crcConfig.GetPodmanSocket(config),
+		hostSocket:  crcConfig.GetHostPodmanSocket(config, name),
+		tcpAddress:  config.Get(crcConfig.PodmanTCPAddress).AsString(),
+		guestIP:     guestIP,
+	}
+	if podmanSocket.tcpAddress == "" {
+		return podmanSocket
+	}
+	// only the user mode network forwards host ports to the instance
+	if crcConfig.GetNetworkMode(config) != network.UserNetworkingMode {
+		logging.Warnf("%s is ignored, it can only be used with %s set to '%s'", crcConfig.PodmanTCPAddress, crcConfig.NetworkMode, network.UserNetworkingMode)
+		podmanSocket.tcpAddress = ""
+	}
+	return podmanSocket
+}
+
+func podmanSocketExposeRequests(name string, instanceIP string, podmanSocket podmanSocketConfig) []types.ExposeRequest {
+	socketProtocol := types.UNIX
+	if runtime.GOOS == "windows" {
+		socketProtocol = types.NPIPE
+	}
+	exposeRequests := []types.ExposeRequest{
+		{
+			Protocol: socketProtocol,
+			Local:    podmanSocket.hostSocket,
+			Remote:   getSSHTunnelURI(name, instanceIP, podmanSocket.guestSocket),
+		},
+	}
+	if podmanSocket.tcpAddress != "" {
+		exposeRequests = append(exposeRequests, types.ExposeRequest{
+			Protocol: "tcp",
+			Local:    podmanSocket.tcpAddress,
+			Remote:   net.JoinHostPort(instanceIP, podmanTCPPort),
+		})
+	}
+	return exposeRequests
+}
+
+// configurePodmanSocket makes the podman socket forwarded to the host usable,
+// it's also served on podmanTCPPort when a host TCP address is set
+func configurePodmanSocket(sshRunner *crcssh.Runner, podmanSocket podmanSocketConfig) error {
+	// the root podman socket is also used by 'crc podman-env --root'
+	if _, _, err := sshRunner.RunPrivileged("make root Podman socket accessible", "chmod 777 /run/podman/ /run/podman/podman.sock"); err != nil {
+		return errors.Wrap(err, "Failed to change permissions to root podman socket")
+	}
+	if podmanSocket.guestSocket == constants.RootlessPodmanSocket {
+		// the socket must outlive the ssh sessions of the user
+		if _, _, err := sshRunner.RunPrivileged("keep the user session of core", "loginctl", "enable-linger", "core"); err != nil {
+			return errors.Wrap(err, "Failed to enable lingering for the core user")
+		}
+		sd := systemd.NewInstanceSystemdCommander(sshRunner).User()
+		if err := sd.Enable("podman.socket"); err != nil {
+			return errors.Wrap(err, "Failed to enable the rootless podman socket")
+		}
+		if err := sd.Start("podman.socket"); err != nil {
+			return errors.Wrap(err, "Failed to start the rootless podman socket")
+		}
+	}
+	return configurePodmanTCPSocket(sshRunner, podmanSocket)
+}
+
+// configurePodmanTCPSocket serves the podman socket on podmanTCPPort of the
+// user mode network address of the instance, not on all its interfaces
+func configurePodmanTCPSocket(sshRunner *crcssh.Runner, podmanSocket podmanSocketConfig) error {
+	enabled := podmanSocket.tcpAddress != ""
+	sd := systemd.NewInstanceSystemdCommander(sshRunner)
+	socketUnitPath := fmt.Sprintf("/etc/systemd/system/%s", podmanTCPSocketUnit)
+	if !enabled {
+		if _, _, err := sshRunner.Run("test", "-f", socketUnitPath); err != nil {
+			// never enabled
+			return nil
+		}
+		logging.Debugf("Disabling %s", podmanTCPSocketUnit)
+		if err := sd.Stop(podmanTCPSocketUnit); err != nil {
+			return err
+		}
+		return sd.Disable(podmanTCPSocketUnit)
+	}
+
+	if err := sshRunner.CopyDataPrivileged([]byte(fmt.Sprintf(podmanTCPSocketUnitTemplate, net.JoinHostPort(podmanSocket.guestIP, podmanTCPPort))), socketUnitPath, 0644); err != nil {
+		return err
+	}
+	if err := sshRunner.CopyDataPrivileged([]byte(fmt.Sprintf(podmanTCPServiceUnitTemplate, podmanSocket.guestSocket)),
+		fmt.Sprintf("/etc/systemd/system/%s", podmanTCPServiceUnit), 0644); err != nil {
+		return err
+	}
+	if err := sd.Enable(podmanTCPSocketUnit); err != nil {
+		return errors.Wrap(err, "Failed to enable the podman TCP socket")
+	}
+	// the proxy is restarted on the next connection, with the current podman socket
+	if err := sd.Stop(podmanTCPServiceUnit); err != nil {
+		logging.Debugf("Failed to stop %s: %v", podmanTCPServiceUnit, err)
+	}
+	if err := sd.Start(podmanTCPSocketUnit); err != nil {
+		return errors.Wrap(err, "Failed to start the podman TCP socket")
+	}
+	return nil
+}
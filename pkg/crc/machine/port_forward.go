@@ -0,0 +1,95 @@
+package machine
+
+import (
+	"fmt"
+
+	"github.com/containers/gvisor-tap-vsock/pkg/types"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+	"github.com/pkg/errors"
+)
+
+func (client *client) ListPortForwards() ([]network.PortForward, error) {
+	return crcConfig.GetPortForwards(client.config), nil
+}
+
+// AddPortForward stores portForward in the configuration so that it's set up
+// on each start, and exposes it right away if the instance is running
+func (client *client) AddPortForward(portForward network.PortForward) error {
+	if !client.useVSock() {
+		return fmt.Errorf("port forwarding requires %s set to '%s'", crcConfig.NetworkMode, network.UserNetworkingMode)
+	}
+	portForwards := crcConfig.GetPortForwards(client.config)
+	for _, pf := range portForwards {
+		if pf.Matches(portForward) {
+			return fmt.Errorf("host port %d/%s is already forwarded to port %d", pf.HostPort, pf.Protocol, pf.GuestPort)
+		}
+	}
+	userNetwork, err := client.userNetwork()
+	if err != nil {
+		return err
+	}
+	instanceIP := userNetwork.InstanceIP.String()
+	podmanSocket := getPodmanSocketConfig(client.name, client.config, instanceIP)
+	builtinPorts := vsockPorts(client.name, instanceIP, client.GetPreset(), client.config.Get(crcConfig.IngressHTTPPort).AsUInt(), client.config.Get(crcConfig.IngressHTTPSPort).AsUInt(), podmanSocket)
+	reserved := reservedHostPorts(builtinPorts)
+	if client.registryEnabled() {
+		reserved[client.registryPortForward(client.GetPreset()).HostPort] = "the local registry forward"
+	}
+	if err := network.CheckReservedHostPorts([]network.PortForward{portForward}, reserved); err != nil {
+		return err
+	}
+	previous := network.FormatPortForwards(portForwards)
+	if _, err := client.config.Set(crcConfig.PortForwards, network.FormatPortForwards(append(portForwards, portForward))); err != nil {
+		return err
+	}
+
+	if running, _ := client.IsRunning(); !running {
+		return nil
+	}
+	exposeRequest := portForwardExposeRequest(instanceIP, portForward)
+	if err := daemonclient.New().NetworkClient.Expose(&exposeRequest); err != nil {
+		if _, setErr := client.config.Set(crcConfig.PortForwards, previous); setErr != nil {
+			logging.Warnf("Failed to remove port forward %s from the configuration: %v", portForward, setErr)
+		}
+		return errors.Wrapf(err, "failed to expose port %s -> %s", exposeRequest.Local, exposeRequest.Remote)
+	}
+	return nil
+}
+
+// RemovePortForward removes the forward using the host port and protocol of
+// portForward from the configuration, and unexposes it if the instance is running
+func (client *client) RemovePortForward(portForward network.PortForward) error {
+	var (
+		found     *network.PortForward
+		remaining []network.PortForward
+	)
+	for _, pf := range crcConfig.GetPortForwards(client.config) {
+		if pf.Matches(portForward) {
+			found = &pf
+			continue
+		}
+		remaining = append(remaining, pf)
+	}
+	if found == nil {
+		return fmt.Errorf("host port %d/%s is not forwarded", portForward.HostPort, portForward.Protocol)
+	}
+	if _, err := client.config.Set(crcConfig.PortForwards, network.FormatPortForwards(remaining)); err != nil {
+		return err
+	}
+
+	if running, _ := client.IsRunning(); !running || !client.useVSock() {
+		return nil
+	}
+	userNetwork, err := client.userNetwork()
+	if err != nil {
+		return err
+	}
+	exposeRequest := portForwardExposeRequest(userNetwork.InstanceIP.String(), *found)
+	if err := daemonclient.New().NetworkClient.Unexpose(&types.UnexposeRequest{Protocol: exposeRequest.Protocol, Local: exposeRequest.Local}); err != nil {
+		return errors.Wrapf(err, "failed to unexpose port %s", exposeRequest.Local)
+	}
+	return nil
+}
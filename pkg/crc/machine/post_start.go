@@ -0,0 +1,33 @@
+package machine
+
+import (
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+// applyPostStartActions creates the namespaces, applies the manifests and
+// subscribes to the operators of actions, in this order
+func applyPostStartActions(sshRunner *crcssh.Runner, ocConfig oc.Config, actions types.PostStartActions) error {
+	for _, namespace := range actions.Namespaces {
+		logging.Infof("Creating namespace %s...", namespace)
+		if err := cluster.CreateNamespace(sshRunner, ocConfig, namespace); err != nil {
+			return err
+		}
+	}
+	for _, manifest := range actions.Manifests {
+		logging.Infof("Applying %s...", manifest)
+		if _, err := cluster.ApplyManifest(sshRunner, ocConfig, manifest); err != nil {
+			return err
+		}
+	}
+	for _, operator := range actions.Operators {
+		logging.Infof("Subscribing to operator %s...", operator.Name)
+		if err := cluster.SubscribeToOperator(sshRunner, ocConfig, operator.Name, operator.Channel, operator.Source, operator.Namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,12 +3,13 @@ package machine
 import "github.com/pkg/errors"
 
 func (client *client) PowerOff() error {
-	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	vm, err := client.loadVirtualMachine()
 	if err != nil {
 		return errors.Wrap(err, "Cannot load machine")
 	}
 	defer vm.Close()
 
+	vm.closeSSHConnections()
 	if err := vm.Kill(); err != nil {
 		return errors.Wrap(err, "Cannot kill machine")
 	}
@@ -0,0 +1,246 @@
+package machine
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	gvtypes "github.com/containers/gvisor-tap-vsock/pkg/types"
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/crc-org/crc/v2/pkg/crc/systemd"
+	"github.com/pkg/errors"
+)
+
+const (
+	registriesConfDropInTemplate = `# written by 'crc registry enable'
+[[registry]]
+location = "%s"
+insecure = true
+`
+	// drop-in letting CRI-O pull the images of the registry deployed on MicroShift over http
+	instanceRegistriesConfDropIn = "/etc/containers/registries.conf.d/crc-registry.conf"
+)
+
+// EnableRegistry stores in the configuration that the registry of the cluster
+// is reachable from the host, and sets it up right away if the instance is
+// running. The returned registry is nil when the instance is not running.
+// The configuration is left unchanged when the setup fails.
+func (client *client) EnableRegistry() (*types.Registry, error) {
+	previous := client.config.Get(crcConfig.EnableLocalRegistry)
+	if _, err := client.config.Set(crcConfig.EnableLocalRegistry, true); err != nil {
+		return nil, err
+	}
+	if running, _ := client.IsRunning(); !running {
+		return nil, nil
+	}
+
+	registry, err := client.setupRegistry()
+	if err != nil {
+		if rollbackErr := restoreSetting(client.config, crcConfig.EnableLocalRegistry, previous); rollbackErr != nil {
+			logging.Warnf("Cannot restore %s: %v", crcConfig.EnableLocalRegistry, rollbackErr)
+		}
+		return nil, err
+	}
+	return registry, nil
+}
+
+// setupRegistry makes the registry of the running instance reachable from the host
+func (client *client) setupRegistry() (*types.Registry, error) {
+	vm, err := client.loadVirtualMachine()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot load machine")
+	}
+	defer vm.Close()
+	instanceIP, err := vm.IP()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting the IP")
+	}
+	sshRunner, err := vm.SSHRunner()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating the ssh client")
+	}
+	defer sshRunner.Close()
+
+	preset := vm.bundle.GetBundleType()
+	registry, err := client.configureRegistry(sshRunner, registryOCConfig(sshRunner, preset), preset, instanceIP)
+	if err != nil {
+		return nil, err
+	}
+	if client.useVSock() {
+		if err := client.exposeRegistry(preset); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// DisableRegistry removes what EnableRegistry sets up, the images pushed to
+// the registry are kept
+func (client *client) DisableRegistry() error {
+	if _, err := client.config.Set(crcConfig.EnableLocalRegistry, false); err != nil {
+		return err
+	}
+	if err := os.Remove(constants.GetRegistriesConfDropInPath(client.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if running, _ := client.IsRunning(); !running {
+		return nil
+	}
+
+	bundleInfo, sshRunner, err := loadVM(client)
+	if err != nil {
+		return err
+	}
+	defer sshRunner.Close()
+
+	preset := bundleInfo.GetBundleType()
+	ocConfig := registryOCConfig(sshRunner, preset)
+	if preset == crcPreset.Microshift {
+		if err := cluster.RemoveLocalRegistry(ocConfig); err != nil {
+			return err
+		}
+		if _, _, err := sshRunner.RunPrivileged("remove the registries.conf drop-in of the registry", "rm", "-f", instanceRegistriesConfDropIn); err != nil {
+			return err
+		}
+	} else if err := cluster.UnexposeImageRegistry(ocConfig); err != nil {
+		return err
+	}
+
+	if !client.useVSock() {
+		return nil
+	}
+	userNetwork, err := client.userNetwork()
+	if err != nil {
+		return err
+	}
+	exposeRequest := portForwardExposeRequest(userNetwork.InstanceIP.String(), client.registryPortForward(preset))
+	if err := daemonclient.New().NetworkClient.Unexpose(&gvtypes.UnexposeRequest{Protocol: exposeRequest.Protocol, Local: exposeRequest.Local}); err != nil {
+		return errors.Wrapf(err, "failed to unexpose port %s", exposeRequest.Local)
+	}
+	return nil
+}
+
+// configureRegistry makes the registry of the cluster reachable from the host,
+// the host port is exposed by exposeRegistry when using the user network mode
+func (client *client) configureRegistry(sshRunner *crcssh.Runner, ocConfig oc.Config, preset crcPreset.Preset, instanceIP string) (*types.Registry, error) {
+	registry := client.registry(preset, instanceIP)
+	if preset == crcPreset.Microshift {
+		if err := cluster.DeployLocalRegistry(sshRunner, ocConfig); err != nil {
+			return nil, err
+		}
+		if err := sshRunner.CopyDataPrivileged([]byte(fmt.Sprintf(registriesConfDropInTemplate, registry.ClusterAddress)), instanceRegistriesConfDropIn, 0644); err != nil {
+			return nil, err
+		}
+		if err := systemd.NewInstanceSystemdCommander(sshRunner).Reload("crio"); err != nil {
+			return nil, errors.Wrap(err, "Failed to reload CRI-O")
+		}
+	} else if err := cluster.ExposeImageRegistry(sshRunner, ocConfig); err != nil {
+		return nil, err
+	}
+
+	if err := writeRegistriesConfDropIn(constants.GetRegistriesConfDropInPath(client.name), registry.Address); err != nil {
+		return nil, errors.Wrap(err, "Failed to allow insecure access to the registry on the host")
+	}
+	return registry, nil
+}
+
+func (client *client) registry(preset crcPreset.Preset, instanceIP string) *types.Registry {
+	registry := &types.Registry{
+		Address: net.JoinHostPort(instanceIP, strconv.FormatUint(uint64(registryGuestPort(preset)), 10)),
+	}
+	if client.useVSock() {
+		registry.Address = net.JoinHostPort(constants.LocalIP, strconv.FormatUint(uint64(client.registryPortForward(preset).HostPort), 10))
+	}
+	if preset == crcPreset.Microshift {
+		registry.ClusterAddress = fmt.Sprintf("localhost:%d", cluster.LocalRegistryPort)
+	} else {
+		registry.ClusterAddress = cluster.ImageRegistryClusterAddress
+		registry.RequiresLogin = true
+	}
+	return registry
+}
+
+// exposeRegistry forwards the registry port of the host to the instance. It's
+// exposed apart from the other ports as it's optional and the default host
+// port can be used by other services, such as AirPlay on macOS.
+func (client *client) exposeRegistry(preset crcPreset.Preset) error {
+	registryPortForward := client.registryPortForward(preset)
+	if err := network.CheckReservedHostPorts(crcConfig.GetPortForwards(client.config), map[uint]string{registryPortForward.HostPort: "the local registry forward"}); err != nil {
+		return err
+	}
+	userNetwork, err := client.userNetwork()
+	if err != nil {
+		return err
+	}
+	daemonClient := daemonclient.New()
+	alreadyOpenedPorts, err := listOpenPorts(daemonClient)
+	if err != nil {
+		return err
+	}
+	exposeRequest := portForwardExposeRequest(userNetwork.InstanceIP.String(), registryPortForward)
+	if isOpened(alreadyOpenedPorts, exposeRequest) {
+		return nil
+	}
+	if err := daemonClient.NetworkClient.Expose(&exposeRequest); err != nil {
+		return errors.Wrapf(err, "failed to expose port %s -> %s", exposeRequest.Local, exposeRequest.Remote)
+	}
+	return nil
+}
+
+func (client *client) registryPortForward(preset crcPreset.Preset) network.PortForward {
+	return network.PortForward{
+		Protocol:  network.TCPProtocol,
+		HostPort:  client.config.Get(crcConfig.LocalRegistryPort).AsUInt(),
+		GuestPort: registryGuestPort(preset),
+	}
+}
+
+func (client *client) registryEnabled() bool {
+	return client.config.Get(crcConfig.EnableLocalRegistry).AsBool()
+}
+
+// registryGuestPort returns the port of the registry on the instance
+func registryGuestPort(preset crcPreset.Preset) uint {
+	if preset == crcPreset.Microshift {
+		return cluster.LocalRegistryPort
+	}
+	return cluster.ImageRegistryNodePort
+}
+
+func registryOCConfig(sshRunner *crcssh.Runner, preset crcPreset.Preset) oc.Config {
+	ocConfig := oc.UseOCWithSSH(sshRunner)
+	if preset == crcPreset.Microshift {
+		ocConfig.Context = "microshift"
+		ocConfig.Cluster = "microshift"
+	}
+	return ocConfig
+}
+
+func writeRegistriesConfDropIn(path, address string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	logging.Debugf("Allowing insecure access to %s in %s", address, path)
+	return os.WriteFile(path, []byte(fmt.Sprintf(registriesConfDropInTemplate, address)), 0600)
+}
+
+// restoreSetting sets key back to previous, a default value is unset
+func restoreSetting(config crcConfig.Storage, key string, previous crcConfig.SettingValue) error {
+	if previous.IsDefault {
+		_, err := config.Unset(key)
+		return err
+	}
+	_, err := config.Set(key, previous.Value)
+	return err
+}
@@ -0,0 +1,58 @@
+package machine
+
+import (
+	"testing"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRegistryTestClient(t *testing.T, mode network.Mode) *client {
+	cfg := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	crcConfig.RegisterSettings(cfg)
+	_, err := cfg.Set(crcConfig.NetworkMode, string(mode))
+	require.NoError(t, err)
+	return NewClient("crc", false, cfg).(*client)
+}
+
+func TestRegistryUserNetworkMode(t *testing.T) {
+	client := newRegistryTestClient(t, network.UserNetworkingMode)
+	assert.Equal(t, &types.Registry{
+		Address:        "127.0.0.1:5000",
+		ClusterAddress: "image-registry.openshift-image-registry.svc:5000",
+		RequiresLogin:  true,
+	}, client.registry(crcPreset.OpenShift, "127.0.0.1"))
+
+	_, err := client.config.Set(crcConfig.LocalRegistryPort, 5050)
+	require.NoError(t, err)
+	assert.Equal(t, network.PortForward{Protocol: network.TCPProtocol, HostPort: 5050, GuestPort: 30500}, client.registryPortForward(crcPreset.OpenShift))
+}
+
+func TestRegistrySystemNetworkMode(t *testing.T) {
+	client := newRegistryTestClient(t, network.SystemNetworkingMode)
+	assert.Equal(t, &types.Registry{
+		Address:        "192.168.130.11:5000",
+		ClusterAddress: "localhost:5000",
+	}, client.registry(crcPreset.Microshift, "192.168.130.11"))
+}
+
+func TestRestoreSetting(t *testing.T) {
+	client := newRegistryTestClient(t, network.SystemNetworkingMode)
+	previous := client.config.Get(crcConfig.EnableLocalRegistry)
+	_, err := client.config.Set(crcConfig.EnableLocalRegistry, true)
+	require.NoError(t, err)
+	require.NoError(t, restoreSetting(client.config, crcConfig.EnableLocalRegistry, previous))
+	assert.True(t, client.config.Get(crcConfig.EnableLocalRegistry).IsDefault)
+
+	_, err = client.config.Set(crcConfig.LocalRegistryPort, 5050)
+	require.NoError(t, err)
+	previous = client.config.Get(crcConfig.LocalRegistryPort)
+	_, err = client.config.Set(crcConfig.LocalRegistryPort, 5060)
+	require.NoError(t, err)
+	require.NoError(t, restoreSetting(client.config, crcConfig.LocalRegistryPort, previous))
+	assert.Equal(t, uint(5050), client.config.Get(crcConfig.LocalRegistryPort).AsUInt())
+}
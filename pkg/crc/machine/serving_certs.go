@@ -0,0 +1,185 @@
+package machine
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// servingCertificate is a user provided certificate with its key, in pem format
+type servingCertificate struct {
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// servingCertificates are the user provided certificates of a start, they are nil when not set
+type servingCertificates struct {
+	caPEM     []byte
+	ingress   *servingCertificate
+	apiServer *servingCertificate
+}
+
+// loadServingCertificates reads and validates the certificates of startConfig,
+// the ingress one must be valid for appsDomain and the API server one for apiHostname
+func loadServingCertificates(startConfig types.StartConfig, appsDomain, apiHostname string) (*servingCertificates, error) {
+	certs := &servingCertificates{}
+	if startConfig.IngressTLSCertFile == "" && startConfig.IngressTLSKeyFile == "" &&
+		startConfig.APITLSCertFile == "" && startConfig.APITLSKeyFile == "" {
+		return certs, nil
+	}
+	if startConfig.TLSCAFile == "" {
+		return nil, errors.New("the CA of the serving certificates must be set")
+	}
+	caPEM, err := os.ReadFile(startConfig.TLSCAFile)
+	if err != nil {
+		return nil, err
+	}
+	certs.caPEM = caPEM
+
+	if certs.ingress, err = loadServingCertificate(startConfig.IngressTLSCertFile, startConfig.IngressTLSKeyFile, caPEM,
+		fmt.Sprintf("console-openshift-console.%s", appsDomain)); err != nil {
+		return nil, err
+	}
+	if certs.apiServer, err = loadServingCertificate(startConfig.APITLSCertFile, startConfig.APITLSKeyFile, caPEM, apiHostname); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+func loadServingCertificate(certFile, keyFile string, caPEM []byte, hostname string) (*servingCertificate, error) {
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("both the certificate and the key must be set, got certificate '%s' and key '%s'", certFile, keyFile)
+	}
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return nil, fmt.Errorf("invalid certificate %s or key %s: %w", certFile, keyFile, err)
+	}
+	ok, err := crctls.VerifyCertificateAgainstRootCA(string(caPEM), string(certPEM), x509.ExtKeyUsageServerAuth)
+	if err != nil {
+		return nil, fmt.Errorf("cannot verify certificate %s: %w", certFile, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("certificate %s is not a serving certificate signed by the CA", certFile)
+	}
+	cert, err := crctls.PemToCert(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	if err := cert.VerifyHostname(hostname); err != nil {
+		return nil, fmt.Errorf("certificate %s cannot be used: %w", certFile, err)
+	}
+	return &servingCertificate{certPEM: certPEM, keyPEM: keyPEM}, nil
+}
+
+// additionalCAs returns the CAs kept in the instance directory which sign the
+// certificates installed by crc in the cluster, in pem format
+func additionalCAs(name string) []byte {
+	var cas []byte
+	for _, path := range []string{constants.GetIngressCACertPath(name), constants.GetCustomCACertPath(name)} {
+		if ca, err := os.ReadFile(path); err == nil {
+			cas = append(cas, ca...)
+		}
+	}
+	return cas
+}
+
+// saveCustomCA keeps a copy of the CA of the user provided certificates in the
+// instance directory, it's removed when caPEM is nil
+func saveCustomCA(name string, caPEM []byte) error {
+	if caPEM == nil {
+		if err := os.Remove(constants.GetCustomCACertPath(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(constants.GetCustomCACertPath(name), caPEM, 0644)
+}
+
+// apiServerCertificateConfigured returns true if a previous start changed the API server certificate
+func apiServerCertificateConfigured(name string) bool {
+	_, err := os.Stat(constants.GetAPICertPath(name))
+	return err == nil
+}
+
+// configureAPIServerCertificate makes the API server present userCert for
+// the API hostname of the bundle, or the certificate of the cluster when userCert is nil
+func configureAPIServerCertificate(ctx context.Context, name string, ocConfig oc.Config, sshRunner *crcssh.Runner, userCert *servingCertificate, bundleInfo *bundle.CrcBundleInfo) error {
+	installedCertPEM, err := os.ReadFile(constants.GetAPICertPath(name))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if userCert == nil {
+		if installedCertPEM == nil {
+			return nil
+		}
+		logging.Info("Restoring the API server certificate of the cluster...")
+		if err := cluster.ResetAPIServerCertificate(ctx, ocConfig); err != nil {
+			return err
+		}
+		// the CA of the removed certificate is no longer trusted by oc in the instance
+		if err := setInstanceKubeconfigCAs(sshRunner, ocConfig, constants.GetKubeconfigFilePath(name), bundleInfo.GetKubeConfigPath(), additionalCAs(name)); err != nil {
+			return fmt.Errorf("failed to update the kubeconfig of the instance: %w", err)
+		}
+		return os.Remove(constants.GetAPICertPath(name))
+	}
+	if bytes.Equal(installedCertPEM, userCert.certPEM) {
+		return nil
+	}
+
+	// the kubeconfig used by oc in the instance must trust the new certificate before it's served
+	if err := setInstanceKubeconfigCAs(sshRunner, ocConfig, constants.GetKubeconfigFilePath(name), bundleInfo.GetKubeConfigPath(), additionalCAs(name)); err != nil {
+		return fmt.Errorf("failed to update the kubeconfig of the instance: %w", err)
+	}
+	logging.Info("Updating the API server certificate...")
+	if err := cluster.SetAPIServerCertificate(ctx, ocConfig, sshRunner, bundleInfo.GetAPIHostname(), userCert.certPEM, userCert.keyPEM); err != nil {
+		return err
+	}
+	return os.WriteFile(constants.GetAPICertPath(name), userCert.certPEM, 0600)
+}
+
+// setInstanceKubeconfigCAs replaces the certificate authorities of the
+// kubeconfig file of the instance with the ones of the bundle kubeconfig and
+// cas, and copies it to the instance
+func setInstanceKubeconfigCAs(sshRunner *crcssh.Runner, ocConfig oc.Config, kubeconfigFilePath string, bundleKubeconfigFilePath string, cas []byte) error {
+	bundleCA, err := certificateAuthority(bundleKubeconfigFilePath)
+	if err != nil {
+		return err
+	}
+	cfg, err := clientcmd.LoadFromFile(kubeconfigFilePath)
+	if err != nil {
+		return err
+	}
+	kubeCluster, ok := cfg.Clusters["crc"]
+	if !ok {
+		return fmt.Errorf("crc cluster not found in kubeconfig %s", kubeconfigFilePath)
+	}
+	kubeCluster.CertificateAuthorityData = append(bundleCA, cas...)
+	if err := clientcmd.WriteToFile(*cfg, kubeconfigFilePath); err != nil {
+		return err
+	}
+	return sshRunner.CopyFile(kubeconfigFilePath, ocConfig.KubeconfigPath, 0644)
+}
@@ -0,0 +1,78 @@
+package machine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestCertificates(t *testing.T, dir string, dnsNames ...string) (string, string, string) {
+	var domains []string
+	for _, dnsName := range dnsNames {
+		domains = append(domains, strings.TrimPrefix(dnsName, "*."))
+	}
+	caKey, caCert, err := crctls.GetSelfSignedIngressCA(domains)
+	require.NoError(t, err)
+	keyPEM, certPEM, err := crctls.GenerateServingCertificate(caKey, caCert, dnsNames...)
+	require.NoError(t, err)
+
+	caFile := filepath.Join(dir, "ca.crt")
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(caFile, crctls.CertToPem(caCert), 0600))
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0600))
+	return caFile, certFile, keyFile
+}
+
+func TestLoadServingCertificates(t *testing.T) {
+	caFile, certFile, keyFile := writeTestCertificates(t, t.TempDir(), "*.apps.dev.example.com", "api.crc.testing")
+
+	certs, err := loadServingCertificates(types.StartConfig{
+		IngressTLSCertFile: certFile,
+		IngressTLSKeyFile:  keyFile,
+		APITLSCertFile:     certFile,
+		APITLSKeyFile:      keyFile,
+		TLSCAFile:          caFile,
+	}, "apps.dev.example.com", "api.crc.testing")
+	require.NoError(t, err)
+	assert.NotEmpty(t, certs.caPEM)
+	require.NotNil(t, certs.ingress)
+	require.NotNil(t, certs.apiServer)
+	assert.Equal(t, certs.ingress.certPEM, certs.apiServer.certPEM)
+}
+
+func TestLoadServingCertificatesNotSet(t *testing.T) {
+	certs, err := loadServingCertificates(types.StartConfig{}, "apps-crc.testing", "api.crc.testing")
+	require.NoError(t, err)
+	assert.Nil(t, certs.caPEM)
+	assert.Nil(t, certs.ingress)
+	assert.Nil(t, certs.apiServer)
+}
+
+func TestLoadServingCertificatesErrors(t *testing.T) {
+	dir := t.TempDir()
+	caFile, certFile, keyFile := writeTestCertificates(t, dir, "*.apps-crc.testing")
+	otherCAFile, _, otherKeyFile := writeTestCertificates(t, t.TempDir(), "*.apps-crc.testing")
+
+	_, err := loadServingCertificates(types.StartConfig{IngressTLSCertFile: certFile, IngressTLSKeyFile: keyFile}, "apps-crc.testing", "api.crc.testing")
+	assert.EqualError(t, err, "the CA of the serving certificates must be set")
+
+	_, err = loadServingCertificates(types.StartConfig{IngressTLSCertFile: certFile, TLSCAFile: caFile}, "apps-crc.testing", "api.crc.testing")
+	assert.ErrorContains(t, err, "both the certificate and the key must be set")
+
+	_, err = loadServingCertificates(types.StartConfig{IngressTLSCertFile: certFile, IngressTLSKeyFile: otherKeyFile, TLSCAFile: caFile}, "apps-crc.testing", "api.crc.testing")
+	assert.ErrorContains(t, err, "invalid certificate")
+
+	_, err = loadServingCertificates(types.StartConfig{IngressTLSCertFile: certFile, IngressTLSKeyFile: keyFile, TLSCAFile: otherCAFile}, "apps-crc.testing", "api.crc.testing")
+	assert.ErrorContains(t, err, "is not a serving certificate signed by the CA")
+
+	_, err = loadServingCertificates(types.StartConfig{APITLSCertFile: certFile, APITLSKeyFile: keyFile, TLSCAFile: caFile}, "apps-crc.testing", "api.crc.testing")
+	assert.ErrorContains(t, err, "cannot be used")
+}
@@ -0,0 +1,226 @@
+package machine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/validation"
+	"github.com/pkg/errors"
+)
+
+const snapshotsMetadataFile = "snapshots.json"
+
+func (client *client) CreateSnapshot(name string) (*types.Snapshot, error) {
+	if err := validation.ValidateSnapshotName(name); err != nil {
+		return nil, err
+	}
+	vm, err := client.loadStoppedVirtualMachine("create a snapshot")
+	if err != nil {
+		return nil, err
+	}
+	defer vm.Close()
+
+	snapshots, err := readSnapshotsMetadata(client.name)
+	if err != nil {
+		return nil, err
+	}
+	if findSnapshot(snapshots, name) != -1 {
+		return nil, fmt.Errorf("Snapshot '%s' already exists", name)
+	}
+
+	logging.Infof("Creating snapshot %s of the instance disk...", name)
+	if err := createDiskSnapshot(client.name, name); err != nil {
+		return nil, errors.Wrap(err, "Cannot create disk snapshot")
+	}
+	if err := saveSnapshotHostKey(client.name, name); err != nil {
+		return nil, err
+	}
+	snapshot := types.Snapshot{
+		Name:             name,
+		BundleName:       vm.bundle.GetBundleName(),
+		OpenshiftVersion: vm.bundle.GetVersion(),
+		CreationTime:     time.Now(),
+	}
+	if err := writeSnapshotsMetadata(client.name, append(snapshots, snapshot)); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (client *client) ListSnapshots() ([]types.Snapshot, error) {
+	return readSnapshotsMetadata(client.name)
+}
+
+// RestoreSnapshot reverts the instance disk to the snapshot called name.
+// The restored cluster does not know about the credentials issued after the
+// snapshot was taken, so they are dropped from the host and the next start
+// generates new ones. The SSH host key pinned when the snapshot was taken is
+// pinned again as the host keys of the VM are reverted with its disk.
+func (client *client) RestoreSnapshot(name string) error {
+	if err := validation.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	vm, err := client.loadStoppedVirtualMachine("restore a snapshot")
+	if err != nil {
+		return err
+	}
+	defer vm.Close()
+
+	snapshots, err := readSnapshotsMetadata(client.name)
+	if err != nil {
+		return err
+	}
+	i := findSnapshot(snapshots, name)
+	if i == -1 {
+		return fmt.Errorf("Snapshot '%s' does not exist", name)
+	}
+	if snapshots[i].BundleName != vm.bundle.GetBundleName() {
+		return fmt.Errorf("Snapshot '%s' was created with bundle '%s', but the instance is using '%s'", name, snapshots[i].BundleName, vm.bundle.GetBundleName())
+	}
+
+	logging.Infof("Restoring snapshot %s of the instance disk...", name)
+	if err := revertDiskSnapshot(client.name, name); err != nil {
+		return errors.Wrap(err, "Cannot restore disk snapshot")
+	}
+	if err := restoreSnapshotHostKey(client.name, name); err != nil {
+		return err
+	}
+	return removeStaleCredentials(client.name)
+}
+
+func (client *client) DeleteSnapshot(name string) error {
+	if err := validation.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	snapshots, err := readSnapshotsMetadata(client.name)
+	if err != nil {
+		return err
+	}
+	i := findSnapshot(snapshots, name)
+	if i == -1 {
+		return fmt.Errorf("Snapshot '%s' does not exist", name)
+	}
+	vm, err := client.loadStoppedVirtualMachine("delete a snapshot")
+	if err != nil {
+		return err
+	}
+	defer vm.Close()
+
+	if err := deleteDiskSnapshot(client.name, name); err != nil {
+		return errors.Wrap(err, "Cannot delete disk snapshot")
+	}
+	if err := os.Remove(snapshotKnownHostsPath(client.name, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return writeSnapshotsMetadata(client.name, slices.Delete(snapshots, i, i+1))
+}
+
+// loadStoppedVirtualMachine loads the instance VM and makes sure it is not running,
+// as the disk image cannot be modified while it is in use
+func (client *client) loadStoppedVirtualMachine(action string) (*virtualMachine, error) {
+	vm, err := client.loadVirtualMachine()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot load machine")
+	}
+	vmState, err := vm.State()
+	if err != nil {
+		vm.Close()
+		return nil, errors.Wrap(err, "Cannot get machine state")
+	}
+	if vmState == state.Running {
+		vm.Close()
+		return nil, fmt.Errorf("Cannot %s while the instance is running, stop it with 'crc stop' first", action)
+	}
+	return vm, nil
+}
+
+// removeStaleCredentials removes the instance kubeconfig and the instance
+// contexts of the global kubeconfig. The client certificate is regenerated and
+// added to the cluster, and the contexts are written again, on the next start.
+func removeStaleCredentials(name string) error {
+	if err := os.Remove(constants.GetKubeconfigFilePath(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := cleanGlobalKubeconfig(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logging.Warnf("Failed to remove crc contexts from kubeconfig: %v", err)
+	}
+	return nil
+}
+
+// snapshotKnownHostsPath returns the copy of the known_hosts file of the
+// instance made when the snapshot was taken
+func snapshotKnownHostsPath(name string, snapshot string) string {
+	return filepath.Join(constants.GetInstanceDir(name), fmt.Sprintf("known_hosts.%s", snapshot))
+}
+
+// saveSnapshotHostKey keeps the SSH host key pinned for the VM when the
+// snapshot is taken. There is none when the VM was never started, its host
+// keys are still the ones of the bundle.
+func saveSnapshotHostKey(name string, snapshot string) error {
+	data, err := os.ReadFile(constants.GetKnownHostsPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(snapshotKnownHostsPath(name, snapshot), data, 0600)
+}
+
+// restoreSnapshotHostKey pins the SSH host key saved with the snapshot, or
+// removes the pinned key when the snapshot has none so that the next start
+// generates new host keys
+func restoreSnapshotHostKey(name string, snapshot string) error {
+	data, err := os.ReadFile(snapshotKnownHostsPath(name, snapshot))
+	if errors.Is(err, os.ErrNotExist) {
+		if err := os.Remove(constants.GetKnownHostsPath(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(constants.GetKnownHostsPath(name), data, 0600)
+}
+
+func snapshotsMetadataPath(name string) string {
+	return filepath.Join(constants.GetInstanceDir(name), snapshotsMetadataFile)
+}
+
+func readSnapshotsMetadata(name string) ([]types.Snapshot, error) {
+	snapshots := []types.Snapshot{}
+	data, err := os.ReadFile(snapshotsMetadataPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshots, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, errors.Wrapf(err, "Cannot parse %s", snapshotsMetadataPath(name))
+	}
+	return snapshots, nil
+}
+
+func writeSnapshotsMetadata(name string, snapshots []types.Snapshot) error {
+	data, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(snapshotsMetadataPath(name), data, 0600)
+}
+
+func findSnapshot(snapshots []types.Snapshot, name string) int {
+	return slices.IndexFunc(snapshots, func(snapshot types.Snapshot) bool {
+		return snapshot.Name == name
+	})
+}
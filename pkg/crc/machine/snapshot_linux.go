@@ -0,0 +1,35 @@
+package machine
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+)
+
+func diskImagePath(name string) string {
+	return filepath.Join(constants.GetInstanceDir(name), fmt.Sprintf("%s.qcow2", name))
+}
+
+func runQemuImgSnapshot(name string, args ...string) error {
+	args = append([]string{"snapshot"}, args...)
+	args = append(args, diskImagePath(name))
+	_, stderr, err := crcos.RunWithDefaultLocale("qemu-img", args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", stderr, err)
+	}
+	return nil
+}
+
+func createDiskSnapshot(name string, snapshot string) error {
+	return runQemuImgSnapshot(name, "-c", snapshot)
+}
+
+func revertDiskSnapshot(name string, snapshot string) error {
+	return runQemuImgSnapshot(name, "-a", snapshot)
+}
+
+func deleteDiskSnapshot(name string, snapshot string) error {
+	return runQemuImgSnapshot(name, "-d", snapshot)
+}
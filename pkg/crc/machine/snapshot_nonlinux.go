@@ -0,0 +1,20 @@
+//go:build !linux
+
+package machine
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func createDiskSnapshot(_ string, _ string) error {
+	return fmt.Errorf("Not implemented for %s", runtime.GOOS)
+}
+
+func revertDiskSnapshot(_ string, _ string) error {
+	return fmt.Errorf("Not implemented for %s", runtime.GOOS)
+}
+
+func deleteDiskSnapshot(_ string, _ string) error {
+	return fmt.Errorf("Not implemented for %s", runtime.GOOS)
+}
@@ -0,0 +1,18 @@
+package machine
+
+import (
+	"testing"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotNameIsValidated(t *testing.T) {
+	crcConfigStorage := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	client := NewClient("i-dont-exist", false, crcConfigStorage)
+
+	_, err := client.CreateSnapshot("../disk")
+	assert.ErrorContains(t, err, "is not a valid snapshot name")
+	assert.ErrorContains(t, client.RestoreSnapshot("../disk"), "is not a valid snapshot name")
+	assert.ErrorContains(t, client.DeleteSnapshot("../disk"), "is not a valid snapshot name")
+}
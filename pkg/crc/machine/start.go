@@ -1,6 +1,7 @@
 package machine
 
 import (
+	"bytes"
 	"context"
 	"crypto/rsa"
 	"crypto/x509"
@@ -14,6 +15,7 @@ import (
 	"go.podman.io/common/pkg/strongunits"
 
 	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	crcerrors "github.com/crc-org/crc/v2/pkg/crc/errors"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
@@ -43,7 +45,7 @@ import (
 
 const minimumMemoryForMonitoring = strongunits.MiB(14336)
 
-func getCrcBundleInfo(ctx context.Context, preset crcPreset.Preset, bundleName, bundlePath string, enableBundleQuayFallback bool) (*bundle.CrcBundleInfo, error) {
+func getCrcBundleInfo(ctx context.Context, preset crcPreset.Preset, bundleName, bundlePath, bundleMirror string, enableBundleQuayFallback bool) (*bundle.CrcBundleInfo, error) {
 	bundleInfo, err := bundle.Use(bundleName)
 	if err == nil {
 		logging.Infof("Loading bundle: %s...", bundleName)
@@ -51,7 +53,7 @@ func getCrcBundleInfo(ctx context.Context, preset crcPreset.Preset, bundleName,
 	}
 	logging.Debugf("Failed to load bundle %s: %v", bundleName, err)
 	logging.Infof("Downloading bundle: %s...", bundleName)
-	bundlePath, err = bundle.Download(ctx, preset, bundlePath, enableBundleQuayFallback)
+	bundlePath, err = bundle.Download(ctx, preset, bundlePath, bundleMirror, enableBundleQuayFallback)
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +202,7 @@ func growLVForMicroshift(sshRunner crcos.CommandRunner, lvFullName string, rootP
 	return nil
 }
 
-func configureSharedDirs(vm *virtualMachine, sshRunner *crcssh.Runner) error {
+func configureSharedDirs(vm *virtualMachine, sshRunner *crcssh.Runner, gatewayIP string) error {
 	logging.Debugf("Configuring shared directories")
 	sharedDirs, err := vm.Driver.GetSharedDirs()
 	if err != nil {
@@ -254,7 +256,7 @@ func configureSharedDirs(vm *virtualMachine, sshRunner *crcssh.Runner) error {
 			if _, _, err := sshRunner.Run("9pfs -V -p", fmt.Sprintf("%d", constants.Plan9HvsockPort), "2", mount.Target); err != nil {
 				logging.Warnf("Failed to connect to 9p server over hvsock: %v", err)
 				logging.Warnf("Falling back to 9p over TCP")
-				if _, _, err := sshRunner.Run("9pfs", constants.VSockGateway, mount.Target); err != nil {
+				if _, _, err := sshRunner.Run("9pfs", gatewayIP, mount.Target); err != nil {
 					return err
 				}
 			}
@@ -268,6 +270,13 @@ func configureSharedDirs(vm *virtualMachine, sshRunner *crcssh.Runner) error {
 }
 
 func (client *client) Start(ctx context.Context, startConfig types.StartConfig) (*types.StartResult, error) {
+	progress := newStartProgress(ctx)
+	result, err := client.start(ctx, startConfig, progress)
+	progress.finish(err)
+	return result, err
+}
+
+func (client *client) start(ctx context.Context, startConfig types.StartConfig, progress *startProgress) (*types.StartResult, error) {
 	telemetry.SetCPUs(ctx, startConfig.CPUs)
 	telemetry.SetMemory(ctx, uint64(startConfig.Memory.ToBytes()))
 	telemetry.SetDiskSize(ctx, uint64(startConfig.DiskSize.ToBytes()))
@@ -276,6 +285,15 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		return nil, err
 	}
 
+	if err := checkNoOtherInstanceRunning(client.name); err != nil {
+		return nil, err
+	}
+
+	userNetwork, err := client.userNetwork()
+	if err != nil {
+		return nil, err
+	}
+
 	// Pre-VM start
 	exists, err := client.Exists()
 	if err != nil {
@@ -283,17 +301,19 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 	}
 
 	if exists {
-		if err := checkMachineInstanceDir(); err != nil {
+		if err := checkMachineInstanceDir(client.name); err != nil {
 			return nil, err
 		}
 	}
 
+	progress.phase(types.StartPhaseBundle)
 	bundleNameFromURI, err := bundle.GetBundleNameFromURI(startConfig.BundlePath)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error getting bundle name")
 	}
 	bundleName := bundle.GetBundleNameWithoutExtension(bundleNameFromURI)
-	crcBundleMetadata, err := getCrcBundleInfo(ctx, startConfig.Preset, bundleName, startConfig.BundlePath, startConfig.EnableBundleQuayFallback)
+	defer markBundleBusy(bundleName)()
+	crcBundleMetadata, err := getCrcBundleInfo(ctx, startConfig.Preset, bundleName, startConfig.BundlePath, startConfig.BundleMirror, startConfig.EnableBundleQuayFallback)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error getting bundle metadata")
 	}
@@ -301,10 +321,14 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 	if err := validation.BundleMismatchWithPresetMetadata(startConfig.Preset, crcBundleMetadata); err != nil {
 		return nil, err
 	}
+	if err := validateAppsDomain(startConfig.AppsDomain, crcBundleMetadata); err != nil {
+		return nil, err
+	}
 
 	if !exists {
 		telemetry.SetStartType(ctx, telemetry.CreationStartType)
 
+		progress.phase(types.StartPhaseCreateVM)
 		// Ask early for pull secret if it hasn't been requested yet
 		_, err = startConfig.PullSecret.Value()
 		if err != nil {
@@ -342,7 +366,7 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		telemetry.SetStartType(ctx, telemetry.StartStartType)
 	}
 
-	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	vm, err := client.loadVirtualMachine()
 	if err != nil {
 		return nil, errors.Wrap(err, "Error loading machine")
 	}
@@ -362,7 +386,7 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 	}
 	if vmState == state.Running {
 		logging.Infof("A CRC VM for %s %s is already running", startConfig.Preset.ForDisplay(), vm.bundle.GetVersion())
-		clusterConfig, err := getClusterConfig(vm.bundle)
+		clusterConfig, err := getClusterConfig(client.name, vm.bundle)
 		if err != nil {
 			return nil, errors.Wrap(err, "Cannot create cluster configuration")
 		}
@@ -379,12 +403,36 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		return nil, err
 	}
 
+	targetAppsDomain := startConfig.AppsDomain
+	if targetAppsDomain == "" {
+		targetAppsDomain = vm.bundle.ClusterInfo.AppsDomain
+	}
+	servingCerts, err := loadServingCertificates(startConfig, targetAppsDomain, vm.bundle.GetAPIHostname())
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid serving certificates")
+	}
+	if err := saveCustomCA(client.name, servingCerts.caPEM); err != nil {
+		return nil, errors.Wrap(err, "Cannot save the CA of the serving certificates")
+	}
+
+	podmanSocket := getPodmanSocketConfig(client.name, client.config, userNetwork.InstanceIP.String())
+	if podmanSocket.tcpAddress != "" {
+		logging.Warnf("The podman socket of the instance is available without authentication on %s, "+
+			"any process of the host which can connect to it gets root access to the instance", podmanSocket.tcpAddress)
+	}
+
+	progress.phase(types.StartPhaseStartVM)
 	logging.Infof("Starting CRC VM for %s %s...", startConfig.Preset, vm.bundle.GetVersion())
 
 	if client.useVSock() {
-		if err := exposePorts(startConfig.Preset, startConfig.IngressHTTPPort, startConfig.IngressHTTPSPort); err != nil {
+		if err := exposePorts(client.name, userNetwork.InstanceIP.String(), startConfig.Preset, startConfig.IngressHTTPPort, startConfig.IngressHTTPSPort, crcConfig.GetPortForwards(client.config), podmanSocket); err != nil {
 			return nil, err
 		}
+		if client.registryEnabled() {
+			if err := client.exposeRegistry(startConfig.Preset); err != nil {
+				logging.Warnf("The local registry is not reachable from the host: %v", err)
+			}
+		}
 	}
 
 	if err := client.updateVMConfig(startConfig, vm); err != nil {
@@ -395,6 +443,7 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		return nil, errors.Wrap(err, "Error starting machine")
 	}
 
+	progress.phase(types.StartPhaseWaitSSH)
 	// Post-VM start
 	vmState, err = vm.State()
 	if err != nil {
@@ -421,22 +470,34 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 	}
 	logging.Info("CRC VM is running")
 
+	progress.phase(types.StartPhaseUpdateSSHKey)
+	// the known_hosts file is removed when the VM is created, its host keys are
+	// the ones of the bundle until they are regenerated
+	if _, err := os.Stat(constants.GetKnownHostsPath(client.name)); errors.Is(err, os.ErrNotExist) {
+		logging.Info("Generating new SSH host keys...")
+		if err := sshRunner.RegenerateHostKeys(constants.GetKnownHostsPath(client.name)); err != nil {
+			return nil, errors.Wrap(err, "Error regenerating the SSH host keys")
+		}
+	}
+	// Post VM start immediately update SSH key and copy kubeconfig to instance
+	// dir and VM
+	if err := updateSSHKeyPair(client.name, sshRunner); err != nil {
+		return nil, errors.Wrap(err, "Error updating public key")
+	}
+
+	progress.phase(types.StartPhaseEmergencyLogin)
+	// the password is only sent once the host key of the VM is pinned
 	if startConfig.EmergencyLogin {
-		if err := enableEmergencyLogin(sshRunner); err != nil {
+		if err := enableEmergencyLogin(client.name, sshRunner); err != nil {
 			return nil, errors.Wrap(err, "Error enabling emergency login")
 		}
 	} else {
-		if err := disableEmergencyLogin(sshRunner); err != nil {
+		if err := disableEmergencyLogin(client.name, sshRunner); err != nil {
 			return nil, errors.Wrap(err, "Error deleting the password for core user")
 		}
 	}
 
-	// Post VM start immediately update SSH key and copy kubeconfig to instance
-	// dir and VM
-	if err := updateSSHKeyPair(sshRunner); err != nil {
-		return nil, errors.Wrap(err, "Error updating public key")
-	}
-
+	progress.phase(types.StartPhaseDiskResize)
 	// Trigger disk resize, this will be a no-op if no disk size change is needed
 	if err := growRootFileSystem(sshRunner, startConfig.Preset, startConfig.PersistentVolumeSize); err != nil {
 		return nil, errors.Wrap(err, "Error updating filesystem size")
@@ -444,6 +505,7 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 
 	// Start network time synchronization if `CRC_DEBUG_ENABLE_STOP_NTP` is not set
 	if stopNtp, _ := strconv.ParseBool(os.Getenv("CRC_DEBUG_ENABLE_STOP_NTP")); stopNtp {
+		progress.phase(types.StartPhaseTimeSync)
 		logging.Info("Stopping network time synchronization in CRC VM")
 		if _, _, err := sshRunner.RunPrivileged("Turning off the ntp server", "timedatectl set-ntp off"); err != nil {
 			return nil, errors.Wrap(err, "Failed to stop network time synchronization")
@@ -460,20 +522,24 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 
 	// Add nameserver to VM if provided by User
 	if startConfig.NameServer != "" {
+		progress.phase(types.StartPhaseNameServer)
 		if err = addNameServerToInstance(sshRunner, startConfig.NameServer); err != nil {
 			return nil, errors.Wrap(err, "Failed to add nameserver to the VM")
 		}
 	}
 	if startConfig.EnableSharedDirs {
-		if err := configureSharedDirs(vm, sshRunner); err != nil {
+		progress.phase(types.StartPhaseSharedDirs)
+		if err := configureSharedDirs(vm, sshRunner, userNetwork.GatewayIP.String()); err != nil {
 			return nil, err
 		}
 	}
 
-	if _, _, err := sshRunner.RunPrivileged("make root Podman socket accessible", "chmod 777 /run/podman/ /run/podman/podman.sock"); err != nil {
-		return nil, errors.Wrap(err, "Failed to change permissions to root podman socket")
+	progress.phase(types.StartPhasePodmanSocket)
+	if err := configurePodmanSocket(sshRunner, podmanSocket); err != nil {
+		return nil, err
 	}
 
+	progress.phase(types.StartPhaseDNS)
 	proxyConfig, err := getProxyConfig(vm.bundle)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error getting proxy configuration")
@@ -489,16 +555,22 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		SSHRunner: sshRunner,
 		IP:        instanceIP,
 		// TODO: should be more finegrained
-		BundleMetadata:  *vm.bundle,
-		NetworkMode:     client.networkMode(),
-		ModifyHostsFile: client.modifyHostsFile(),
+		BundleMetadata:       *vm.bundle,
+		NetworkMode:          client.networkMode(),
+		UserNetworkGatewayIP: userNetwork.GatewayIP.String(),
+		ModifyHostsFile:      client.modifyHostsFile(),
+	}
+	if startConfig.AppsDomain != vm.bundle.ClusterInfo.AppsDomain {
+		servicePostStartConfig.CustomAppsDomain = startConfig.AppsDomain
 	}
+	servicePostStartConfig.PreviousCustomAppsDomain = customAppsDomain(client.name)
 
 	// Run the DNS server inside the VM
 	if err := dns.RunPostStart(servicePostStartConfig); err != nil {
 		return nil, errors.Wrap(err, "Error running post start")
 	}
 
+	progress.phase(types.StartPhaseDNSCheck)
 	// Check DNS lookup before starting the kubelet
 	logging.Info("Check internal and public DNS query...")
 	if !client.useVSock() {
@@ -524,6 +596,12 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		logging.Warn(fmt.Sprintf("Failed to query DNS from host: %v", err))
 	}
 
+	progress.phase(types.StartPhaseImageCache)
+	imageCacheConfigured, err := client.configureImageCache(sshRunner)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to configure the image cache")
+	}
+
 	if vm.bundle.IsMicroshift() {
 		// **************************
 		//  END OF MICROSHIFT START CODE
@@ -533,26 +611,45 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		ocConfig.Context = "microshift"
 		ocConfig.Cluster = "microshift"
 
-		if err := startMicroshift(ctx, sshRunner, ocConfig, startConfig.PullSecret); err != nil {
+		progress.phase(types.StartPhaseStartMicroshift)
+		if err := startMicroshift(ctx, client.name, sshRunner, ocConfig, startConfig.PullSecret); err != nil {
 			return nil, err
 		}
 
 		if client.useVSock() {
+			progress.phase(types.StartPhaseRoutesController)
 			if err := ensureRoutesControllerIsRunning(sshRunner, ocConfig); err != nil {
 				return nil, err
 			}
 		}
+		progress.phase(types.StartPhaseWriteKubeconfig)
 		logging.Info("Adding microshift context to kubeconfig...")
-		if err := mergeKubeConfigFile(constants.KubeconfigFilePath); err != nil {
+		if err := mergeKubeConfigFile(client.name, constants.GetKubeconfigFilePath(client.name)); err != nil {
 			return nil, err
 		}
 
+		if client.registryEnabled() {
+			progress.phase(types.StartPhaseLocalRegistry)
+			if _, err := client.configureRegistry(sshRunner, ocConfig, startConfig.Preset, instanceIP); err != nil {
+				logging.Warnf("Failed to configure the local registry: %v", err)
+			}
+		}
+
+		if !startConfig.PostStart.IsEmpty() {
+			progress.phase(types.StartPhasePostStart)
+			if err := applyPostStartActions(sshRunner, ocConfig, startConfig.PostStart); err != nil {
+				return nil, errors.Wrap(err, "Failed to apply the post-start actions")
+			}
+		}
+
 		return &types.StartResult{
-			ClusterConfig: types.ClusterConfig{ClusterType: startConfig.Preset},
-			Status:        vmState,
+			ClusterConfig:  types.ClusterConfig{ClusterType: startConfig.Preset},
+			Status:         vmState,
+			PostStartHooks: client.runPostStartHooks(ctx, progress, sshRunner, ocConfig),
 		}, nil
 	}
 
+	progress.phase(types.StartPhaseKubeletCerts)
 	// Check the certs validity inside the vm
 	logging.Info("Verifying validity of the kubelet certificates...")
 	certsExpired, err := cluster.CheckCertsValidity(sshRunner)
@@ -560,6 +657,7 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		return nil, errors.Wrap(err, "Failed to check certificate validity")
 	}
 
+	progress.phase(types.StartPhaseStartKubelet)
 	logging.Info("Starting kubelet service")
 	sd := systemd.NewInstanceSystemdCommander(sshRunner)
 	if err := sd.Start("kubelet"); err != nil {
@@ -568,86 +666,145 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 
 	ocConfig := oc.UseOCWithSSH(sshRunner)
 
+	progress.phase(types.StartPhaseCertRenewal)
 	if err := cluster.ApproveCSRAndWaitForCertsRenewal(ctx, sshRunner, ocConfig, certsExpired[cluster.KubeletClientCert], certsExpired[cluster.KubeletServerCert], certsExpired[cluster.AggregatorClientCert]); err != nil {
 		logBundleDate(vm.bundle)
 		return nil, errors.Wrap(err, "Failed to renew TLS certificates: please check if a newer CRC release is available")
 	}
 
+	progress.phase(types.StartPhaseWaitAPIServer)
 	if err := cluster.WaitForAPIServer(ctx, ocConfig); err != nil {
 		return nil, errors.Wrap(err, "Error waiting for apiserver")
 	}
 
+	progress.phase(types.StartPhaseClusterProxy)
 	if err := ensureProxyIsConfiguredInOpenShift(ctx, ocConfig, sshRunner, proxyConfig); err != nil {
 		return nil, errors.Wrap(err, "Failed to update cluster proxy configuration")
 	}
 
+	progress.phase(types.StartPhaseMCOLease)
 	if err := cluster.DeleteMCOLeaderLease(ctx, ocConfig); err != nil {
 		return nil, err
 	}
 
+	progress.phase(types.StartPhasePullSecret)
 	if err := cluster.EnsurePullSecretPresentInTheCluster(ctx, ocConfig, startConfig.PullSecret); err != nil {
 		return nil, errors.Wrap(err, "Failed to update cluster pull secret")
 	}
 
-	if err := cluster.EnsureSSHKeyPresentInTheCluster(ctx, ocConfig, constants.GetPublicKeyPath()); err != nil {
+	progress.phase(types.StartPhaseClusterSSHKey)
+	if err := cluster.EnsureSSHKeyPresentInTheCluster(ctx, ocConfig, constants.GetPublicKeyPath(client.name)); err != nil {
 		return nil, errors.Wrap(err, "Failed to update ssh public key to machine config")
 	}
 
-	if err := cluster.UpdateUserPasswords(ctx, ocConfig, startConfig.KubeAdminPassword, startConfig.DeveloperPassword); err != nil {
+	progress.phase(types.StartPhaseUserPasswords)
+	if err := cluster.UpdateUserPasswords(ctx, ocConfig, client.name, startConfig.KubeAdminPassword, startConfig.DeveloperPassword); err != nil {
 		return nil, errors.Wrap(err, "Failed to update kubeadmin user password")
 	}
 
+	progress.phase(types.StartPhaseClusterID)
 	if err := cluster.EnsureClusterIDIsNotEmpty(ctx, ocConfig); err != nil {
 		return nil, errors.Wrap(err, "Failed to update cluster ID")
 	}
 
+	if imageCacheConfigured {
+		if err := client.configureImageDigestMirrorSet(sshRunner, ocConfig); err != nil {
+			return nil, errors.Wrap(err, "Failed to configure the mirrors of the image cache")
+		}
+	}
+
+	if startConfig.AppsDomain != "" || servingCerts.ingress != nil || startConfig.TrustClusterCA || ingressConfigured(client.name) {
+		progress.phase(types.StartPhaseAppsDomain)
+		previousIngressCA, _ := os.ReadFile(constants.GetIngressCACertPath(client.name))
+		if err := configureIngress(ctx, client.name, ocConfig, sshRunner, startConfig.AppsDomain, servingCerts.ingress, startConfig.TrustClusterCA, vm.bundle); err != nil {
+			return nil, errors.Wrap(err, "Failed to configure the ingress")
+		}
+		if ingressCA, _ := os.ReadFile(constants.GetIngressCACertPath(client.name)); startConfig.TrustClusterCA && !bytes.Equal(previousIngressCA, ingressCA) {
+			logging.Warn("The router CA of the instance changed, run 'crc setup' to add it to the system trust store")
+		}
+		if client.useVSock() && servicePostStartConfig.CustomAppsDomain != "" {
+			if err := addAppsDomainZone(startConfig.AppsDomain, userNetwork.InstanceIP); err != nil {
+				logging.Warnf("The names of %s will only resolve in the instance after a restart of the daemon: %v", startConfig.AppsDomain, err)
+			}
+		}
+	}
+
 	if client.useVSock() {
+		progress.phase(types.StartPhaseRoutesController)
 		if err := ensureRoutesControllerIsRunning(sshRunner, ocConfig); err != nil {
 			return nil, err
 		}
 	}
 
 	if client.monitoringEnabled() {
+		progress.phase(types.StartPhaseMonitoring)
 		logging.Info("Enabling cluster monitoring operator...")
 		if err := cluster.StartMonitoring(ocConfig); err != nil {
 			return nil, errors.Wrap(err, "Cannot start monitoring stack")
 		}
 	}
 
-	if err := updateKubeconfig(ctx, ocConfig, sshRunner, vm.bundle.GetKubeConfigPath()); err != nil {
+	progress.phase(types.StartPhaseUpdateKubeconfig)
+	if err := updateKubeconfig(ctx, ocConfig, sshRunner, vm.bundle.GetKubeConfigPath(), constants.GetKubeconfigFilePath(client.name)); err != nil {
 		return nil, errors.Wrap(err, "Failed to update kubeconfig file")
 	}
 
+	if servingCerts.apiServer != nil || apiServerCertificateConfigured(client.name) {
+		progress.phase(types.StartPhaseAPIServerCert)
+		if err := configureAPIServerCertificate(ctx, client.name, ocConfig, sshRunner, servingCerts.apiServer, vm.bundle); err != nil {
+			return nil, errors.Wrap(err, "Failed to configure the API server certificate")
+		}
+	}
+
+	progress.phase(types.StartPhaseClusterStable)
 	logging.Infof("Starting %s instance... [waiting for the cluster to stabilize]", startConfig.Preset)
-	if err := cluster.WaitForClusterStable(ctx, instanceIP, constants.KubeconfigFilePath, proxyConfig); err != nil {
+	if err := cluster.WaitForClusterStable(ctx, instanceIP, constants.GetKubeconfigFilePath(client.name), proxyConfig); err != nil {
 		logging.Warnf("Cluster is not ready: %v", err)
 	}
 
+	progress.phase(types.StartPhasePullSecretDisk)
 	if err := cluster.WaitForPullSecretPresentOnInstanceDisk(ctx, sshRunner); err != nil {
 		return nil, errors.Wrap(err, "Failed to update pull secret on the disk")
 	}
 
+	progress.phase(types.StartPhaseProxyPropagation)
 	waitForProxyPropagation(ctx, ocConfig, proxyConfig)
 
-	clusterConfig, err := getClusterConfig(vm.bundle)
+	progress.phase(types.StartPhaseWriteKubeconfig)
+	clusterConfig, err := getClusterConfig(client.name, vm.bundle)
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot get cluster configuration")
 	}
 
-	logging.Info("Adding crc-admin and crc-developer contexts to kubeconfig...")
-	if err := writeKubeconfig(instanceIP, clusterConfig, startConfig.IngressHTTPSPort); err != nil {
+	logging.Infof("Adding %s and %s contexts to kubeconfig...", adminContext(client.name), developerContext(client.name))
+	if err := writeKubeconfig(client.name, instanceIP, clusterConfig, appsDomain(client.name, vm.bundle), startConfig.IngressHTTPSPort); err != nil {
 		logging.Errorf("Cannot update kubeconfig: %v", err)
 	}
 
+	if client.registryEnabled() {
+		progress.phase(types.StartPhaseLocalRegistry)
+		if _, err := client.configureRegistry(sshRunner, ocConfig, startConfig.Preset, instanceIP); err != nil {
+			logging.Warnf("Failed to configure the local registry: %v", err)
+		}
+	}
+
+	if !startConfig.PostStart.IsEmpty() {
+		progress.phase(types.StartPhasePostStart)
+		if err := applyPostStartActions(sshRunner, ocConfig, startConfig.PostStart); err != nil {
+			return nil, errors.Wrap(err, "Failed to apply the post-start actions")
+		}
+	}
+
 	return &types.StartResult{
 		KubeletStarted: true,
 		ClusterConfig:  *clusterConfig,
 		Status:         vmState,
+		PostStartHooks: client.runPostStartHooks(ctx, progress, sshRunner, ocConfig),
 	}, nil
 }
 
 func (client *client) IsRunning() (bool, error) {
-	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	vm, err := client.loadVirtualMachine()
 	if err != nil {
 		return false, errors.Wrap(err, "Cannot load machine")
 	}
@@ -666,6 +823,17 @@ func (client *client) IsRunning() (bool, error) {
 }
 
 func (client *client) validateStartConfig(startConfig types.StartConfig) error {
+	if startConfig.Preset == crcPreset.Microshift {
+		if startConfig.AppsDomain != "" {
+			return fmt.Errorf("A custom apps domain cannot be used with the %s preset", crcPreset.Microshift)
+		}
+		if startConfig.IngressTLSCertFile != "" || startConfig.APITLSCertFile != "" {
+			return fmt.Errorf("Custom serving certificates cannot be used with the %s preset", crcPreset.Microshift)
+		}
+		if len(startConfig.PostStart.Operators) > 0 {
+			return fmt.Errorf("Operators cannot be installed with the %s preset", crcPreset.Microshift)
+		}
+	}
 	if client.monitoringEnabled() && startConfig.Memory < minimumMemoryForMonitoring {
 		return fmt.Errorf("Too little memory (%s) allocated to the virtual machine to start the monitoring stack, %s is the minimum",
 			units.BytesSize(float64(startConfig.Memory.ToBytes())),
@@ -700,14 +868,18 @@ func createHost(machineConfig config.MachineConfig, preset crcPreset.Preset) err
 	}
 
 	logging.Info("Generating new SSH key pair...")
-	if err := crcssh.GenerateSSHKey(constants.GetPrivateKeyPath()); err != nil {
+	if err := crcssh.GenerateSSHKey(constants.GetPrivateKeyPath(machineConfig.Name)); err != nil {
 		return fmt.Errorf("error generating ssh key pair: %w", err)
 	}
+	// the host keys of the new VM are regenerated and pinned on its first start
+	if err := os.Remove(constants.GetKnownHostsPath(machineConfig.Name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing the host key of the previous VM: %w", err)
+	}
 	if preset == crcPreset.OpenShift || preset == crcPreset.OKD {
-		if err := cluster.GenerateUserPassword(constants.GetKubeAdminPasswordPath(), "kubeadmin"); err != nil {
+		if err := cluster.GenerateUserPassword(constants.GetKubeAdminPasswordPath(machineConfig.Name), "kubeadmin"); err != nil {
 			return errors.Wrap(err, "Error generating new kubeadmin password")
 		}
-		if err = os.WriteFile(constants.GetDeveloperPasswordPath(), []byte(constants.DefaultDeveloperPassword), 0o600); err != nil {
+		if err = os.WriteFile(constants.GetDeveloperPasswordPath(machineConfig.Name), []byte(constants.DefaultDeveloperPassword), 0o600); err != nil {
 			return errors.Wrap(err, "Error writing developer password")
 		}
 	}
@@ -720,6 +892,7 @@ func createHost(machineConfig config.MachineConfig, preset crcPreset.Preset) err
 }
 
 func startHost(ctx context.Context, vm *virtualMachine) error {
+	vm.closeSSHConnections()
 	if err := vm.Driver.Start(); err != nil {
 		return fmt.Errorf("error in driver during machine start: %w", err)
 	}
@@ -752,8 +925,9 @@ func addNameServerToInstance(sshRunner *crcssh.Runner, ns string) error {
 	return nil
 }
 
-func enableEmergencyLogin(sshRunner *crcssh.Runner) error {
-	if crcos.FileExists(constants.PasswdFilePath) {
+func enableEmergencyLogin(name string, sshRunner *crcssh.Runner) error {
+	passwdFilePath := constants.GetPasswdFilePath(name)
+	if crcos.FileExists(passwdFilePath) {
 		return nil
 	}
 	charset := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -761,23 +935,23 @@ func enableEmergencyLogin(sshRunner *crcssh.Runner) error {
 	for i := range b {
 		b[i] = charset[rand.Intn(len(charset))] //nolint:gosec
 	}
-	if err := os.WriteFile(constants.PasswdFilePath, b, 0o600); err != nil {
+	if err := os.WriteFile(passwdFilePath, b, 0o600); err != nil {
 		return err
 	}
-	logging.Infof("Emergency login password for core user is stored to %s", constants.PasswdFilePath)
+	logging.Infof("Emergency login password for core user is stored to %s", passwdFilePath)
 	_, _, err := sshRunner.Run(fmt.Sprintf("sudo passwd core -f --unlock && echo %s | sudo passwd core --stdin", b))
 	return err
 }
 
-func disableEmergencyLogin(sshRunner *crcssh.Runner) error {
-	defer os.Remove(constants.PasswdFilePath)
+func disableEmergencyLogin(name string, sshRunner *crcssh.Runner) error {
+	defer os.Remove(constants.GetPasswdFilePath(name))
 	_, _, err := sshRunner.RunPrivileged("disable core user password", "passwd", "--lock", "core")
 	return err
 }
 
-func updateSSHKeyPair(sshRunner *crcssh.Runner) error {
+func updateSSHKeyPair(name string, sshRunner *crcssh.Runner) error {
 	// Read generated public key
-	publicKey, err := os.ReadFile(constants.GetPublicKeyPath())
+	publicKey, err := os.ReadFile(constants.GetPublicKeyPath(name))
 	if err != nil {
 		return err
 	}
@@ -799,7 +973,7 @@ func updateSSHKeyPair(sshRunner *crcssh.Runner) error {
 }
 
 func copyKubeconfigFileWithUpdatedUserClientCertAndKey(selfSignedCAKey *rsa.PrivateKey, selfSignedCACert *x509.Certificate, srcKubeConfigPath, dstKubeConfigPath string) error {
-	if _, err := os.Stat(constants.KubeconfigFilePath); err == nil {
+	if _, err := os.Stat(dstKubeConfigPath); err == nil {
 		return nil
 	}
 	clientKey, clientCert, err := crctls.GenerateClientCertificate(selfSignedCAKey, selfSignedCACert)
@@ -872,25 +1046,26 @@ func ensureRoutesNetworkPolicy(sshRunner *crcssh.Runner, ocConfig oc.Config) err
 	return err
 }
 
-func updateKubeconfig(ctx context.Context, ocConfig oc.Config, sshRunner *crcssh.Runner, kubeconfigFilePath string) error {
+func updateKubeconfig(ctx context.Context, ocConfig oc.Config, sshRunner *crcssh.Runner, kubeconfigFilePath string, instanceKubeconfigFilePath string) error {
 	selfSignedCAKey, selfSignedCACert, err := crctls.GetSelfSignedCA()
 	if err != nil {
 		return errors.Wrap(err, "Not able to generate root CA key and Cert")
 	}
-	if err := copyKubeconfigFileWithUpdatedUserClientCertAndKey(selfSignedCAKey, selfSignedCACert, kubeconfigFilePath, constants.KubeconfigFilePath); err != nil {
-		return errors.Wrapf(err, "Failed to copy kubeconfig file: %s", constants.KubeconfigFilePath)
+	if err := copyKubeconfigFileWithUpdatedUserClientCertAndKey(selfSignedCAKey, selfSignedCACert, kubeconfigFilePath, instanceKubeconfigFilePath); err != nil {
+		return errors.Wrapf(err, "Failed to copy kubeconfig file: %s", instanceKubeconfigFilePath)
 	}
-	adminClientCA, err := adminClientCertificate(constants.KubeconfigFilePath)
+	adminClientCA, err := adminClientCertificate(instanceKubeconfigFilePath)
 	if err != nil {
 		return errors.Wrap(err, "Not able to get user CA")
 	}
-	if err := cluster.EnsureGeneratedClientCAPresentInTheCluster(ctx, ocConfig, sshRunner, selfSignedCACert, adminClientCA); err != nil {
+	if err := cluster.EnsureGeneratedClientCAPresentInTheCluster(ctx, ocConfig, sshRunner, selfSignedCACert, adminClientCA, instanceKubeconfigFilePath); err != nil {
 		return errors.Wrap(err, "Failed to update user CA to cluster")
 	}
 	return nil
 }
 
-func startMicroshift(ctx context.Context, sshRunner *crcssh.Runner, ocConfig oc.Config, pullSec cluster.PullSecretLoader) error {
+func startMicroshift(ctx context.Context, name string, sshRunner *crcssh.Runner, ocConfig oc.Config, pullSec cluster.PullSecretLoader) error {
+	kubeconfigFilePath := constants.GetKubeconfigFilePath(name)
 	logging.Infof("Starting Microshift service... [takes around 1min]")
 	if err := ensurePullSecretPresentInVM(sshRunner, pullSec); err != nil {
 		return err
@@ -898,20 +1073,20 @@ func startMicroshift(ctx context.Context, sshRunner *crcssh.Runner, ocConfig oc.
 	if _, _, err := sshRunner.RunPrivileged("Starting microshift service", "systemctl", "start", "microshift"); err != nil {
 		return err
 	}
-	if err := sshRunner.CopyFileFromVM(fmt.Sprintf("/var/lib/microshift/resources/kubeadmin/api%s/kubeconfig", constants.ClusterDomain), constants.KubeconfigFilePath, 0o600); err != nil {
+	if err := sshRunner.CopyFileFromVM(fmt.Sprintf("/var/lib/microshift/resources/kubeadmin/api%s/kubeconfig", constants.ClusterDomain), kubeconfigFilePath, 0o600); err != nil {
 		return err
 	}
-	if err := sshRunner.CopyFile(constants.KubeconfigFilePath, "/opt/kubeconfig", 0o644); err != nil {
+	if err := sshRunner.CopyFile(kubeconfigFilePath, "/opt/kubeconfig", 0o644); err != nil {
 		return err
 	}
 
 	return cluster.WaitForAPIServer(ctx, ocConfig)
 }
 
-func checkMachineInstanceDir() error {
+func checkMachineInstanceDir(name string) error {
 	requiredFiles := []string{
-		constants.GetPrivateKeyPath(),
-		constants.GetPublicKeyPath(),
+		constants.GetPrivateKeyPath(name),
+		constants.GetPublicKeyPath(name),
 	}
 	for _, filePath := range requiredFiles {
 		if !crcos.FileExists(filePath) {
@@ -0,0 +1,88 @@
+package machine
+
+import (
+	"context"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+)
+
+// StartProgressReporter is notified each time a phase of client.Start
+// begins or ends when the start was requested with a context returned by
+// WithStartProgressReporter
+type StartProgressReporter interface {
+	StartProgress(progress types.StartProgress)
+}
+
+// StartProgressReporterFunc is an adapter to use a function as a StartProgressReporter
+type StartProgressReporterFunc func(progress types.StartProgress)
+
+func (f StartProgressReporterFunc) StartProgress(progress types.StartProgress) {
+	f(progress)
+}
+
+type startProgressReporterKey struct{}
+
+// WithStartProgressReporter returns a copy of ctx which makes Start report its progress to reporter
+func WithStartProgressReporter(ctx context.Context, reporter StartProgressReporter) context.Context {
+	return context.WithValue(ctx, startProgressReporterKey{}, reporter)
+}
+
+func startProgressReporterFromContext(ctx context.Context) StartProgressReporter {
+	if reporter, ok := ctx.Value(startProgressReporterKey{}).(StartProgressReporter); ok {
+		return reporter
+	}
+	return nil
+}
+
+// startProgress keeps track of the phase currently running. Starting a new
+// phase marks the previous one as completed.
+type startProgress struct {
+	reporter StartProgressReporter
+	current  types.StartPhase
+	started  time.Time
+	now      func() time.Time
+}
+
+func newStartProgress(ctx context.Context) *startProgress {
+	return &startProgress{
+		reporter: startProgressReporterFromContext(ctx),
+		now:      time.Now,
+	}
+}
+
+func (p *startProgress) phase(phase types.StartPhase) {
+	p.finish(nil)
+	p.current = phase
+	p.started = p.now()
+	p.report(types.PhaseRunning, 0, nil)
+}
+
+// finish marks the running phase as completed, or as failed when err is not nil
+func (p *startProgress) finish(err error) {
+	if p.current == "" {
+		return
+	}
+	elapsed := p.now().Sub(p.started)
+	if err != nil {
+		p.report(types.PhaseFailed, elapsed, err)
+	} else {
+		p.report(types.PhaseCompleted, elapsed, nil)
+	}
+	p.current = ""
+}
+
+func (p *startProgress) report(state types.PhaseState, elapsed time.Duration, err error) {
+	if p.reporter == nil {
+		return
+	}
+	progress := types.StartProgress{
+		Phase:   p.current,
+		State:   state,
+		Elapsed: elapsed,
+	}
+	if err != nil {
+		progress.Error = err.Error()
+	}
+	p.reporter.StartProgress(progress)
+}
@@ -0,0 +1,43 @@
+package machine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartProgress(t *testing.T) {
+	var reported []types.StartProgress
+	ctx := WithStartProgressReporter(context.Background(), StartProgressReporterFunc(func(progress types.StartProgress) {
+		reported = append(reported, progress)
+	}))
+
+	now := time.Unix(0, 0)
+	progress := newStartProgress(ctx)
+	progress.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	progress.phase(types.StartPhaseStartVM)
+	progress.phase(types.StartPhaseWaitSSH)
+	progress.finish(errors.New("ssh timeout"))
+	progress.finish(nil)
+
+	assert.Equal(t, []types.StartProgress{
+		{Phase: types.StartPhaseStartVM, State: types.PhaseRunning},
+		{Phase: types.StartPhaseStartVM, State: types.PhaseCompleted, Elapsed: time.Second},
+		{Phase: types.StartPhaseWaitSSH, State: types.PhaseRunning},
+		{Phase: types.StartPhaseWaitSSH, State: types.PhaseFailed, Elapsed: time.Second, Error: "ssh timeout"},
+	}, reported)
+}
+
+func TestStartProgressWithoutReporter(t *testing.T) {
+	progress := newStartProgress(context.Background())
+	progress.phase(types.StartPhaseBundle)
+	progress.finish(nil)
+}
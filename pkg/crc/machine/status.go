@@ -21,7 +21,7 @@ import (
 type openShiftStatusSupplierFunc func(context.Context, string) types.OpenshiftStatus
 
 func (client *client) Status() (*types.ClusterStatusResult, error) {
-	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	vm, err := client.loadVirtualMachine()
 	if err != nil {
 		if errors.Is(err, errMissingHost(client.name)) {
 			return &types.ClusterStatusResult{
@@ -49,9 +49,13 @@ func (client *client) Status() (*types.ClusterStatusResult, error) {
 	ramSize, ramUse := client.getRAMStatus(vm)
 	diskSize, diskUse := client.getDiskDetails(vm)
 	pvSize, pvUse := client.getPVCSize(vm)
-	var openShiftStatusSupplier = getOpenShiftStatus
+	var statusFunc = getOpenShiftStatus
 	if vm.bundle.IsMicroshift() {
-		openShiftStatusSupplier = getMicroShiftStatus
+		statusFunc = getMicroShiftStatus
+	}
+	kubeconfigFilePath := constants.GetKubeconfigFilePath(client.name)
+	openShiftStatusSupplier := func(ctx context.Context, ip string) types.OpenshiftStatus {
+		return statusFunc(ctx, ip, kubeconfigFilePath)
 	}
 
 	return createClusterStatusResult(vmStatus, vm.bundle.GetBundleType(), vm.bundle.GetVersion(), ip, diskSize, diskUse, ramSize, ramUse, pvUse, pvSize, openShiftStatusSupplier)
@@ -92,7 +96,7 @@ func createClusterStatusResult(vmStatus state.State, bundleType preset.Preset, v
 }
 
 func (client *client) GetClusterLoad() (*types.ClusterLoadResult, error) {
-	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	vm, err := client.loadVirtualMachine()
 	if err != nil {
 		if errors.Is(err, errMissingHost(client.name)) {
 			return &types.ClusterLoadResult{
@@ -147,8 +151,8 @@ func (client *client) getDiskDetails(vm *virtualMachine) (strongunits.B, strongu
 	return disk.([]strongunits.B)[0], disk.([]strongunits.B)[1]
 }
 
-func getOpenShiftStatus(ctx context.Context, ip string) types.OpenshiftStatus {
-	status, err := cluster.GetClusterOperatorsStatus(ctx, ip, constants.KubeconfigFilePath)
+func getOpenShiftStatus(ctx context.Context, ip string, kubeconfigFilePath string) types.OpenshiftStatus {
+	status, err := cluster.GetClusterOperatorsStatus(ctx, ip, kubeconfigFilePath)
 	if err != nil {
 		logging.Debugf("cannot get OpenShift status: %v", err)
 		return types.OpenshiftUnreachable
@@ -156,8 +160,8 @@ func getOpenShiftStatus(ctx context.Context, ip string) types.OpenshiftStatus {
 	return getStatus(status)
 }
 
-func getMicroShiftStatus(ctx context.Context, ip string) types.OpenshiftStatus {
-	status, err := cluster.GetClusterNodeStatus(ctx, ip, constants.KubeconfigFilePath)
+func getMicroShiftStatus(ctx context.Context, ip string, kubeconfigFilePath string) types.OpenshiftStatus {
+	status, err := cluster.GetClusterNodeStatus(ctx, ip, kubeconfigFilePath)
 	if err != nil {
 		logging.Debugf("failed to get microshift node status: %v", err)
 		return types.OpenshiftUnreachable
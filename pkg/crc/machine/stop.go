@@ -9,21 +9,22 @@ import (
 )
 
 func (client *client) Stop() (state.State, error) {
-	defer func(input, output string) {
-		err := cleanKubeconfig(input, output)
+	defer func() {
+		err := cleanGlobalKubeconfig(client.name)
 		if err != nil && !errors.Is(err, os.ErrNotExist) {
 			logging.Warnf("Failed to remove crc contexts from kubeconfig: %v", err)
 		}
-	}(getGlobalKubeConfigPath(), getGlobalKubeConfigPath())
+	}()
 	if running, _ := client.IsRunning(); !running {
 		return state.Error, errors.New("Instance is already stopped")
 	}
-	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	vm, err := client.loadVirtualMachine()
 	if err != nil {
 		return state.Error, errors.Wrap(err, "Cannot load machine")
 	}
 	defer vm.Close()
 	logging.Info("Stopping the instance, this may take a few minutes...")
+	vm.closeSSHConnections()
 	if err := vm.Stop(); err != nil {
 		status, stateErr := vm.State()
 		if stateErr != nil {
@@ -37,7 +38,11 @@ func (client *client) Stop() (state.State, error) {
 	}
 	// In case usermode networking make sure all the port bind on host should be released
 	if client.useVSock() {
-		return status, unexposePorts()
+		userNetwork, err := client.userNetwork()
+		if err != nil {
+			return status, err
+		}
+		return status, unexposePorts(userNetwork.InstanceIP.String())
 	}
 	return status, nil
 }
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 )
 
@@ -111,6 +113,22 @@ func (s *Synchronized) cancelUnlocked(timeout time.Duration) error {
 	}
 }
 
+// CancelStart aborts the ongoing start and waits until it is fully cancelled
+func (s *Synchronized) CancelStart() error {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	if s.currentStateUnlocked() != Starting {
+		return errors.New("cluster is not starting")
+	}
+	if err := s.cancelUnlocked(startCancelTimeout); err != nil {
+		return err
+	}
+	s.currentState = Idle
+	s.startCancel = nil
+	return nil
+}
+
 func (s *Synchronized) prepareStopDelete(state State) error {
 	s.stateLock.Lock()
 	defer s.stateLock.Unlock()
@@ -197,3 +215,68 @@ func (s *Synchronized) GenerateBundle(forceStop bool) error {
 func (s *Synchronized) GetPreset() crcPreset.Preset {
 	return s.underlying.GetPreset()
 }
+
+func (s *Synchronized) CreateSnapshot(name string) (*types.Snapshot, error) {
+	if s.CurrentState() != Idle {
+		return nil, errors.New("cluster is busy")
+	}
+	return s.underlying.CreateSnapshot(name)
+}
+
+func (s *Synchronized) ListSnapshots() ([]types.Snapshot, error) {
+	return s.underlying.ListSnapshots()
+}
+
+func (s *Synchronized) RestoreSnapshot(name string) error {
+	if s.CurrentState() != Idle {
+		return errors.New("cluster is busy")
+	}
+	return s.underlying.RestoreSnapshot(name)
+}
+
+func (s *Synchronized) DeleteSnapshot(name string) error {
+	if s.CurrentState() != Idle {
+		return errors.New("cluster is busy")
+	}
+	return s.underlying.DeleteSnapshot(name)
+}
+
+func (s *Synchronized) ListBundles() ([]types.CachedBundle, error) {
+	return s.underlying.ListBundles()
+}
+
+func (s *Synchronized) GetBundle(bundleName string) (*bundle.CrcBundleInfo, error) {
+	return s.underlying.GetBundle(bundleName)
+}
+
+func (s *Synchronized) DownloadBundle(ctx context.Context, bundleURI string) (*bundle.CrcBundleInfo, error) {
+	return s.underlying.DownloadBundle(ctx, bundleURI)
+}
+
+func (s *Synchronized) RemoveBundle(bundleName string) error {
+	return s.underlying.RemoveBundle(bundleName)
+}
+
+func (s *Synchronized) ListPortForwards() ([]network.PortForward, error) {
+	return s.underlying.ListPortForwards()
+}
+
+func (s *Synchronized) AddPortForward(portForward network.PortForward) error {
+	return s.underlying.AddPortForward(portForward)
+}
+
+func (s *Synchronized) RemovePortForward(portForward network.PortForward) error {
+	return s.underlying.RemovePortForward(portForward)
+}
+
+func (s *Synchronized) EnableRegistry() (*types.Registry, error) {
+	return s.underlying.EnableRegistry()
+}
+
+func (s *Synchronized) Diagnose(ctx context.Context, archivePath string) (*types.Diagnostics, error) {
+	return s.underlying.Diagnose(ctx, archivePath)
+}
+
+func (s *Synchronized) DisableRegistry() error {
+	return s.underlying.DisableRegistry()
+}
@@ -6,8 +6,10 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/stretchr/testify/assert"
 )
@@ -107,6 +109,33 @@ func TestCancelStart(t *testing.T) {
 	assert.Equal(t, Idle, syncMachine.CurrentState())
 }
 
+func TestCancelStartWithoutDeleting(t *testing.T) {
+	isRunning := make(chan struct{}, 1)
+	waitingMachine := &waitingMachine{
+		isRunning:       isRunning,
+		startCompleteCh: make(chan struct{}, 1),
+	}
+	syncMachine := NewSynchronizedMachine(waitingMachine)
+	assert.EqualError(t, syncMachine.CancelStart(), "cluster is not starting")
+
+	lock := &sync.WaitGroup{}
+	lock.Add(1)
+	go func() {
+		defer lock.Done()
+		_, err := syncMachine.Start(context.Background(), types.StartConfig{})
+		assert.EqualError(t, err, "context canceled")
+	}()
+
+	<-isRunning
+	assert.NoError(t, syncMachine.CancelStart())
+	lock.Wait()
+
+	assert.Equal(t, Idle, syncMachine.CurrentState())
+	waitingMachine.startCompleteCh <- struct{}{}
+	_, err := syncMachine.Start(context.Background(), types.StartConfig{})
+	assert.NoError(t, err)
+}
+
 type waitingMachine struct {
 	isRunning        chan struct{}
 	startCompleteCh  chan struct{}
@@ -178,3 +207,59 @@ func (m *waitingMachine) GetPreset() crcPreset.Preset {
 func (m *waitingMachine) GetClusterLoad() (*types.ClusterLoadResult, error) {
 	return nil, errors.New("not implemented")
 }
+
+func (m *waitingMachine) CreateSnapshot(_ string) (*types.Snapshot, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) ListSnapshots() ([]types.Snapshot, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) RestoreSnapshot(_ string) error {
+	return errors.New("not implemented")
+}
+
+func (m *waitingMachine) DeleteSnapshot(_ string) error {
+	return errors.New("not implemented")
+}
+
+func (m *waitingMachine) ListBundles() ([]types.CachedBundle, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) GetBundle(_ string) (*bundle.CrcBundleInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) DownloadBundle(_ context.Context, _ string) (*bundle.CrcBundleInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) RemoveBundle(_ string) error {
+	return errors.New("not implemented")
+}
+
+func (m *waitingMachine) ListPortForwards() ([]network.PortForward, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) AddPortForward(_ network.PortForward) error {
+	return errors.New("not implemented")
+}
+
+func (m *waitingMachine) RemovePortForward(_ network.PortForward) error {
+	return errors.New("not implemented")
+}
+
+func (m *waitingMachine) EnableRegistry() (*types.Registry, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) DisableRegistry() error {
+	return errors.New("not implemented")
+}
+
+func (m *waitingMachine) Diagnose(_ context.Context, _ string) (*types.Diagnostics, error) {
+	return nil, errors.New("not implemented")
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/crc-org/crc/v2/pkg/crc/cluster"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/network/httpproxy"
@@ -47,8 +50,50 @@ type StartConfig struct {
 	// Persistent volume size
 	PersistentVolumeSize int
 
+	// Custom apps domain, the one of the bundle is used when empty
+	AppsDomain string
+
+	// User provided serving certificates and their CA, the cluster ones are used when empty
+	IngressTLSCertFile string
+	IngressTLSKeyFile  string
+	APITLSCertFile     string
+	APITLSKeyFile      string
+	TLSCAFile          string
+
+	// Sign the router certificate with a CA of the instance, for 'crc setup' to add it to the system trust store
+	TrustClusterCA bool
+
 	// Enable bundle quay fallback
 	EnableBundleQuayFallback bool
+
+	// Location of the mirror of the default bundles
+	BundleMirror string
+
+	// Actions applied to the cluster at the end of the start
+	PostStart PostStartActions
+}
+
+// PostStartActions are applied to the cluster each time it's started
+type PostStartActions struct {
+	// Namespaces are created when they don't exist
+	Namespaces []string
+	// Manifests are absolute paths or URLs of files given to 'oc apply'
+	Manifests []string
+	// Operators are installed from the catalogs of the cluster
+	Operators []Operator
+}
+
+func (actions PostStartActions) IsEmpty() bool {
+	return len(actions.Namespaces) == 0 && len(actions.Manifests) == 0 && len(actions.Operators) == 0
+}
+
+// Operator is an OLM package the cluster subscribes to
+type Operator struct {
+	Name string
+	// Channel is the default channel of the package when empty
+	Channel   string
+	Source    string
+	Namespace string
 }
 
 type ClusterConfig struct {
@@ -66,6 +111,117 @@ type StartResult struct {
 	Status         state.State
 	ClusterConfig  ClusterConfig
 	KubeletStarted bool
+	PostStartHooks []HookResult
+}
+
+// Registry describes how to push images to the registry of the cluster from the host
+type Registry struct {
+	// Address is the host and port of the registry on the host
+	Address string
+	// ClusterAddress is the host and port of the registry in the image references of the pods
+	ClusterAddress string
+	// RequiresLogin is true when pushing requires a token of the cluster user
+	RequiresLogin bool
+}
+
+// Diagnostics describes the archive created by 'crc diagnose'
+type Diagnostics struct {
+	// Path is the location of the archive
+	Path string
+	// Failures lists what could not be collected, such as the journal of a stopped instance
+	Failures []string
+}
+
+// HookResult is the outcome of a post-start hook
+type HookResult struct {
+	Name    string
+	LogFile string
+	// Error is empty when the hook succeeded
+	Error string
+}
+
+// StartPhase identifies one of the steps run by client.Start
+type StartPhase string
+
+const (
+	StartPhaseBundle           StartPhase = "bundle"
+	StartPhaseCreateVM         StartPhase = "create-vm"
+	StartPhaseStartVM          StartPhase = "start-vm"
+	StartPhaseWaitSSH          StartPhase = "wait-ssh"
+	StartPhaseUpdateSSHKey     StartPhase = "update-ssh-key"
+	StartPhaseEmergencyLogin   StartPhase = "emergency-login"
+	StartPhaseDiskResize       StartPhase = "disk-resize"
+	StartPhaseTimeSync         StartPhase = "time-sync"
+	StartPhaseNameServer       StartPhase = "nameserver"
+	StartPhaseSharedDirs       StartPhase = "shared-dirs"
+	StartPhasePodmanSocket     StartPhase = "podman-socket"
+	StartPhaseDNS              StartPhase = "dns"
+	StartPhaseDNSCheck         StartPhase = "dns-check"
+	StartPhaseImageCache       StartPhase = "image-cache"
+	StartPhaseStartMicroshift  StartPhase = "start-microshift"
+	StartPhaseKubeletCerts     StartPhase = "kubelet-certs"
+	StartPhaseStartKubelet     StartPhase = "start-kubelet"
+	StartPhaseCertRenewal      StartPhase = "cert-renewal"
+	StartPhaseWaitAPIServer    StartPhase = "wait-apiserver"
+	StartPhaseClusterProxy     StartPhase = "cluster-proxy"
+	StartPhaseMCOLease         StartPhase = "mco-lease"
+	StartPhasePullSecret       StartPhase = "pull-secret"
+	StartPhaseClusterSSHKey    StartPhase = "cluster-ssh-key"
+	StartPhaseUserPasswords    StartPhase = "user-passwords"
+	StartPhaseClusterID        StartPhase = "cluster-id"
+	StartPhaseAppsDomain       StartPhase = "apps-domain"
+	StartPhaseAPIServerCert    StartPhase = "apiserver-cert"
+	StartPhaseRoutesController StartPhase = "routes-controller"
+	StartPhaseMonitoring       StartPhase = "monitoring"
+	StartPhaseUpdateKubeconfig StartPhase = "update-kubeconfig"
+	StartPhaseClusterStable    StartPhase = "cluster-stable"
+	StartPhasePullSecretDisk   StartPhase = "pull-secret-disk"
+	StartPhaseProxyPropagation StartPhase = "proxy-propagation"
+	StartPhaseWriteKubeconfig  StartPhase = "write-kubeconfig"
+	StartPhaseLocalRegistry    StartPhase = "local-registry"
+	StartPhasePostStart        StartPhase = "post-start"
+	StartPhasePostStartHooks   StartPhase = "post-start-hooks"
+)
+
+type PhaseState string
+
+const (
+	PhaseRunning   PhaseState = "Running"
+	PhaseCompleted PhaseState = "Completed"
+	PhaseFailed    PhaseState = "Failed"
+)
+
+// StartProgress is emitted each time a start phase begins or ends. Elapsed
+// is the time spent in the phase so far, it is serialized in milliseconds.
+type StartProgress struct {
+	Phase   StartPhase    `json:"phase"`
+	State   PhaseState    `json:"state"`
+	Elapsed time.Duration `json:"-"`
+	Error   string        `json:"error,omitempty"`
+}
+
+type startProgressJSON struct {
+	*startProgressFields
+	ElapsedMilliseconds int64 `json:"elapsedMs"`
+}
+
+type startProgressFields StartProgress
+
+func (p StartProgress) MarshalJSON() ([]byte, error) {
+	fields := startProgressFields(p)
+	return json.Marshal(startProgressJSON{
+		startProgressFields: &fields,
+		ElapsedMilliseconds: p.Elapsed.Milliseconds(),
+	})
+}
+
+func (p *StartProgress) UnmarshalJSON(data []byte) error {
+	progress := startProgressJSON{startProgressFields: (*startProgressFields)(p)}
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return err
+	}
+	p.Elapsed = time.Duration(progress.ElapsedMilliseconds) * time.Millisecond
+	return nil
 }
 
 type StopResult struct {
@@ -111,8 +267,33 @@ type ConsoleResult struct {
 }
 
 type ConnectionDetails struct {
-	IP          string
-	SSHPort     int
-	SSHUsername string
-	SSHKeys     []string
+	IP                   string
+	SSHPort              int
+	SSHUsername          string
+	SSHKeys              []string
+	KnownHostsFile       string
+	BundleKnownHostsFile string
+}
+
+type InstanceInfo struct {
+	Name          string
+	State         state.State
+	Preset        crcpreset.Preset
+	BundleName    string
+	BundleVersion string
+}
+
+type Snapshot struct {
+	Name             string
+	BundleName       string
+	OpenshiftVersion string
+	CreationTime     time.Time
+}
+
+type CachedBundle struct {
+	Name    string
+	Version string
+	Preset  crcpreset.Preset
+	Size    int64
+	UsedBy  []string
 }
@@ -19,6 +19,8 @@ type virtualMachine struct {
 	bundle *bundle.CrcBundleInfo
 	api    libmachine.API
 	vsock  bool
+
+	sshConnections *ssh.Pool
 }
 
 type MissingHostError struct {
@@ -69,7 +71,16 @@ func (vm *virtualMachine) Close() error {
 	return vm.api.Close()
 }
 
+// closeSSHConnections closes the SSH connection shared with the client, the
+// next runners connect to the restarted VM
+func (vm *virtualMachine) closeSSHConnections() {
+	if vm.sshConnections != nil {
+		vm.sshConnections.Close()
+	}
+}
+
 func (vm *virtualMachine) Remove() error {
+	vm.closeSSHConnections()
 	if err := vm.Driver.Remove(); err != nil {
 		return errors.Wrap(err, "Driver cannot remove machine")
 	}
@@ -108,5 +119,9 @@ func (vm *virtualMachine) SSHRunner() (*ssh.Runner, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ssh.CreateRunner(ip, vm.SSHPort(), constants.GetPrivateKeyPath(), constants.GetECDSAPrivateKeyPath(), vm.bundle.GetSSHKeyPath())
+	createRunner := ssh.CreateRunner
+	if vm.sshConnections != nil {
+		createRunner = vm.sshConnections.CreateRunner
+	}
+	return createRunner(ip, vm.SSHPort(), constants.GetKnownHostsPath(vm.name), vm.bundle.GetKnownHostsPath(), constants.GetPrivateKeyPath(vm.name), constants.GetECDSAPrivateKeyPath(vm.name), vm.bundle.GetSSHKeyPath())
 }
@@ -4,20 +4,25 @@ import (
 	"fmt"
 	"net"
 	"net/url"
-	"runtime"
 	"strconv"
+	"strings"
 
 	"github.com/containers/gvisor-tap-vsock/pkg/types"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
 	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/pkg/errors"
 )
 
-func exposePorts(preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint) error {
-	portsToExpose := vsockPorts(preset, ingressHTTPPort, ingressHTTPSPort)
+func exposePorts(name string, instanceIP string, preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint, portForwards []network.PortForward, podmanSocket podmanSocketConfig) error {
+	builtinPorts := vsockPorts(name, instanceIP, preset, ingressHTTPPort, ingressHTTPSPort, podmanSocket)
+	if err := network.CheckReservedHostPorts(portForwards, reservedHostPorts(builtinPorts)); err != nil {
+		return err
+	}
+	portsToExpose := append(builtinPorts, portForwardExposeRequests(instanceIP, portForwards)...)
 	daemonClient := daemonclient.New()
 	alreadyOpenedPorts, err := listOpenPorts(daemonClient)
 	if err != nil {
@@ -38,6 +43,37 @@ func exposePorts(preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint
 	return nil
 }
 
+// addAppsDomainZone adds the zone of appsDomain to the DNS server of the
+// daemon, the zones of the configuration are only read when the daemon starts
+func addAppsDomainZone(appsDomain string, instanceIP net.IP) error {
+	daemonClient := daemonclient.New()
+	zones, err := daemonClient.NetworkClient.ListDNS()
+	if err != nil {
+		return err
+	}
+	zone, err := appsDomainZone(zones, appsDomain, instanceIP)
+	if err != nil || zone == nil {
+		return err
+	}
+	return daemonClient.NetworkClient.AddDNS(zone)
+}
+
+// appsDomainZone returns the zone to add to the daemon for appsDomain, or nil
+// if it is already there. The daemon appends the zones added at runtime and
+// the first matching zone answers, so a zone containing appsDomain would hide it.
+func appsDomainZone(zones []types.Zone, appsDomain string, instanceIP net.IP) (*types.Zone, error) {
+	name := appsDomain + "."
+	for _, zone := range zones {
+		if zone.Name == name {
+			return nil, nil
+		}
+		if strings.HasSuffix(name, "."+zone.Name) {
+			return nil, fmt.Errorf("the %s zone of the daemon answers for %s", zone.Name, appsDomain)
+		}
+	}
+	return &types.Zone{Name: name, DefaultIP: instanceIP.To16()}, nil
+}
+
 func isOpened(exposed []types.ExposeRequest, port types.ExposeRequest) bool {
 	for _, alreadyOpenedPort := range exposed {
 		if port == alreadyOpenedPort {
@@ -47,7 +83,9 @@ func isOpened(exposed []types.ExposeRequest, port types.ExposeRequest) bool {
 	return false
 }
 
-func unexposePorts() error {
+// unexposePorts releases the ports forwarded to instanceIP, the forwards of
+// the other instances of the daemon are kept
+func unexposePorts(instanceIP string) error {
 	var mErr crcErrors.MultiError
 	daemonClient := daemonclient.New()
 	alreadyOpenedPorts, err := listOpenPorts(daemonClient)
@@ -55,6 +93,9 @@ func unexposePorts() error {
 		return err
 	}
 	for _, port := range alreadyOpenedPorts {
+		if !isExposedTo(port, instanceIP) {
+			continue
+		}
 		if err := daemonClient.NetworkClient.Unexpose(&types.UnexposeRequest{Protocol: port.Protocol, Local: port.Local}); err != nil {
 			mErr.Collect(errors.Wrapf(err, "failed to unexpose port %s ", port.Local))
 		}
@@ -65,6 +106,19 @@ func unexposePorts() error {
 	return mErr
 }
 
+// isExposedTo returns true when port is forwarded to instanceIP, its remote
+// is either host:port or an ssh-tunnel URI for the forwarded sockets
+func isExposedTo(port types.ExposeRequest, instanceIP string) bool {
+	if u, err := url.Parse(port.Remote); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Hostname() == instanceIP
+	}
+	host, _, err := net.SplitHostPort(port.Remote)
+	if err != nil {
+		return false
+	}
+	return host == instanceIP
+}
+
 func listOpenPorts(daemonClient *daemonclient.Client) ([]types.ExposeRequest, error) {
 	alreadyOpenedPorts, err := daemonClient.NetworkClient.List()
 	if err != nil {
@@ -75,34 +129,22 @@ func listOpenPorts(daemonClient *daemonclient.Client) ([]types.ExposeRequest, er
 }
 
 const (
-	virtualMachineIP = "192.168.127.2"
-	hostVirtualIP    = "192.168.127.254"
-	internalSSHPort  = "22"
-	remoteHTTPPort   = "80"
-	remoteHTTPSPort  = "443"
-	apiPort          = "6443"
-	cockpitPort      = "9090"
+	internalSSHPort = "22"
+	remoteHTTPPort  = "80"
+	remoteHTTPSPort = "443"
+	apiPort         = "6443"
+	cockpitPort     = "9090"
 )
 
-func vsockPorts(preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint) []types.ExposeRequest {
-	socketProtocol := types.UNIX
-	socketLocal := constants.GetHostDockerSocketPath()
-	if runtime.GOOS == "windows" {
-		socketProtocol = types.NPIPE
-		socketLocal = constants.DefaultPodmanNamedPipe
-	}
+func vsockPorts(name string, instanceIP string, preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint, podmanSocket podmanSocketConfig) []types.ExposeRequest {
 	exposeRequest := []types.ExposeRequest{
 		{
 			Protocol: "tcp",
 			Local:    net.JoinHostPort(constants.LocalIP, strconv.Itoa(constants.VsockSSHPort)),
-			Remote:   net.JoinHostPort(virtualMachineIP, internalSSHPort),
-		},
-		{
-			Protocol: socketProtocol,
-			Local:    socketLocal,
-			Remote:   getSSHTunnelURI(),
+			Remote:   net.JoinHostPort(instanceIP, internalSSHPort),
 		},
 	}
+	exposeRequest = append(exposeRequest, podmanSocketExposeRequests(name, instanceIP, podmanSocket)...)
 
 	switch preset {
 	case crcPreset.OpenShift, crcPreset.OKD, crcPreset.Microshift:
@@ -110,17 +152,17 @@ func vsockPorts(preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint)
 			types.ExposeRequest{
 				Protocol: "tcp",
 				Local:    net.JoinHostPort(constants.LocalIP, apiPort),
-				Remote:   net.JoinHostPort(virtualMachineIP, apiPort),
+				Remote:   net.JoinHostPort(instanceIP, apiPort),
 			},
 			types.ExposeRequest{
 				Protocol: "tcp",
 				Local:    fmt.Sprintf(":%d", ingressHTTPSPort),
-				Remote:   net.JoinHostPort(virtualMachineIP, remoteHTTPSPort),
+				Remote:   net.JoinHostPort(instanceIP, remoteHTTPSPort),
 			},
 			types.ExposeRequest{
 				Protocol: "tcp",
 				Local:    fmt.Sprintf(":%d", ingressHTTPPort),
-				Remote:   net.JoinHostPort(virtualMachineIP, remoteHTTPPort),
+				Remote:   net.JoinHostPort(instanceIP, remoteHTTPPort),
 			})
 	default:
 		logging.Errorf("Invalid preset: %s", preset)
@@ -129,14 +171,49 @@ func vsockPorts(preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint)
 	return exposeRequest
 }
 
-func getSSHTunnelURI() string {
+// reservedHostPorts returns the TCP host ports used by ports, with the
+// address they are forwarded to
+func reservedHostPorts(ports []types.ExposeRequest) map[uint]string {
+	reserved := make(map[uint]string)
+	for _, port := range ports {
+		if port.Protocol != types.TCP {
+			continue
+		}
+		_, hostPort, err := net.SplitHostPort(port.Local)
+		if err != nil {
+			continue
+		}
+		if p, err := strconv.ParseUint(hostPort, 10, 16); err == nil {
+			reserved[uint(p)] = fmt.Sprintf("the forward to %s", port.Remote)
+		}
+	}
+	return reserved
+}
+
+func portForwardExposeRequests(instanceIP string, portForwards []network.PortForward) []types.ExposeRequest {
+	var exposeRequests []types.ExposeRequest
+	for _, pf := range portForwards {
+		exposeRequests = append(exposeRequests, portForwardExposeRequest(instanceIP, pf))
+	}
+	return exposeRequests
+}
+
+func portForwardExposeRequest(instanceIP string, pf network.PortForward) types.ExposeRequest {
+	return types.ExposeRequest{
+		Protocol: types.TransportProtocol(pf.Protocol),
+		Local:    net.JoinHostPort(constants.LocalIP, strconv.FormatUint(uint64(pf.HostPort), 10)),
+		Remote:   net.JoinHostPort(instanceIP, strconv.FormatUint(uint64(pf.GuestPort), 10)),
+	}
+}
+
+func getSSHTunnelURI(name string, instanceIP string, socketPath string) string {
 	u := url.URL{
 		Scheme:     "ssh-tunnel",
 		User:       url.User("core"),
-		Host:       net.JoinHostPort(virtualMachineIP, internalSSHPort),
-		Path:       "/run/podman/podman.sock",
+		Host:       net.JoinHostPort(instanceIP, internalSSHPort),
+		Path:       socketPath,
 		ForceQuery: false,
-		RawQuery:   fmt.Sprintf("key=%s", url.QueryEscape(constants.GetPrivateKeyPath())),
+		RawQuery:   fmt.Sprintf("key=%s", url.QueryEscape(constants.GetPrivateKeyPath(name))),
 	}
 	return u.String()
 }
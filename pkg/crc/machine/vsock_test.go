@@ -0,0 +1,48 @@
+package machine
+
+import (
+	"net"
+	"testing"
+
+	"github.com/containers/gvisor-tap-vsock/pkg/types"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsExposedTo(t *testing.T) {
+	assert.True(t, isExposedTo(types.ExposeRequest{Local: "127.0.0.1:2222", Remote: "192.168.127.2:22"}, "192.168.127.2"))
+	assert.True(t, isExposedTo(types.ExposeRequest{Local: "/tmp/podman.sock", Remote: getSSHTunnelURI("crc", "192.168.127.2", "/run/podman/podman.sock")}, "192.168.127.2"))
+	assert.False(t, isExposedTo(types.ExposeRequest{Local: "127.0.0.1:2222", Remote: "192.168.128.2:22"}, "192.168.127.2"))
+	assert.False(t, isExposedTo(types.ExposeRequest{Local: "/tmp/podman.sock", Remote: getSSHTunnelURI("other", "192.168.128.2", "/run/podman/podman.sock")}, "192.168.127.2"))
+}
+
+func TestReservedHostPorts(t *testing.T) {
+	podmanSocket := podmanSocketConfig{guestSocket: "/run/podman/podman.sock", hostSocket: "/tmp/podman.sock", tcpAddress: "127.0.0.1:2375"}
+	reserved := reservedHostPorts(vsockPorts("crc", "192.168.127.2", crcPreset.OpenShift, 8080, 8443, podmanSocket))
+	assert.Equal(t, map[uint]string{
+		2222: "the forward to 192.168.127.2:22",
+		2375: "the forward to 192.168.127.2:2375",
+		6443: "the forward to 192.168.127.2:6443",
+		8443: "the forward to 192.168.127.2:443",
+		8080: "the forward to 192.168.127.2:80",
+	}, reserved)
+}
+
+func TestAppsDomainZone(t *testing.T) {
+	instanceIP := net.ParseIP("192.168.127.2")
+	zones := []types.Zone{
+		{Name: "apps-crc.testing.", DefaultIP: instanceIP},
+		{Name: "crc.testing."},
+	}
+
+	zone, err := appsDomainZone(zones, "apps.example.com", instanceIP)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.Zone{Name: "apps.example.com.", DefaultIP: instanceIP}, zone)
+
+	zone, err = appsDomainZone(append(zones, *zone), "apps.example.com", instanceIP)
+	assert.NoError(t, err)
+	assert.Nil(t, zone)
+
+	_, err = appsDomainZone(zones, "apps.crc.testing", instanceIP)
+	assert.EqualError(t, err, "the crc.testing. zone of the daemon answers for apps.crc.testing")
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The metrics are written using the Prometheus text exposition format
+// https://prometheus.io/docs/instrumenting/exposition_formats/#text-based-format
+
+type metricType string
+
+const (
+	gaugeType     metricType = "gauge"
+	counterType   metricType = "counter"
+	histogramType metricType = "histogram"
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+func writeHeader(w io.Writer, name, help string, typ metricType) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+}
+
+func writeSample(w io.Writer, name string, value float64, labels ...label) {
+	fmt.Fprintf(w, "%s%s %s\n", name, formatLabels(labels), formatValue(value))
+}
+
+func writeGauge(w io.Writer, name, help string, value float64) {
+	writeHeader(w, name, help, gaugeType)
+	writeSample(w, name, value)
+}
+
+func writeCounter(w io.Writer, name, help string, value float64) {
+	writeHeader(w, name, help, counterType)
+	writeSample(w, name, value)
+}
+
+func formatLabels(labels []label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	formatted := make([]string, 0, len(labels))
+	for _, l := range labels {
+		formatted = append(formatted, fmt.Sprintf("%s=%q", l.name, l.value))
+	}
+	return "{" + strings.Join(formatted, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
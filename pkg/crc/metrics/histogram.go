@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"sync"
+)
+
+// Histogram counts observations in buckets, optionally partitioned by the
+// value of a single label
+type Histogram struct {
+	name    string
+	help    string
+	label   string
+	buckets []float64
+
+	lock   sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram creates a histogram with the given upper bounds, which must be sorted.
+// label is the name of the label used by ObserveWithLabel, it can be empty.
+func NewHistogram(name, help, label string, buckets []float64) *Histogram {
+	return &Histogram{
+		name:    name,
+		help:    help,
+		label:   label,
+		buckets: buckets,
+		series:  map[string]*histogramSeries{},
+	}
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.ObserveWithLabel("", value)
+}
+
+func (h *Histogram) ObserveWithLabel(labelValue string, value float64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	series, ok := h.series[labelValue]
+	if !ok {
+		series = &histogramSeries{
+			counts: make([]uint64, len(h.buckets)),
+		}
+		h.series[labelValue] = series
+	}
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	writeHeader(w, h.name, h.help, histogramType)
+	for _, labelValue := range sortedKeys(h.series) {
+		series := h.series[labelValue]
+		var labels []label
+		if h.label != "" {
+			labels = append(labels, label{name: h.label, value: labelValue})
+		}
+		for i, upperBound := range h.buckets {
+			writeSample(w, h.name+"_bucket", float64(series.counts[i]), append(labels, label{name: "le", value: formatValue(upperBound)})...)
+		}
+		writeSample(w, h.name+"_bucket", float64(series.count), append(labels, label{name: "le", value: formatValue(math.Inf(1))})...)
+		writeSample(w, h.name+"_sum", series.sum, labels...)
+		writeSample(w, h.name+"_count", float64(series.count), labels...)
+	}
+}
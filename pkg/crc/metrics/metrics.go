@@ -0,0 +1,209 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+)
+
+const (
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	// statusCacheTTL is how long the status of the instance is reused
+	// between scrapes, getting it runs several commands in the instance
+	statusCacheTTL = 15 * time.Second
+)
+
+var (
+	startHistogramsLock sync.Mutex
+	startHistograms     = map[string]*instanceStartHistograms{}
+
+	openshiftStatuses = []types.OpenshiftStatus{
+		types.OpenshiftUnreachable,
+		types.OpenshiftStarting,
+		types.OpenshiftRunning,
+		types.OpenshiftDegraded,
+		types.OpenshiftStopping,
+		types.OpenshiftStopped,
+	}
+)
+
+// NetworkStats is implemented by the virtual network of the daemon
+type NetworkStats interface {
+	BytesSent() uint64
+	BytesReceived() uint64
+}
+
+// instanceStartHistograms records the starts of an instance requested through the daemon API
+type instanceStartHistograms struct {
+	duration      *Histogram
+	phaseDuration *Histogram
+}
+
+// startHistogramsFor returns the start histograms of the instance called name.
+// They outlive the handlers, which are created again when the daemon restarts
+// an instance API.
+func startHistogramsFor(name string) *instanceStartHistograms {
+	startHistogramsLock.Lock()
+	defer startHistogramsLock.Unlock()
+
+	histograms, ok := startHistograms[name]
+	if !ok {
+		histograms = &instanceStartHistograms{
+			duration: NewHistogram("crc_start_duration_seconds",
+				"Duration of the instance starts, by result.",
+				"result",
+				[]float64{30, 60, 120, 180, 300, 450, 600, 900, 1200, 1800}),
+			phaseDuration: NewHistogram("crc_start_phase_duration_seconds",
+				"Duration of the phases of the instance starts.",
+				"phase",
+				[]float64{0.5, 1, 5, 10, 30, 60, 120, 300, 600}),
+		}
+		startHistograms[name] = histograms
+	}
+	return histograms
+}
+
+// ObserveStartProgress records the duration of the completed start phases of the instance called name
+func ObserveStartProgress(name string, progress types.StartProgress) {
+	if progress.State == types.PhaseRunning {
+		return
+	}
+	startHistogramsFor(name).phaseDuration.ObserveWithLabel(string(progress.Phase), progress.Elapsed.Seconds())
+}
+
+type handler struct {
+	client  machine.Client
+	network NetworkStats
+	now     func() time.Time
+
+	lock      sync.Mutex
+	status    *types.ClusterStatusResult
+	load      *types.ClusterLoadResult
+	updatedAt time.Time
+}
+
+// NewHandler returns a http.Handler serving the metrics of the instance
+// managed by client. network can be nil when the daemon doesn't run the
+// user mode network stack.
+func NewHandler(client machine.Client, network NetworkStats) http.Handler {
+	return &handler{
+		client:  client,
+		network: network,
+		now:     time.Now,
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var buf bytes.Buffer
+	h.write(&buf)
+	w.Header().Set("Content-Type", contentType)
+	if _, err := buf.WriteTo(w); err != nil {
+		logging.Debugf("Cannot write metrics: %v", err)
+	}
+}
+
+// instanceStatus returns the status and the load of the instance, they are
+// only queried again once they are older than statusCacheTTL. load is nil
+// when the instance isn't running or when it cannot be queried.
+func (h *handler) instanceStatus() (*types.ClusterStatusResult, *types.ClusterLoadResult) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.status != nil && h.now().Sub(h.updatedAt) < statusCacheTTL {
+		return h.status, h.load
+	}
+
+	status, err := h.client.Status()
+	if err != nil {
+		logging.Debugf("Cannot get instance status for metrics: %v", err)
+		status = &types.ClusterStatusResult{
+			CrcStatus:       state.Error,
+			OpenshiftStatus: types.OpenshiftUnreachable,
+		}
+	}
+	var load *types.ClusterLoadResult
+	if status.CrcStatus == state.Running {
+		load, err = h.client.GetClusterLoad()
+		if err != nil {
+			logging.Debugf("Cannot get instance load for metrics: %v", err)
+			load = nil
+		}
+	}
+
+	h.status, h.load, h.updatedAt = status, load, h.now()
+	return status, load
+}
+
+func (h *handler) write(w io.Writer) {
+	status, load := h.instanceStatus()
+
+	writeGauge(w, "crc_vm_running", "Whether the instance is running.", boolToFloat(status.CrcStatus == state.Running))
+
+	if status.CrcStatus == state.Running {
+		if load != nil {
+			writeLoad(w, load)
+		}
+		writeGauge(w, "crc_vm_disk_used_bytes", "Disk space used in the instance.", float64(status.DiskUse))
+		writeGauge(w, "crc_vm_disk_size_bytes", "Disk size of the instance.", float64(status.DiskSize))
+		if status.PersistentVolumeSize != 0 {
+			writeGauge(w, "crc_persistent_volume_used_bytes", "Space used in the persistent volumes storage.", float64(status.PersistentVolumeUse))
+			writeGauge(w, "crc_persistent_volume_size_bytes", "Size of the persistent volumes storage.", float64(status.PersistentVolumeSize))
+		}
+	}
+
+	writeGauge(w, "crc_cluster_ready", "Whether all the cluster operators are available and none is progressing or degraded.", boolToFloat(status.OpenshiftStatus == types.OpenshiftRunning))
+	writeHeader(w, "crc_cluster_status", "Status of the cluster, the sample with the current status is 1.", gaugeType)
+	for _, openshiftStatus := range openshiftStatuses {
+		writeSample(w, "crc_cluster_status", boolToFloat(status.OpenshiftStatus == openshiftStatus), label{name: "status", value: string(openshiftStatus)})
+	}
+
+	if h.network != nil {
+		writeCounter(w, "crc_network_sent_bytes_total", "Bytes sent to the instance by the virtual network.", float64(h.network.BytesSent()))
+		writeCounter(w, "crc_network_received_bytes_total", "Bytes received from the instance by the virtual network.", float64(h.network.BytesReceived()))
+	}
+
+	startHistograms := startHistogramsFor(h.client.GetName())
+	startHistograms.duration.write(w)
+	startHistograms.phaseDuration.write(w)
+}
+
+func writeLoad(w io.Writer, load *types.ClusterLoadResult) {
+	writeGauge(w, "crc_vm_memory_used_bytes", "Memory used in the instance.", float64(load.RAMUse))
+	writeGauge(w, "crc_vm_memory_size_bytes", "Memory size of the instance.", float64(load.RAMSize))
+	if len(load.CPUUse) == 0 {
+		return
+	}
+	writeHeader(w, "crc_vm_cpu_usage_percent", "CPU usage in the instance, by CPU.", gaugeType)
+	for cpu, usage := range load.CPUUse {
+		writeSample(w, "crc_vm_cpu_usage_percent", float64(usage), label{name: "cpu", value: strconv.Itoa(cpu)})
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ObserveStart records the duration of a start of the instance called name and whether it succeeded
+func ObserveStart(name string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	startHistogramsFor(name).duration.ObserveWithLabel(result, duration.Seconds())
+}
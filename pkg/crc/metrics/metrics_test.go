@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNetwork struct{}
+
+type countingClient struct {
+	*fakemachine.Client
+	statusCalls int
+}
+
+func (c *countingClient) Status() (*types.ClusterStatusResult, error) {
+	c.statusCalls++
+	return c.Client.Status()
+}
+
+func (n *fakeNetwork) BytesSent() uint64 {
+	return 1234
+}
+
+func (n *fakeNetwork) BytesReceived() uint64 {
+	return 5678
+}
+
+func TestHistogram(t *testing.T) {
+	histogram := NewHistogram("test_duration_seconds", "Test durations.", "result", []float64{1, 10})
+	histogram.ObserveWithLabel("success", 0.5)
+	histogram.ObserveWithLabel("success", 5)
+	histogram.ObserveWithLabel("failure", 20)
+
+	var buf bytes.Buffer
+	histogram.write(&buf)
+	assert.Equal(t, `# HELP test_duration_seconds Test durations.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{result="failure",le="1"} 0
+test_duration_seconds_bucket{result="failure",le="10"} 0
+test_duration_seconds_bucket{result="failure",le="+Inf"} 1
+test_duration_seconds_sum{result="failure"} 20
+test_duration_seconds_count{result="failure"} 1
+test_duration_seconds_bucket{result="success",le="1"} 1
+test_duration_seconds_bucket{result="success",le="10"} 2
+test_duration_seconds_bucket{result="success",le="+Inf"} 2
+test_duration_seconds_sum{result="success"} 5.5
+test_duration_seconds_count{result="success"} 2
+`, buf.String())
+}
+
+func TestHandler(t *testing.T) {
+	ObserveStart("crc", 3*time.Minute, nil)
+	ObserveStart("crc", time.Minute, errors.New("failed"))
+	ObserveStart("other", time.Minute, nil)
+	ObserveStartProgress("crc", types.StartProgress{Phase: types.StartPhaseWaitSSH, State: types.PhaseRunning})
+	ObserveStartProgress("crc", types.StartProgress{Phase: types.StartPhaseWaitSSH, State: types.PhaseCompleted, Elapsed: 2 * time.Second})
+
+	handler := NewHandler(fakemachine.NewClient(), &fakeNetwork{})
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	resp := recorder.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	for _, expected := range []string{
+		"crc_vm_running 1\n",
+		"crc_vm_memory_used_bytes 1000\n",
+		"crc_vm_memory_size_bytes 2000\n",
+		`crc_vm_cpu_usage_percent{cpu="1"} 20` + "\n",
+		"crc_vm_disk_used_bytes 1e+10\n",
+		"crc_cluster_ready 1\n",
+		`crc_cluster_status{status="Running"} 1` + "\n",
+		`crc_cluster_status{status="Degraded"} 0` + "\n",
+		"# TYPE crc_network_sent_bytes_total counter\n",
+		"crc_network_sent_bytes_total 1234\n",
+		"crc_network_received_bytes_total 5678\n",
+		`crc_start_duration_seconds_bucket{result="success",le="180"} 1` + "\n",
+		`crc_start_duration_seconds_count{result="failure"} 1` + "\n",
+		`crc_start_phase_duration_seconds_count{phase="wait-ssh"} 1` + "\n",
+	} {
+		assert.Contains(t, string(body), expected)
+	}
+	assert.NotContains(t, string(body), `crc_start_duration_seconds_bucket{result="success",le="60"} 1`)
+}
+
+func TestHandlerWhenStatusFails(t *testing.T) {
+	handler := NewHandler(fakemachine.NewFailingClient(), nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "crc_vm_running 0\n")
+	assert.Contains(t, string(body), `crc_cluster_status{status="Unreachable"} 1`+"\n")
+	assert.NotContains(t, string(body), "crc_network_sent_bytes_total")
+}
+
+func TestHandlerCachesStatus(t *testing.T) {
+	client := &countingClient{Client: fakemachine.NewClient()}
+	handler := NewHandler(client, nil).(*handler)
+	now := time.Now()
+	handler.now = func() time.Time {
+		return now
+	}
+
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		body, err := io.ReadAll(recorder.Result().Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	assert.Contains(t, scrape(), "crc_vm_memory_used_bytes 1000\n")
+	assert.Contains(t, scrape(), "crc_vm_memory_used_bytes 1000\n")
+	assert.Equal(t, 1, client.statusCalls)
+
+	now = now.Add(statusCacheTTL)
+	scrape()
+	assert.Equal(t, 2, client.statusCalls)
+}
@@ -0,0 +1,46 @@
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+func parseIPv4(address string) (net.IP, error) {
+	ip := net.ParseIP(address).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IPv4 address '%s'", address)
+	}
+	return ip, nil
+}
+
+func parseIPv4Subnet(subnet string) (*net.IPNet, error) {
+	ip, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil || ip.To4() == nil {
+		return nil, fmt.Errorf("invalid IPv4 subnet '%s', expected CIDR notation such as '192.168.130.0/24'", subnet)
+	}
+	if !ip.Equal(ipNet.IP) {
+		return nil, fmt.Errorf("invalid subnet '%s', did you mean '%s'?", subnet, ipNet)
+	}
+	ipNet.IP = ipNet.IP.To4()
+	return ipNet, nil
+}
+
+func addToIP(ip net.IP, n uint32) net.IP {
+	ret := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ret, binary.BigEndian.Uint32(ip.To4())+n)
+	return ret
+}
+
+func broadcastAddress(ipNet *net.IPNet) net.IP {
+	broadcast := make(net.IP, net.IPv4len)
+	for i := range broadcast {
+		broadcast[i] = ipNet.IP[i] | ^ipNet.Mask[i]
+	}
+	return broadcast
+}
+
+// isHostAddress returns true if ip is in ipNet and is neither its network nor its broadcast address
+func isHostAddress(ipNet *net.IPNet, ip net.IP) bool {
+	return ipNet.Contains(ip) && !ip.Equal(ipNet.IP) && !ip.Equal(broadcastAddress(ipNet))
+}
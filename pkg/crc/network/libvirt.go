@@ -0,0 +1,155 @@
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	DefaultLibvirtBridge = "crc"
+	DefaultLibvirtSubnet = "192.168.130.0/24"
+
+	// offset in the subnet of the instance address when no DHCP range is set,
+	// this gives the historical 192.168.130.11 with the default subnet
+	libvirtInstanceOffset = 11
+	// the kernel limits interface names to 15 characters
+	maxBridgeNameLength = 15
+)
+
+// LibvirtNetwork describes the libvirt network used by the system network mode on Linux
+type LibvirtNetwork struct {
+	Bridge  string
+	Subnet  *net.IPNet
+	Gateway net.IP
+	// InstanceIP is the address statically leased to the instance
+	InstanceIP net.IP
+	// DHCPStart and DHCPEnd are nil when the network has no dynamic DHCP range
+	DHCPStart net.IP
+	DHCPEnd   net.IP
+}
+
+// Prefix returns the length of the subnet mask
+func (n *LibvirtNetwork) Prefix() int {
+	ones, _ := n.Subnet.Mask.Size()
+	return ones
+}
+
+// DefaultLibvirtNetwork returns the network used when none of the libvirt network settings are changed
+func DefaultLibvirtNetwork() *LibvirtNetwork {
+	n, err := ParseLibvirtNetwork(DefaultLibvirtBridge, DefaultLibvirtSubnet, "", "")
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// ParseLibvirtNetwork checks the consistency of the libvirt network settings.
+// An empty gateway is the first address of the subnet. When dhcpRange is not
+// empty, it's 'start-end' and the instance gets the start address, otherwise
+// the instance gets the address at offset 11 in the subnet.
+func ParseLibvirtNetwork(bridge, subnet, gateway, dhcpRange string) (*LibvirtNetwork, error) {
+	if err := validateBridgeName(bridge); err != nil {
+		return nil, err
+	}
+	ipNet, err := parseIPv4Subnet(subnet)
+	if err != nil {
+		return nil, err
+	}
+	n := &LibvirtNetwork{
+		Bridge: bridge,
+		Subnet: ipNet,
+	}
+
+	if gateway == "" {
+		n.Gateway = addToIP(ipNet.IP, 1)
+	} else if n.Gateway, err = parseIPv4(gateway); err != nil {
+		return nil, err
+	}
+	if !isHostAddress(ipNet, n.Gateway) {
+		return nil, fmt.Errorf("gateway %s is not a host address of subnet %s", n.Gateway, ipNet)
+	}
+
+	if dhcpRange == "" {
+		n.InstanceIP = addToIP(ipNet.IP, libvirtInstanceOffset)
+		if !isHostAddress(ipNet, n.InstanceIP) {
+			return nil, fmt.Errorf("subnet %s is too small", ipNet)
+		}
+	} else {
+		if n.DHCPStart, n.DHCPEnd, err = parseIPRange(dhcpRange); err != nil {
+			return nil, err
+		}
+		if !isHostAddress(ipNet, n.DHCPStart) || !isHostAddress(ipNet, n.DHCPEnd) {
+			return nil, fmt.Errorf("DHCP range %s is not within subnet %s", dhcpRange, ipNet)
+		}
+		if bytes.Compare(n.Gateway, n.DHCPStart) >= 0 && bytes.Compare(n.Gateway, n.DHCPEnd) <= 0 {
+			return nil, fmt.Errorf("gateway %s is within DHCP range %s", n.Gateway, dhcpRange)
+		}
+		n.InstanceIP = n.DHCPStart
+	}
+	if n.InstanceIP.Equal(n.Gateway) {
+		return nil, fmt.Errorf("gateway %s conflicts with the instance address", n.Gateway)
+	}
+	return n, nil
+}
+
+func ValidateLibvirtBridge(val interface{}) (bool, string) {
+	if err := validateBridgeName(cast.ToString(val)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func ValidateLibvirtSubnet(val interface{}) (bool, string) {
+	if _, err := parseIPv4Subnet(cast.ToString(val)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func ValidateLibvirtGateway(val interface{}) (bool, string) {
+	if gateway := cast.ToString(val); gateway != "" {
+		if _, err := parseIPv4(gateway); err != nil {
+			return false, err.Error()
+		}
+	}
+	return true, ""
+}
+
+func ValidateLibvirtDHCPRange(val interface{}) (bool, string) {
+	if dhcpRange := cast.ToString(val); dhcpRange != "" {
+		if _, _, err := parseIPRange(dhcpRange); err != nil {
+			return false, err.Error()
+		}
+	}
+	return true, ""
+}
+
+func validateBridgeName(name string) error {
+	if name == "" || len(name) > maxBridgeNameLength || strings.ContainsAny(name, "/: \t") {
+		return fmt.Errorf("invalid bridge name '%s', it must have between 1 and %d characters and no '/', ':' or spaces", name, maxBridgeNameLength)
+	}
+	return nil
+}
+
+func parseIPRange(ipRange string) (net.IP, net.IP, error) {
+	start, end, ok := strings.Cut(ipRange, "-")
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid range '%s', expected 'start-end' such as '192.168.130.11-192.168.130.254'", ipRange)
+	}
+	startIP, err := parseIPv4(strings.TrimSpace(start))
+	if err != nil {
+		return nil, nil, err
+	}
+	endIP, err := parseIPv4(strings.TrimSpace(end))
+	if err != nil {
+		return nil, nil, err
+	}
+	if bytes.Compare(startIP, endIP) > 0 {
+		return nil, nil, fmt.Errorf("invalid range '%s', %s is after %s", ipRange, startIP, endIP)
+	}
+	return startIP, endIP, nil
+}
@@ -0,0 +1,77 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultLibvirtNetwork(t *testing.T) {
+	n := DefaultLibvirtNetwork()
+	assert.Equal(t, "crc", n.Bridge)
+	assert.Equal(t, "192.168.130.0/24", n.Subnet.String())
+	assert.Equal(t, 24, n.Prefix())
+	assert.Equal(t, net.IPv4(192, 168, 130, 1).To4(), n.Gateway)
+	assert.Equal(t, net.IPv4(192, 168, 130, 11).To4(), n.InstanceIP)
+	assert.Nil(t, n.DHCPStart)
+}
+
+func TestParseLibvirtNetwork(t *testing.T) {
+	n, err := ParseLibvirtNetwork("crc0", "10.88.0.0/16", "10.88.0.254", "10.88.1.10-10.88.1.200")
+	require.NoError(t, err)
+	assert.Equal(t, 16, n.Prefix())
+	assert.Equal(t, "10.88.0.254", n.Gateway.String())
+	assert.Equal(t, "10.88.1.10", n.InstanceIP.String())
+	assert.Equal(t, "10.88.1.10", n.DHCPStart.String())
+	assert.Equal(t, "10.88.1.200", n.DHCPEnd.String())
+
+	n, err = ParseLibvirtNetwork("crc", "172.30.5.0/28", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "172.30.5.1", n.Gateway.String())
+	assert.Equal(t, "172.30.5.11", n.InstanceIP.String())
+}
+
+func TestParseLibvirtNetworkErrors(t *testing.T) {
+	for _, tc := range []struct {
+		bridge, subnet, gateway, dhcpRange string
+		err                                string
+	}{
+		{"", "192.168.130.0/24", "", "", "invalid bridge name ''"},
+		{"this-name-is-too-long", "192.168.130.0/24", "", "", "invalid bridge name 'this-name-is-too-long'"},
+		{"crc", "192.168.130.0", "", "", "invalid IPv4 subnet '192.168.130.0'"},
+		{"crc", "fd00::/64", "", "", "invalid IPv4 subnet 'fd00::/64'"},
+		{"crc", "192.168.130.1/24", "", "", "invalid subnet '192.168.130.1/24', did you mean '192.168.130.0/24'?"},
+		{"crc", "192.168.130.0/29", "", "", "subnet 192.168.130.0/29 is too small"},
+		{"crc", "192.168.130.0/24", "192.168.131.1", "", "gateway 192.168.131.1 is not a host address of subnet 192.168.130.0/24"},
+		{"crc", "192.168.130.0/24", "192.168.130.255", "", "gateway 192.168.130.255 is not a host address of subnet 192.168.130.0/24"},
+		{"crc", "192.168.130.0/24", "192.168.130.11", "", "gateway 192.168.130.11 conflicts with the instance address"},
+		{"crc", "192.168.130.0/24", "", "192.168.130.20", "invalid range '192.168.130.20'"},
+		{"crc", "192.168.130.0/24", "", "192.168.130.20-192.168.130.10", "invalid range '192.168.130.20-192.168.130.10', 192.168.130.20 is after 192.168.130.10"},
+		{"crc", "192.168.130.0/24", "", "192.168.130.10-192.168.131.10", "DHCP range 192.168.130.10-192.168.131.10 is not within subnet 192.168.130.0/24"},
+		{"crc", "192.168.130.0/24", "192.168.130.50", "192.168.130.10-192.168.130.100", "gateway 192.168.130.50 is within DHCP range 192.168.130.10-192.168.130.100"},
+	} {
+		_, err := ParseLibvirtNetwork(tc.bridge, tc.subnet, tc.gateway, tc.dhcpRange)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), tc.err)
+		}
+	}
+}
+
+func TestValidateLibvirtSettings(t *testing.T) {
+	valid, _ := ValidateLibvirtSubnet("10.0.0.0/8")
+	assert.True(t, valid)
+	valid, _ = ValidateLibvirtSubnet("10.0.0.0")
+	assert.False(t, valid)
+	valid, _ = ValidateLibvirtGateway("")
+	assert.True(t, valid)
+	valid, _ = ValidateLibvirtGateway("10.0.0.300")
+	assert.False(t, valid)
+	valid, _ = ValidateLibvirtDHCPRange("10.0.0.2-10.0.0.9")
+	assert.True(t, valid)
+	valid, _ = ValidateLibvirtDHCPRange("10.0.0.9-10.0.0.2")
+	assert.False(t, valid)
+	valid, _ = ValidateLibvirtBridge("virbr-crc")
+	assert.True(t, valid)
+}
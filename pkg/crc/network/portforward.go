@@ -0,0 +1,143 @@
+package network
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	TCPProtocol = "tcp"
+	UDPProtocol = "udp"
+)
+
+// PortForward forwards HostPort on the host loopback interface to GuestPort
+// in the instance when using the user mode network
+type PortForward struct {
+	Protocol  string
+	HostPort  uint
+	GuestPort uint
+}
+
+// String returns the forward as 'hostPort:guestPort/protocol', which is the
+// format accepted by ParsePortForward
+func (pf PortForward) String() string {
+	return fmt.Sprintf("%d:%d/%s", pf.HostPort, pf.GuestPort, pf.Protocol)
+}
+
+// Matches returns true if pf and other use the same host port and protocol
+func (pf PortForward) Matches(other PortForward) bool {
+	return pf.HostPort == other.HostPort && pf.Protocol == other.Protocol
+}
+
+// ParsePortForward parses 'hostPort:guestPort[/protocol]', the protocol is tcp
+// when it's omitted
+func ParsePortForward(spec string) (PortForward, error) {
+	ports, protocol, err := splitProtocol(spec)
+	if err != nil {
+		return PortForward{}, err
+	}
+	hostPort, guestPort, ok := strings.Cut(ports, ":")
+	if !ok {
+		return PortForward{}, fmt.Errorf("invalid port forward '%s', expected 'hostPort:guestPort[/protocol]'", spec)
+	}
+	pf := PortForward{Protocol: protocol}
+	if pf.HostPort, err = parsePort(hostPort); err != nil {
+		return PortForward{}, err
+	}
+	if pf.GuestPort, err = parsePort(guestPort); err != nil {
+		return PortForward{}, err
+	}
+	return pf, nil
+}
+
+// ParseHostPort parses 'hostPort[/protocol]' which identifies a port forward
+func ParseHostPort(spec string) (PortForward, error) {
+	port, protocol, err := splitProtocol(spec)
+	if err != nil {
+		return PortForward{}, err
+	}
+	hostPort, err := parsePort(port)
+	if err != nil {
+		return PortForward{}, err
+	}
+	return PortForward{Protocol: protocol, HostPort: hostPort}, nil
+}
+
+// ParsePortForwards parses a comma-separated list of port forwards
+func ParsePortForwards(specs string) ([]PortForward, error) {
+	var portForwards []PortForward
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		pf, err := ParsePortForward(spec)
+		if err != nil {
+			return nil, err
+		}
+		for _, existing := range portForwards {
+			if existing.Matches(pf) {
+				return nil, fmt.Errorf("host port %d/%s is forwarded more than once", pf.HostPort, pf.Protocol)
+			}
+		}
+		portForwards = append(portForwards, pf)
+	}
+	return portForwards, nil
+}
+
+// FormatPortForwards is the inverse of ParsePortForwards
+func FormatPortForwards(portForwards []PortForward) string {
+	specs := make([]string, 0, len(portForwards))
+	for _, pf := range portForwards {
+		specs = append(specs, pf.String())
+	}
+	return strings.Join(specs, ",")
+}
+
+// CheckReservedHostPorts returns an error when one of portForwards uses a TCP
+// host port of reserved, which describes what each port is used for
+func CheckReservedHostPorts(portForwards []PortForward, reserved map[uint]string) error {
+	for _, pf := range portForwards {
+		if usage, ok := reserved[pf.HostPort]; ok && pf.Protocol == TCPProtocol {
+			return fmt.Errorf("host port %d/%s is already used by %s", pf.HostPort, pf.Protocol, usage)
+		}
+	}
+	return nil
+}
+
+// ValidatePortForwards checks that val is a valid list of port forwards
+// which doesn't use any of the reserved host ports
+func ValidatePortForwards(val interface{}, reserved map[uint]string) (bool, string) {
+	portForwards, err := ParsePortForwards(cast.ToString(val))
+	if err != nil {
+		return false, err.Error()
+	}
+	if err := CheckReservedHostPorts(portForwards, reserved); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func splitProtocol(spec string) (string, string, error) {
+	ports, protocol, ok := strings.Cut(strings.TrimSpace(spec), "/")
+	if !ok {
+		return ports, TCPProtocol, nil
+	}
+	switch protocol {
+	case TCPProtocol, UDPProtocol:
+		return ports, protocol, nil
+	default:
+		return "", "", fmt.Errorf("invalid protocol '%s', should be either %s or %s", protocol, TCPProtocol, UDPProtocol)
+	}
+}
+
+func parsePort(port string) (uint, error) {
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || p == 0 {
+		return 0, fmt.Errorf("invalid port '%s'", port)
+	}
+	return uint(p), nil
+}
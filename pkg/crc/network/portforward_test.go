@@ -0,0 +1,62 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortForward(t *testing.T) {
+	pf, err := ParsePortForward("8080:30080")
+	require.NoError(t, err)
+	assert.Equal(t, PortForward{Protocol: TCPProtocol, HostPort: 8080, GuestPort: 30080}, pf)
+
+	pf, err = ParsePortForward("5353:53/udp")
+	require.NoError(t, err)
+	assert.Equal(t, PortForward{Protocol: UDPProtocol, HostPort: 5353, GuestPort: 53}, pf)
+	assert.Equal(t, "5353:53/udp", pf.String())
+
+	for _, invalid := range []string{"8080", "8080:", ":80", "0:80", "70000:80", "8080:80/sctp", "a:b"} {
+		_, err := ParsePortForward(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseHostPort(t *testing.T) {
+	pf, err := ParseHostPort("8080/udp")
+	require.NoError(t, err)
+	assert.True(t, pf.Matches(PortForward{Protocol: UDPProtocol, HostPort: 8080, GuestPort: 80}))
+	assert.False(t, pf.Matches(PortForward{Protocol: TCPProtocol, HostPort: 8080, GuestPort: 80}))
+}
+
+func TestParsePortForwards(t *testing.T) {
+	portForwards, err := ParsePortForwards("8080:30080, 5432:5432,5353:53/udp")
+	require.NoError(t, err)
+	assert.Len(t, portForwards, 3)
+	assert.Equal(t, "8080:30080/tcp,5432:5432/tcp,5353:53/udp", FormatPortForwards(portForwards))
+
+	portForwards, err = ParsePortForwards("")
+	require.NoError(t, err)
+	assert.Empty(t, portForwards)
+
+	_, err = ParsePortForwards("8080:80,8080:81")
+	assert.EqualError(t, err, "host port 8080/tcp is forwarded more than once")
+	_, err = ParsePortForwards("8080:80,8080:81/udp")
+	assert.NoError(t, err)
+}
+
+func TestCheckReservedHostPorts(t *testing.T) {
+	reserved := map[uint]string{6443: "the API server forward"}
+	portForwards, err := ParsePortForwards("8080:80,6443:6443/udp")
+	require.NoError(t, err)
+	assert.NoError(t, CheckReservedHostPorts(portForwards, reserved))
+
+	portForwards, err = ParsePortForwards("8080:80,6443:6443")
+	require.NoError(t, err)
+	assert.EqualError(t, CheckReservedHostPorts(portForwards, reserved), "host port 6443/tcp is already used by the API server forward")
+
+	ok, msg := ValidatePortForwards("6443:443", reserved)
+	assert.False(t, ok)
+	assert.Equal(t, "host port 6443/tcp is already used by the API server forward", msg)
+}
@@ -0,0 +1,172 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	DefaultUserNetworkSubnet     = "192.168.127.0/24"
+	DefaultUserNetworkGatewayMAC = "5a:94:ef:e4:0c:dd"
+
+	// HostDNSRecordTarget can be used instead of an IP address in DNS records
+	// to resolve a name to the address of the host in the user mode network
+	HostDNSRecordTarget = "host"
+)
+
+// UserNetwork describes the virtual network provided by the daemon with the user network mode
+type UserNetwork struct {
+	Subnet     *net.IPNet
+	GatewayMAC string
+	// GatewayIP is the first address of the subnet
+	GatewayIP net.IP
+	// InstanceIP is the second address of the subnet, leased to the instance
+	InstanceIP net.IP
+	// HostVirtualIP is the last address of the subnet, it's NATed to the host
+	HostVirtualIP net.IP
+	DNSRecords    []DNSRecord
+}
+
+// DNSRecord is a static DNS record served by the daemon. When Name starts
+// with '*.', IP is the default answer for the whole domain.
+type DNSRecord struct {
+	Name string
+	IP   net.IP
+}
+
+// Wildcard returns true if the record answers for a whole domain
+func (r DNSRecord) Wildcard() bool {
+	return strings.HasPrefix(r.Name, "*.")
+}
+
+// Zone returns the DNS zone the record belongs to, which is its parent domain
+func (r DNSRecord) Zone() string {
+	_, zone, _ := strings.Cut(r.Name, ".")
+	return zone
+}
+
+// Label returns the name of the record relative to its zone
+func (r DNSRecord) Label() string {
+	label, _, _ := strings.Cut(r.Name, ".")
+	return label
+}
+
+// DefaultUserNetwork returns the network used when none of the user network settings are changed
+func DefaultUserNetwork() *UserNetwork {
+	n, err := ParseUserNetwork(DefaultUserNetworkSubnet, DefaultUserNetworkGatewayMAC, "")
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// ParseUserNetwork parses the user network settings. dnsRecords is a
+// comma-separated list of 'name=IP' where IP can also be 'host'.
+func ParseUserNetwork(subnet, gatewayMAC, dnsRecords string) (*UserNetwork, error) {
+	ipNet, err := parseIPv4Subnet(subnet)
+	if err != nil {
+		return nil, err
+	}
+	if ones, _ := ipNet.Mask.Size(); ones > 29 {
+		return nil, fmt.Errorf("subnet %s is too small", ipNet)
+	}
+	if err := validateMAC(gatewayMAC); err != nil {
+		return nil, err
+	}
+	n := &UserNetwork{
+		Subnet:        ipNet,
+		GatewayMAC:    gatewayMAC,
+		GatewayIP:     addToIP(ipNet.IP, 1),
+		InstanceIP:    addToIP(ipNet.IP, 2),
+		HostVirtualIP: addToIP(broadcastAddress(ipNet), ^uint32(0)),
+	}
+	if n.DNSRecords, err = parseDNSRecords(dnsRecords, n.HostVirtualIP); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func ValidateUserNetworkSubnet(val interface{}) (bool, string) {
+	if _, err := ParseUserNetwork(cast.ToString(val), DefaultUserNetworkGatewayMAC, ""); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func ValidateMACAddress(val interface{}) (bool, string) {
+	if err := validateMAC(cast.ToString(val)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func ValidateDNSRecords(val interface{}) (bool, string) {
+	if _, err := parseDNSRecords(cast.ToString(val), nil); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func validateMAC(mac string) error {
+	hw, err := net.ParseMAC(mac)
+	if err != nil || len(hw) != 6 {
+		return fmt.Errorf("invalid MAC address '%s'", mac)
+	}
+	return nil
+}
+
+func parseDNSRecords(records string, hostIP net.IP) ([]DNSRecord, error) {
+	var dnsRecords []DNSRecord
+	for _, spec := range strings.Split(records, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		name, ip, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid DNS record '%s', expected 'name=IP'", spec)
+		}
+		record := DNSRecord{Name: strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")}
+		if err := validateRecordName(record.Name); err != nil {
+			return nil, err
+		}
+		if ip = strings.TrimSpace(ip); ip == HostDNSRecordTarget {
+			record.IP = hostIP
+		} else {
+			parsed, err := parseIPv4(ip)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DNS record '%s': %w", spec, err)
+			}
+			record.IP = parsed
+		}
+		for _, existing := range dnsRecords {
+			if existing.Name == record.Name {
+				return nil, fmt.Errorf("DNS record '%s' is defined more than once", record.Name)
+			}
+		}
+		dnsRecords = append(dnsRecords, record)
+	}
+	return dnsRecords, nil
+}
+
+func validateRecordName(name string) error {
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("invalid DNS name '%s', it must have a parent domain", name)
+	}
+	for i, label := range labels {
+		if i == 0 && label == "*" {
+			continue
+		}
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") ||
+			strings.IndexFunc(label, func(r rune) bool {
+				return (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-'
+			}) >= 0 {
+			return fmt.Errorf("invalid DNS name '%s'", name)
+		}
+	}
+	return nil
+}
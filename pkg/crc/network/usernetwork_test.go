@@ -0,0 +1,55 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultUserNetwork(t *testing.T) {
+	n := DefaultUserNetwork()
+	assert.Equal(t, "192.168.127.0/24", n.Subnet.String())
+	assert.Equal(t, "5a:94:ef:e4:0c:dd", n.GatewayMAC)
+	assert.Equal(t, "192.168.127.1", n.GatewayIP.String())
+	assert.Equal(t, "192.168.127.2", n.InstanceIP.String())
+	assert.Equal(t, "192.168.127.254", n.HostVirtualIP.String())
+	assert.Empty(t, n.DNSRecords)
+}
+
+func TestParseUserNetwork(t *testing.T) {
+	n, err := ParseUserNetwork("10.10.0.0/16", "5a:94:ef:00:00:01", "Registry.Corp.Local.=host, *.dev.test=10.0.0.5")
+	require.NoError(t, err)
+	assert.Equal(t, "10.10.0.1", n.GatewayIP.String())
+	assert.Equal(t, "10.10.0.2", n.InstanceIP.String())
+	assert.Equal(t, "10.10.255.254", n.HostVirtualIP.String())
+	require.Len(t, n.DNSRecords, 2)
+
+	assert.Equal(t, "registry.corp.local", n.DNSRecords[0].Name)
+	assert.Equal(t, "corp.local", n.DNSRecords[0].Zone())
+	assert.Equal(t, "registry", n.DNSRecords[0].Label())
+	assert.False(t, n.DNSRecords[0].Wildcard())
+	assert.Equal(t, "10.10.255.254", n.DNSRecords[0].IP.String())
+
+	assert.Equal(t, "dev.test", n.DNSRecords[1].Zone())
+	assert.True(t, n.DNSRecords[1].Wildcard())
+	assert.Equal(t, "10.0.0.5", n.DNSRecords[1].IP.String())
+}
+
+func TestParseUserNetworkErrors(t *testing.T) {
+	for _, tc := range []struct {
+		subnet, gatewayMAC, dnsRecords string
+		err                            string
+	}{
+		{"192.168.127.0/30", DefaultUserNetworkGatewayMAC, "", "subnet 192.168.127.0/30 is too small"},
+		{"192.168.127.0/24", "5a:94:ef", "", "invalid MAC address '5a:94:ef'"},
+		{"192.168.127.0/24", DefaultUserNetworkGatewayMAC, "registry.corp.local", "invalid DNS record 'registry.corp.local', expected 'name=IP'"},
+		{"192.168.127.0/24", DefaultUserNetworkGatewayMAC, "registry=10.0.0.1", "invalid DNS name 'registry', it must have a parent domain"},
+		{"192.168.127.0/24", DefaultUserNetworkGatewayMAC, "reg_istry.corp=10.0.0.1", "invalid DNS name 'reg_istry.corp'"},
+		{"192.168.127.0/24", DefaultUserNetworkGatewayMAC, "registry.corp=gateway", "invalid DNS record 'registry.corp=gateway': invalid IPv4 address 'gateway'"},
+		{"192.168.127.0/24", DefaultUserNetworkGatewayMAC, "a.corp=10.0.0.1,A.corp=10.0.0.2", "DNS record 'a.corp' is defined more than once"},
+	} {
+		_, err := ParseUserNetwork(tc.subnet, tc.gatewayMAC, tc.dnsRecords)
+		assert.EqualError(t, err, tc.err)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/errors"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+	crcpreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 )
 
 type Flags uint32
@@ -151,38 +154,85 @@ func doRegisterSettings(cfg crcConfig.Schema, checks []Check) {
 	}
 }
 
-func getPreflightChecksHelper(config crcConfig.Storage) []Check {
-	experimentalFeatures := config.Get(crcConfig.ExperimentalFeatures).AsBool()
-	mode := crcConfig.GetNetworkMode(config)
-	bundlePath := config.Get(crcConfig.Bundle).AsString()
-	preset := crcConfig.GetPreset(config)
-	enableBundleQuayFallback := config.Get(crcConfig.EnableBundleQuayFallback).AsBool()
-	logging.Infof("Using bundle path %s", bundlePath)
-	return getPreflightChecks(experimentalFeatures, mode, bundlePath, preset, enableBundleQuayFallback)
+// checkOptions holds the configuration values which determine the preflight checks to run
+type checkOptions struct {
+	networkMode              network.Mode
+	bundlePath               string
+	preset                   crcpreset.Preset
+	bundleMirror             string
+	enableBundleQuayFallback bool
+	libvirtNetwork           *network.LibvirtNetwork
+	trustClusterCA           bool
+	// name of the instance whose cluster is set up
+	name string
 }
 
-// StartPreflightChecks performs the preflight checks before starting the cluster
-func StartPreflightChecks(config crcConfig.Storage) error {
-	if err := doPreflightChecks(config, getPreflightChecksHelper(config)); err != nil {
+// defaultCheckOptions returns the options used to list all the preflight checks,
+// regardless of the configuration
+func defaultCheckOptions(networkMode network.Mode, libvirtNetwork *network.LibvirtNetwork) checkOptions {
+	return checkOptions{
+		networkMode:    networkMode,
+		bundlePath:     constants.GetDefaultBundlePath(crcpreset.OpenShift),
+		preset:         crcpreset.OpenShift,
+		libvirtNetwork: libvirtNetwork,
+		name:           constants.DefaultName,
+	}
+}
+
+func getPreflightChecksHelper(config crcConfig.Storage, name string) ([]Check, error) {
+	libvirtNetwork, err := crcConfig.GetLibvirtNetwork(config)
+	if err != nil {
+		return nil, err
+	}
+	opts := checkOptions{
+		networkMode:              crcConfig.GetNetworkMode(config),
+		bundlePath:               config.Get(crcConfig.Bundle).AsString(),
+		preset:                   crcConfig.GetPreset(config),
+		bundleMirror:             config.Get(crcConfig.BundleMirror).AsString(),
+		enableBundleQuayFallback: config.Get(crcConfig.EnableBundleQuayFallback).AsBool(),
+		libvirtNetwork:           libvirtNetwork,
+		trustClusterCA:           config.Get(crcConfig.TrustClusterCA).AsBool(),
+		name:                     name,
+	}
+	logging.Infof("Using bundle path %s", opts.bundlePath)
+	return getPreflightChecks(opts), nil
+}
+
+// StartPreflightChecks performs the preflight checks before starting the cluster of the instance called name
+func StartPreflightChecks(config crcConfig.Storage, name string) error {
+	checks, err := getPreflightChecksHelper(config, name)
+	if err != nil {
+		return err
+	}
+	if err := doPreflightChecks(config, checks); err != nil {
 		return &errors.PreflightError{Err: err}
 	}
 	return nil
 }
 
-// SetupHost performs the prerequisite checks and setups the host to run the cluster
-func SetupHost(config crcConfig.Storage, checkOnly bool) error {
-	return doFixPreflightChecks(config, getPreflightChecksHelper(config), checkOnly)
+// SetupHost performs the prerequisite checks and setups the host to run the cluster of the instance called name
+func SetupHost(config crcConfig.Storage, name string, checkOnly bool) error {
+	checks, err := getPreflightChecksHelper(config, name)
+	if err != nil {
+		return err
+	}
+	return doFixPreflightChecks(config, checks, checkOnly)
 }
 
 func RegisterSettings(config crcConfig.Schema) {
-	doRegisterSettings(config, getAllPreflightChecks())
+	doRegisterSettings(config, getAllPreflightChecks(network.DefaultLibvirtNetwork()))
 }
 
-func CleanUpHost() error {
+func CleanUpHost(config crcConfig.Storage) error {
+	libvirtNetwork, err := crcConfig.GetLibvirtNetwork(config)
+	if err != nil {
+		logging.Warnf("Cleaning up the default libvirt network: %v", err)
+		libvirtNetwork = network.DefaultLibvirtNetwork()
+	}
 	// A user can use setup with experiment flag
 	// and not use cleanup with same flag, to avoid
 	// any extra step/confusion we are just adding the checks
 	// which are behind the experiment flag. This way cleanup
 	// perform action in a sane way.
-	return doCleanUpPreflightChecks(getAllPreflightChecks())
+	return doCleanUpPreflightChecks(getAllPreflightChecks(libvirtNetwork))
 }
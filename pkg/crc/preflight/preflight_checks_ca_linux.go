@@ -0,0 +1,207 @@
+package preflight
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+)
+
+const clusterCATrustFileName = "crc-cluster-ca.crt"
+
+// trustStore is a directory of the host read by the tool updating the system CA bundle
+type trustStore struct {
+	anchorsDir string
+	updateCmd  []string
+}
+
+var trustStores = []trustStore{
+	// Fedora, RHEL
+	{anchorsDir: "/etc/pki/ca-trust/source/anchors", updateCmd: []string{"update-ca-trust", "extract"}},
+	// Debian, Ubuntu
+	{anchorsDir: "/usr/local/share/ca-certificates", updateCmd: []string{"update-ca-certificates"}},
+}
+
+func clusterCATrustCheck(bundlePath string, name string) Check {
+	return Check{
+		configKeySuffix:    "check-cluster-ca-trusted",
+		checkDescription:   "Checking if the CA of the cluster is trusted by the system",
+		check:              checkClusterCATrusted(bundlePath, name),
+		fixDescription:     "Adding the CA of the cluster to the system trust store",
+		fix:                fixClusterCATrusted(bundlePath, name),
+		flags:              SetupOnly,
+		cleanupDescription: "Removing the CA of the cluster from the system trust store",
+		cleanup:            removeClusterCATrust,
+
+		labels: labels{Os: Linux},
+	}
+}
+
+func hostTrustStore() (*trustStore, error) {
+	for i := range trustStores {
+		if _, err := os.Stat(trustStores[i].anchorsDir); err == nil {
+			return &trustStores[i], nil
+		}
+	}
+	return nil, errors.New("cannot find the system trust store, only the p11-kit and ca-certificates ones are supported")
+}
+
+// clusterCAPath returns the path of the CAs of the cluster of the instance
+// called name in the trust store
+func (store *trustStore) clusterCAPath(name string) string {
+	if name == constants.DefaultName {
+		return filepath.Join(store.anchorsDir, clusterCATrustFileName)
+	}
+	return filepath.Join(store.anchorsDir, fmt.Sprintf("crc-%s-cluster-ca.crt", name))
+}
+
+func (store *trustStore) update() error {
+	stdOut, stdErr, err := crcos.RunPrivileged("Updating the system trust store", store.updateCmd...)
+	if err != nil {
+		return fmt.Errorf("failed to update the system trust store: %s %s: %w", stdOut, stdErr, err)
+	}
+	return nil
+}
+
+// errNoClusterCA is returned when 'crc start' has not generated the router CA of the instance yet
+var errNoClusterCA = errors.New("no router CA in the instance directory")
+
+// clusterCA returns the CAs of the certificates served by the router of the
+// instance called name, which are kept in the instance directory by 'crc
+// start'. The CA of the bundle is never trusted as its key is shipped with
+// every bundle, and the CAs are refused when their name constraints do not
+// limit them to the domains of the cluster.
+func clusterCA(bundlePath string, name string) ([]byte, error) {
+	bundleName, err := bundle.GetBundleNameFromURI(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	bundleInfo, err := bundle.Get(bundleName)
+	if err != nil {
+		return nil, err
+	}
+	domains := []string{
+		bundleInfo.ClusterInfo.AppsDomain,
+		fmt.Sprintf("%s.%s", bundleInfo.ClusterInfo.ClusterName, bundleInfo.ClusterInfo.BaseDomain),
+	}
+	if appsDomain, err := os.ReadFile(constants.GetAppsDomainPath(name)); err == nil {
+		domains = append(domains, strings.TrimSpace(string(appsDomain)))
+	}
+	var cas []byte
+	for _, path := range []string{constants.GetIngressCACertPath(name), constants.GetCustomCACertPath(name)} {
+		caPEM, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ca, err := constrainedCAs(path, caPEM, domains)
+		if err != nil {
+			return nil, err
+		}
+		cas = append(cas, ca...)
+	}
+	if len(cas) == 0 {
+		return nil, errNoClusterCA
+	}
+	return cas, nil
+}
+
+// constrainedCAs returns the certificates of caPEM, or an error if the name
+// constraints of one of them do not limit it to domains or their subdomains
+func constrainedCAs(path string, caPEM []byte, domains []string) ([]byte, error) {
+	var cas []byte
+	for block, rest := pem.Decode(caPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the certificate from %s: %w", path, err)
+		}
+		if !isConstrainedTo(cert, domains) {
+			return nil, fmt.Errorf("the name constraints of the CA %s from %s do not limit it to the domains of the cluster, it cannot be added to the system trust store", cert.Subject.CommonName, path)
+		}
+		cas = append(cas, pem.EncodeToMemory(block)...)
+	}
+	return cas, nil
+}
+
+func isConstrainedTo(cert *x509.Certificate, domains []string) bool {
+	if !cert.PermittedDNSDomainsCritical || len(cert.PermittedDNSDomains) == 0 {
+		return false
+	}
+	for _, permitted := range cert.PermittedDNSDomains {
+		if !slices.ContainsFunc(domains, func(domain string) bool {
+			return permitted == domain || strings.HasSuffix(permitted, "."+domain)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkClusterCATrusted(bundlePath string, name string) func() error {
+	return func() error {
+		store, err := hostTrustStore()
+		if err != nil {
+			return err
+		}
+		ca, err := clusterCA(bundlePath, name)
+		if errors.Is(err, errNoClusterCA) {
+			logging.Warnf("The router CA of the %s instance is generated by 'crc start', run 'crc setup' again after it to trust it", name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return crcos.FileContentMatches(store.clusterCAPath(name), ca)
+	}
+}
+
+func fixClusterCATrusted(bundlePath string, name string) func() error {
+	return func() error {
+		store, err := hostTrustStore()
+		if err != nil {
+			return err
+		}
+		ca, err := clusterCA(bundlePath, name)
+		if err != nil {
+			return err
+		}
+		if err := crcos.WriteToFileAsRoot(fmt.Sprintf("Writing the CA of the cluster to %s", store.clusterCAPath(name)), string(ca), store.clusterCAPath(name), 0644); err != nil {
+			return err
+		}
+		return store.update()
+	}
+}
+
+// removeClusterCATrust removes the CAs of the clusters of all the instances from the trust store
+func removeClusterCATrust() error {
+	for i := range trustStores {
+		store := &trustStores[i]
+		paths, err := filepath.Glob(filepath.Join(store.anchorsDir, "crc-*cluster-ca.crt"))
+		if err != nil || len(paths) == 0 {
+			continue
+		}
+		for _, path := range paths {
+			if err := crcos.RemoveFileAsRoot(fmt.Sprintf("Removing %s", path), path); err != nil {
+				return err
+			}
+		}
+		if err := store.update(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
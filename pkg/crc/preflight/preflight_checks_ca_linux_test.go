@@ -0,0 +1,30 @@
+package preflight
+
+import (
+	"testing"
+
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstrainedCAs(t *testing.T) {
+	domains := []string{"apps-crc.testing", "crc.testing", "apps.dev.example.com"}
+
+	_, constrainedCA, err := crctls.GetSelfSignedIngressCA([]string{"apps.dev.example.com", "apps-crc.testing"})
+	require.NoError(t, err)
+	constrainedPEM := crctls.CertToPem(constrainedCA)
+	cas, err := constrainedCAs("ingress-ca.crt", constrainedPEM, domains)
+	assert.NoError(t, err)
+	assert.Equal(t, constrainedPEM, cas)
+
+	_, otherDomainCA, err := crctls.GetSelfSignedIngressCA([]string{"example.com"})
+	require.NoError(t, err)
+	_, err = constrainedCAs("custom-ca.crt", append(constrainedPEM, crctls.CertToPem(otherDomainCA)...), domains)
+	assert.ErrorContains(t, err, "do not limit it to the domains of the cluster")
+
+	_, unconstrainedCA, err := crctls.GetSelfSignedCA()
+	require.NoError(t, err)
+	_, err = constrainedCAs("custom-ca.crt", crctls.CertToPem(unconstrainedCA), domains)
+	assert.ErrorContains(t, err, "do not limit it to the domains of the cluster")
+}
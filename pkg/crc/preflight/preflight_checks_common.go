@@ -21,13 +21,13 @@ import (
 	"github.com/pkg/errors"
 )
 
-func bundleCheck(bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) Check {
+func bundleCheck(bundlePath string, preset crcpreset.Preset, bundleMirror string, enableBundleQuayFallback bool) Check {
 	return Check{
 		configKeySuffix:  "check-bundle-extracted",
 		checkDescription: "Checking if CRC bundle is extracted in '$HOME/.crc'",
 		check:            checkBundleExtracted(bundlePath),
 		fixDescription:   "Getting bundle for the CRC executable",
-		fix:              fixBundleExtracted(bundlePath, preset, enableBundleQuayFallback),
+		fix:              fixBundleExtracted(bundlePath, preset, bundleMirror, enableBundleQuayFallback),
 		flags:            SetupOnly,
 
 		labels: None,
@@ -48,48 +48,53 @@ func memoryCheck(preset crcpreset.Preset) Check {
 	}
 }
 
-var genericCleanupChecks = []Check{
-	{
-		cleanupDescription: "Removing CRC Machine Instance directory",
-		cleanup:            removeCRCMachinesDir,
-		flags:              CleanUpOnly,
-
-		labels: None,
-	},
-	{
-		cleanupDescription: "Removing older logs",
-		cleanup:            removeAllLogs,
-		flags:              CleanUpOnly,
+// genericCleanupChecks returns the cleanup checks common to all platforms,
+// instanceIP is the address of the instance when it is not reached through
+// vsock, it can be empty
+func genericCleanupChecks(instanceIP string) []Check {
+	return []Check{
+		{
+			cleanupDescription: "Removing CRC Machine Instance directory",
+			cleanup:            removeCRCMachinesDir,
+			flags:              CleanUpOnly,
+
+			labels: None,
+		},
+		{
+			cleanupDescription: "Removing older logs",
+			cleanup:            removeAllLogs,
+			flags:              CleanUpOnly,
 
-		labels: None,
-	},
-	{
-		cleanupDescription: "Removing pull secret from the keyring",
-		cleanup:            cluster.ForgetPullSecret,
-		flags:              CleanUpOnly,
+			labels: None,
+		},
+		{
+			cleanupDescription: "Removing pull secret from the keyring",
+			cleanup:            cluster.ForgetPullSecret,
+			flags:              CleanUpOnly,
 
-		labels: None,
-	},
-	{
-		cleanupDescription: "Removing hosts file records added by CRC",
-		cleanup:            removeHostsFileEntry,
-		flags:              CleanUpOnly,
+			labels: None,
+		},
+		{
+			cleanupDescription: "Removing hosts file records added by CRC",
+			cleanup:            removeHostsFileEntry,
+			flags:              CleanUpOnly,
 
-		labels: None,
-	},
-	{
-		cleanupDescription: "Removing CRC Specific entries from user's known_hosts file",
-		cleanup:            removeCRCHostEntriesFromKnownHosts,
-		flags:              CleanUpOnly,
+			labels: None,
+		},
+		{
+			cleanupDescription: "Removing CRC Specific entries from user's known_hosts file",
+			cleanup:            removeCRCHostEntriesFromKnownHosts(instanceIP),
+			flags:              CleanUpOnly,
 
-		labels: None,
-	},
-	{
-		cleanupDescription: "Removing CRC manpages",
-		cleanup:            removeCrcManPages,
-		flags:              CleanUpOnly,
-		labels:             None,
-	},
+			labels: None,
+		},
+		{
+			cleanupDescription: "Removing CRC manpages",
+			cleanup:            removeCrcManPages,
+			flags:              CleanUpOnly,
+			labels:             None,
+		},
+	}
 }
 
 func checkBundleExtracted(bundlePath string) func() error {
@@ -109,7 +114,7 @@ func checkBundleExtracted(bundlePath string) func() error {
 	}
 }
 
-func fixBundleExtracted(bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) func() error {
+func fixBundleExtracted(bundlePath string, preset crcpreset.Preset, bundleMirror string, enableBundleQuayFallback bool) func() error {
 	// Should be removed after 1.19 release
 	// This check will ensure correct mode for `~/.crc/cache` directory
 	// in case it exists.
@@ -125,7 +130,7 @@ func fixBundleExtracted(bundlePath string, preset crcpreset.Preset, enableBundle
 		}
 		var err error
 		logging.Infof("Downloading bundle: %s...", bundlePath)
-		if bundlePath, err = bundle.Download(context.Background(), preset, bundlePath, enableBundleQuayFallback); err != nil {
+		if bundlePath, err = bundle.Download(context.Background(), preset, bundlePath, bundleMirror, enableBundleQuayFallback); err != nil {
 			return err
 		}
 
@@ -169,6 +174,12 @@ func removeCrcManPages() error {
 	return manpages.RemoveCrcManPages(constants.CrcManPageDir)
 }
 
-func removeCRCHostEntriesFromKnownHosts() error {
-	return ssh.RemoveCRCHostEntriesFromKnownHosts()
+func removeCRCHostEntriesFromKnownHosts(instanceIP string) func() error {
+	return func() error {
+		hosts := []string{ssh.KnownHostsEntry(constants.LocalIP, constants.VsockSSHPort)}
+		if instanceIP != "" {
+			hosts = append(hosts, ssh.KnownHostsEntry(instanceIP, constants.DefaultSSHPort))
+		}
+		return ssh.RemoveCRCHostEntriesFromKnownHosts(hosts...)
+	}
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/libvirt"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/systemd"
 	"github.com/crc-org/crc/v2/pkg/crc/systemd/states"
 	crcos "github.com/crc-org/crc/v2/pkg/os"
@@ -516,21 +517,28 @@ func fixMachineDriverLibvirtInstalled() error {
 	return nil
 }
 
-func checkLibvirtCrcNetworkAvailable() error {
+func checkLibvirtCrcNetworkAvailable(libvirtNetwork *network.LibvirtNetwork) error {
 	logging.Debug("Checking if libvirt 'crc' network exists")
 	_, _, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "net-info", "crc")
 	if err != nil {
 		return fmt.Errorf("libvirt network crc not found")
 	}
 
-	return checkLibvirtCrcNetworkDefinition()
+	return checkLibvirtCrcNetworkDefinition(libvirtNetwork)
 }
 
-func getLibvirtNetworkXML() (string, error) {
+func getLibvirtNetworkXML(libvirtNetwork *network.LibvirtNetwork) (string, error) {
 	config := libvirt.NetworkConfig{
 		NetworkName: libvirt.DefaultNetwork,
+		Bridge:      libvirtNetwork.Bridge,
+		Gateway:     libvirtNetwork.Gateway.String(),
+		Prefix:      libvirtNetwork.Prefix(),
 		MAC:         libvirt.MACAddress,
-		IP:          libvirt.IPAddress,
+		IP:          libvirtNetwork.InstanceIP.String(),
+	}
+	if libvirtNetwork.DHCPStart != nil {
+		config.DHCPStart = libvirtNetwork.DHCPStart.String()
+		config.DHCPEnd = libvirtNetwork.DHCPEnd.String()
 	}
 	t, err := template.New("netxml").Parse(libvirt.NetworkTemplate)
 	if err != nil {
@@ -545,17 +553,18 @@ func getLibvirtNetworkXML() (string, error) {
 	return netXMLDef.String(), nil
 }
 
-func fixLibvirtCrcNetworkAvailable() error {
+func fixLibvirtCrcNetworkAvailable(libvirtNetwork *network.LibvirtNetwork) error {
 	logging.Debug("Creating libvirt 'crc' network")
 
-	netXMLDef, err := getLibvirtNetworkXML()
+	netXMLDef, err := getLibvirtNetworkXML(libvirtNetwork)
 	if err != nil {
 		logging.Debugf("getLibvirtNetworkXML() failed: %v", err)
 		return fmt.Errorf("failed to read libvirt 'crc' network definition")
 	}
 
-	// For time being we are going to override the crc network according what we have in our binary template.
-	// We also don't care about the error or output from those commands atm.
+	// The crc network is overridden with the definition generated from our template and the configuration,
+	// this migrates existing networks to a new subnet or bridge. We don't care about the error or output
+	// from those commands atm.
 	// #nosec G204
 	_, _, _ = crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "net-destroy", libvirt.DefaultNetwork)
 	// #nosec G204
@@ -651,7 +660,7 @@ func trimSpacesFromXML(str string) string {
 	return builder.String()
 }
 
-func checkLibvirtCrcNetworkDefinition() error {
+func checkLibvirtCrcNetworkDefinition(libvirtNetwork *network.LibvirtNetwork) error {
 	logging.Debug("Checking if libvirt 'crc' definition is up to date")
 	stdOut, _, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "net-dumpxml", "--inactive", "crc")
 	if err != nil {
@@ -659,7 +668,7 @@ func checkLibvirtCrcNetworkDefinition() error {
 	}
 	stdOut = trimSpacesFromXML(stdOut)
 
-	netXMLDef, err := getLibvirtNetworkXML()
+	netXMLDef, err := getLibvirtNetworkXML(libvirtNetwork)
 	if err != nil {
 		return fmt.Errorf("failed to generate 'crc' network XML from template: %w", err)
 	}
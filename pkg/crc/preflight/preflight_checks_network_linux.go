@@ -10,6 +10,7 @@ import (
 	"slices"
 
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/systemd"
 	"github.com/crc-org/crc/v2/pkg/crc/systemd/states"
 	crcos "github.com/crc-org/crc/v2/pkg/os"
@@ -45,37 +46,43 @@ var nmPreflightChecks = []Check{
 	},
 }
 
-var dnsmasqPreflightChecks = []Check{
-	{
-		configKeySuffix:    "check-network-manager-config",
-		checkDescription:   "Checking if /etc/NetworkManager/conf.d/crc-nm-dnsmasq.conf exists",
-		check:              checkCrcNetworkManagerConfig,
-		fixDescription:     "Writing Network Manager config for crc",
-		fix:                fixCrcNetworkManagerConfig,
-		cleanupDescription: "Removing /etc/NetworkManager/conf.d/crc-nm-dnsmasq.conf file",
-		cleanup:            removeCrcNetworkManagerConfig,
-
-		labels: labels{Os: Linux, NetworkMode: System, DNS: Dnsmasq},
-	},
-	{
-		configKeySuffix:    "check-crc-dnsmasq-file",
-		checkDescription:   "Checking if /etc/NetworkManager/dnsmasq.d/crc.conf exists",
-		check:              checkCrcDnsmasqConfigFile,
-		fixDescription:     "Writing dnsmasq config for crc",
-		fix:                fixCrcDnsmasqConfigFile,
-		cleanupDescription: "Removing /etc/NetworkManager/dnsmasq.d/crc.conf file",
-		cleanup:            removeCrcDnsmasqConfigFile,
-
-		labels: labels{Os: Linux, NetworkMode: System, DNS: Dnsmasq},
-	},
+func dnsmasqPreflightChecks(libvirtNetwork *network.LibvirtNetwork) []Check {
+	return []Check{
+		{
+			configKeySuffix:    "check-network-manager-config",
+			checkDescription:   "Checking if /etc/NetworkManager/conf.d/crc-nm-dnsmasq.conf exists",
+			check:              checkCrcNetworkManagerConfig,
+			fixDescription:     "Writing Network Manager config for crc",
+			fix:                fixCrcNetworkManagerConfig,
+			cleanupDescription: "Removing /etc/NetworkManager/conf.d/crc-nm-dnsmasq.conf file",
+			cleanup:            removeCrcNetworkManagerConfig,
+
+			labels: labels{Os: Linux, NetworkMode: System, DNS: Dnsmasq},
+		},
+		{
+			configKeySuffix:  "check-crc-dnsmasq-file",
+			checkDescription: "Checking if /etc/NetworkManager/dnsmasq.d/crc.conf exists",
+			check: func() error {
+				return checkCrcDnsmasqConfigFile(libvirtNetwork)
+			},
+			fixDescription: "Writing dnsmasq config for crc",
+			fix: func() error {
+				return fixCrcDnsmasqConfigFile(libvirtNetwork)
+			},
+			cleanupDescription: "Removing /etc/NetworkManager/dnsmasq.d/crc.conf file",
+			cleanup:            removeCrcDnsmasqConfigFile,
+
+			labels: labels{Os: Linux, NetworkMode: System, DNS: Dnsmasq},
+		},
+	}
 }
 
 var (
 	crcNetworkManagerRootPath = filepath.Join(string(filepath.Separator), "etc", "NetworkManager")
 
-	crcDnsmasqConfigPath = filepath.Join(crcNetworkManagerRootPath, "dnsmasq.d", "crc.conf")
-	crcDnsmasqConfig     = `server=/apps-crc.testing/192.168.130.11
-server=/crc.testing/192.168.130.11
+	crcDnsmasqConfigPath     = filepath.Join(crcNetworkManagerRootPath, "dnsmasq.d", "crc.conf")
+	crcDnsmasqConfigTemplate = `server=/apps-crc.testing/%[1]s
+server=/crc.testing/%[1]s
 `
 
 	crcNetworkManagerConfigPath = filepath.Join(crcNetworkManagerRootPath, "conf.d", "crc-nm-dnsmasq.conf")
@@ -83,9 +90,9 @@ server=/crc.testing/192.168.130.11
 dns=dnsmasq
 `
 
-	crcNetworkManagerOldDispatcherPath = filepath.Join(crcNetworkManagerRootPath, "dispatcher.d", "pre-up.d", "99-crc.sh")
-	crcNetworkManagerDispatcherPath    = filepath.Join(crcNetworkManagerRootPath, "dispatcher.d", "99-crc.sh")
-	crcNetworkManagerDispatcherConfig  = `#!/bin/sh
+	crcNetworkManagerOldDispatcherPath  = filepath.Join(crcNetworkManagerRootPath, "dispatcher.d", "pre-up.d", "99-crc.sh")
+	crcNetworkManagerDispatcherPath     = filepath.Join(crcNetworkManagerRootPath, "dispatcher.d", "99-crc.sh")
+	crcNetworkManagerDispatcherTemplate = `#!/bin/sh
 # This is a NetworkManager dispatcher script to configure split DNS for
 # the 'crc' libvirt network.
 #
@@ -105,42 +112,57 @@ dns=dnsmasq
 
 export LC_ALL=C
 
-systemd-resolve --interface crc --set-dns 192.168.130.11 --set-domain ~testing
+systemd-resolve --interface %s --set-dns %s --set-domain ~testing
 
 exit 0
 `
 )
 
-var systemdResolvedPreflightChecks = []Check{
-	{
-		configKeySuffix:  "check-dnsmasq-network-manager-config",
-		checkDescription: "Checking if dnsmasq configurations file exist for NetworkManager",
-		check:            checkCrcDnsmasqAndNetworkManagerConfigFile,
-		fixDescription:   "Removing dnsmasq configuration file for NetworkManager",
-		fix:              fixCrcDnsmasqAndNetworkManagerConfigFile,
+func systemdResolvedPreflightChecks(libvirtNetwork *network.LibvirtNetwork) []Check {
+	return []Check{
+		{
+			configKeySuffix:  "check-dnsmasq-network-manager-config",
+			checkDescription: "Checking if dnsmasq configurations file exist for NetworkManager",
+			check:            checkCrcDnsmasqAndNetworkManagerConfigFile,
+			fixDescription:   "Removing dnsmasq configuration file for NetworkManager",
+			fix:              fixCrcDnsmasqAndNetworkManagerConfigFile,
+
+			labels: labels{Os: Linux, NetworkMode: System, DNS: SystemdResolved},
+		},
+		{
+			configKeySuffix:  "check-systemd-resolved-running",
+			checkDescription: "Checking if the systemd-resolved service is running",
+			check:            checkSystemdResolvedIsRunning,
+			fixDescription:   "systemd-resolved is required on this distribution. Please make sure it is installed and running manually",
+			flags:            NoFix,
+
+			labels: labels{Os: Linux, NetworkMode: System, DNS: SystemdResolved},
+		},
+		{
+			configKeySuffix:  "check-network-manager-dispatcher-file",
+			checkDescription: fmt.Sprintf("Checking if %s exists", crcNetworkManagerDispatcherPath),
+			check: func() error {
+				return checkCrcNetworkManagerDispatcherFile(libvirtNetwork)
+			},
+			fixDescription: "Writing NetworkManager dispatcher file for crc",
+			fix: func() error {
+				return fixCrcNetworkManagerDispatcherFile(libvirtNetwork)
+			},
+			cleanupDescription: fmt.Sprintf("Removing %s file", crcNetworkManagerDispatcherPath),
+			cleanup:            removeCrcNetworkManagerDispatcherFile,
+
+			labels: labels{Os: Linux, NetworkMode: System, DNS: SystemdResolved},
+		},
+	}
+}
 
-		labels: labels{Os: Linux, NetworkMode: System, DNS: SystemdResolved},
-	},
-	{
-		configKeySuffix:  "check-systemd-resolved-running",
-		checkDescription: "Checking if the systemd-resolved service is running",
-		check:            checkSystemdResolvedIsRunning,
-		fixDescription:   "systemd-resolved is required on this distribution. Please make sure it is installed and running manually",
-		flags:            NoFix,
+// crcDnsmasqConfig forwards the DNS queries for the cluster domains to the instance
+func crcDnsmasqConfig(libvirtNetwork *network.LibvirtNetwork) string {
+	return fmt.Sprintf(crcDnsmasqConfigTemplate, libvirtNetwork.InstanceIP)
+}
 
-		labels: labels{Os: Linux, NetworkMode: System, DNS: SystemdResolved},
-	},
-	{
-		configKeySuffix:    "check-network-manager-dispatcher-file",
-		checkDescription:   fmt.Sprintf("Checking if %s exists", crcNetworkManagerDispatcherPath),
-		check:              checkCrcNetworkManagerDispatcherFile,
-		fixDescription:     "Writing NetworkManager dispatcher file for crc",
-		fix:                fixCrcNetworkManagerDispatcherFile,
-		cleanupDescription: fmt.Sprintf("Removing %s file", crcNetworkManagerDispatcherPath),
-		cleanup:            removeCrcNetworkManagerDispatcherFile,
-
-		labels: labels{Os: Linux, NetworkMode: System, DNS: SystemdResolved},
-	},
+func crcNetworkManagerDispatcherConfig(libvirtNetwork *network.LibvirtNetwork) string {
+	return fmt.Sprintf(crcNetworkManagerDispatcherTemplate, libvirtNetwork.Bridge, libvirtNetwork.InstanceIP)
 }
 
 func fixNetworkManagerConfigFile(path string, content string, perms os.FileMode) error {
@@ -187,9 +209,9 @@ func removeNetworkManagerConfigFile(path string) error {
 	return nil
 }
 
-func checkCrcDnsmasqConfigFile() error {
+func checkCrcDnsmasqConfigFile(libvirtNetwork *network.LibvirtNetwork) error {
 	logging.Debug("Checking dnsmasq configuration")
-	err := crcos.FileContentMatches(crcDnsmasqConfigPath, []byte(crcDnsmasqConfig))
+	err := crcos.FileContentMatches(crcDnsmasqConfigPath, []byte(crcDnsmasqConfig(libvirtNetwork)))
 	if err != nil {
 		return err
 	}
@@ -197,9 +219,9 @@ func checkCrcDnsmasqConfigFile() error {
 	return nil
 }
 
-func fixCrcDnsmasqConfigFile() error {
+func fixCrcDnsmasqConfigFile(libvirtNetwork *network.LibvirtNetwork) error {
 	logging.Debug("Fixing dnsmasq configuration")
-	err := fixNetworkManagerConfigFile(crcDnsmasqConfigPath, crcDnsmasqConfig, 0644)
+	err := fixNetworkManagerConfigFile(crcDnsmasqConfigPath, crcDnsmasqConfig(libvirtNetwork), 0644)
 	if err != nil {
 		return err
 	}
@@ -292,9 +314,9 @@ func checkSystemdResolvedIsRunning() error {
 	return checkSystemdServiceRunning("systemd-resolved.service")
 }
 
-func checkCrcNetworkManagerDispatcherFile() error {
+func checkCrcNetworkManagerDispatcherFile(libvirtNetwork *network.LibvirtNetwork) error {
 	logging.Debug("Checking NetworkManager dispatcher file for crc network")
-	err := crcos.FileContentMatches(crcNetworkManagerDispatcherPath, []byte(crcNetworkManagerDispatcherConfig))
+	err := crcos.FileContentMatches(crcNetworkManagerDispatcherPath, []byte(crcNetworkManagerDispatcherConfig(libvirtNetwork)))
 	if err != nil {
 		return err
 	}
@@ -302,13 +324,13 @@ func checkCrcNetworkManagerDispatcherFile() error {
 	return nil
 }
 
-func fixCrcNetworkManagerDispatcherFile() error {
+func fixCrcNetworkManagerDispatcherFile(libvirtNetwork *network.LibvirtNetwork) error {
 	logging.Debug("Fixing NetworkManager dispatcher configuration")
 
 	// Remove dispatcher script which was used in crc 1.20 - it's been moved to a new location
 	_ = removeNetworkManagerConfigFile(crcNetworkManagerOldDispatcherPath)
 
-	err := fixNetworkManagerConfigFile(crcNetworkManagerDispatcherPath, crcNetworkManagerDispatcherConfig, 0755)
+	err := fixNetworkManagerConfigFile(crcNetworkManagerDispatcherPath, crcNetworkManagerDispatcherConfig(libvirtNetwork), 0755)
 	if err != nil {
 		return err
 	}
@@ -3,9 +3,7 @@ package preflight
 import (
 	"fmt"
 
-	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
-	crcpreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/crc-org/crc/v2/pkg/os/darwin/launchd"
 )
 
@@ -108,21 +106,22 @@ var daemonLaunchdChecks = []Check{
 //
 // Passing 'SystemNetworkingMode' to getPreflightChecks currently achieves this
 // as there are no user networking specific checks
-func getAllPreflightChecks() []Check {
-	return getPreflightChecks(true, network.SystemNetworkingMode, constants.GetDefaultBundlePath(crcpreset.OpenShift), crcpreset.OpenShift, false)
+func getAllPreflightChecks(libvirtNetwork *network.LibvirtNetwork) []Check {
+	return getPreflightChecks(defaultCheckOptions(network.SystemNetworkingMode, libvirtNetwork))
 }
 
-func getChecks(_ network.Mode, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getChecks(opts checkOptions) []Check {
 	checks := []Check{}
 
 	checks = append(checks, deprecationWarning)
 	checks = append(checks, nonWinPreflightChecks...)
-	checks = append(checks, genericPreflightChecks(preset)...)
-	checks = append(checks, memoryCheck(preset))
-	checks = append(checks, genericCleanupChecks...)
+	checks = append(checks, genericPreflightChecks(opts.preset)...)
+	checks = append(checks, memoryCheck(opts.preset))
+	// the instance is reached through vsock or localhost
+	checks = append(checks, genericCleanupChecks("")...)
 	checks = append(checks, vfkitPreflightChecks...)
 	checks = append(checks, resolverPreflightChecks...)
-	checks = append(checks, bundleCheck(bundlePath, preset, enableBundleQuayFallback))
+	checks = append(checks, bundleCheck(opts.bundlePath, opts.preset, opts.bundleMirror, opts.enableBundleQuayFallback))
 	checks = append(checks, trayLaunchdCleanupChecks...)
 	checks = append(checks, daemonLaunchdChecks...)
 	checks = append(checks, sshPortCheck())
@@ -130,9 +129,9 @@ func getChecks(_ network.Mode, bundlePath string, preset crcpreset.Preset, enabl
 	return checks
 }
 
-func getPreflightChecks(_ bool, mode network.Mode, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getPreflightChecks(opts checkOptions) []Check {
 	filter := newFilter()
-	filter.SetNetworkMode(mode)
+	filter.SetNetworkMode(opts.networkMode)
 
-	return filter.Apply(getChecks(mode, bundlePath, preset, enableBundleQuayFallback))
+	return filter.Apply(getChecks(opts))
 }
@@ -6,11 +6,9 @@ import (
 	"os"
 	"strings"
 
-	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
-	crcpreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 	crcos "github.com/crc-org/crc/v2/pkg/os"
 	"github.com/crc-org/crc/v2/pkg/os/linux"
 
@@ -138,27 +136,33 @@ func libvirtPreflightChecks(distro *linux.OsRelease) []Check {
 	return checks
 }
 
-var libvirtNetworkPreflightChecks = []Check{
-	{
-		configKeySuffix:    "check-crc-network",
-		checkDescription:   "Checking if libvirt 'crc' network is available",
-		check:              checkLibvirtCrcNetworkAvailable,
-		fixDescription:     "Setting up libvirt 'crc' network",
-		fix:                fixLibvirtCrcNetworkAvailable,
-		cleanupDescription: "Removing 'crc' network from libvirt",
-		cleanup:            removeLibvirtCrcNetwork,
-
-		labels: labels{Os: Linux, NetworkMode: System},
-	},
-	{
-		configKeySuffix:  "check-crc-network-active",
-		checkDescription: "Checking if libvirt 'crc' network is active",
-		check:            checkLibvirtCrcNetworkActive,
-		fixDescription:   "Starting libvirt 'crc' network",
-		fix:              fixLibvirtCrcNetworkActive,
-
-		labels: labels{Os: Linux, NetworkMode: System},
-	},
+func libvirtNetworkPreflightChecks(libvirtNetwork *network.LibvirtNetwork) []Check {
+	return []Check{
+		{
+			configKeySuffix:  "check-crc-network",
+			checkDescription: "Checking if libvirt 'crc' network is available",
+			check: func() error {
+				return checkLibvirtCrcNetworkAvailable(libvirtNetwork)
+			},
+			fixDescription: "Setting up libvirt 'crc' network",
+			fix: func() error {
+				return fixLibvirtCrcNetworkAvailable(libvirtNetwork)
+			},
+			cleanupDescription: "Removing 'crc' network from libvirt",
+			cleanup:            removeLibvirtCrcNetwork,
+
+			labels: labels{Os: Linux, NetworkMode: System},
+		},
+		{
+			configKeySuffix:  "check-crc-network-active",
+			checkDescription: "Checking if libvirt 'crc' network is active",
+			check:            checkLibvirtCrcNetworkActive,
+			fixDescription:   "Starting libvirt 'crc' network",
+			fix:              fixLibvirtCrcNetworkActive,
+
+			labels: labels{Os: Linux, NetworkMode: System},
+		},
+	}
 }
 
 var vsockPreflightCheck = Check{
@@ -335,47 +339,55 @@ func (filter preflightFilter) SetSystemdUser(distro *linux.OsRelease) {
 // - matching the current distro
 // - matching the networking daemon in use (NetworkManager or systemd-resolved) regardless of user/system networking
 // - and we also want the user networking checks
-func getAllPreflightChecks() []Check {
+// - and the opt-in checks, so that 'crc cleanup' reverts them
+func getAllPreflightChecks(libvirtNetwork *network.LibvirtNetwork) []Check {
 	usingSystemdResolved := checkSystemdResolvedIsRunning()
 	filter := newFilter()
 	filter.SetSystemdResolved(usingSystemdResolved == nil)
 	filter.SetDistro(distro())
 	filter.SetSystemdUser(distro())
 
-	return filter.Apply(getChecks(distro(), constants.GetDefaultBundlePath(crcpreset.OpenShift), crcpreset.OpenShift, false))
+	opts := defaultCheckOptions(network.SystemNetworkingMode, libvirtNetwork)
+	// the cleanup removes the CAs added to the trust store when the check was enabled
+	opts.trustClusterCA = true
+	return filter.Apply(getChecks(distro(), opts))
 }
 
-func getPreflightChecks(_ bool, networkMode network.Mode, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getPreflightChecks(opts checkOptions) []Check {
 	usingSystemdResolved := checkSystemdResolvedIsRunning()
 
-	return getPreflightChecksForDistro(distro(), networkMode, usingSystemdResolved == nil, bundlePath, preset, enableBundleQuayFallback)
+	return getPreflightChecksForDistro(distro(), usingSystemdResolved == nil, opts)
 }
 
-func getPreflightChecksForDistro(distro *linux.OsRelease, networkMode network.Mode, usingSystemdResolved bool, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getPreflightChecksForDistro(distro *linux.OsRelease, usingSystemdResolved bool, opts checkOptions) []Check {
 	filter := newFilter()
 	filter.SetDistro(distro)
 	filter.SetSystemdUser(distro)
-	filter.SetNetworkMode(networkMode)
+	filter.SetNetworkMode(opts.networkMode)
 	filter.SetSystemdResolved(usingSystemdResolved)
 
-	return filter.Apply(getChecks(distro, bundlePath, preset, enableBundleQuayFallback))
+	return filter.Apply(getChecks(distro, opts))
 }
 
-func getChecks(distro *linux.OsRelease, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getChecks(distro *linux.OsRelease, opts checkOptions) []Check {
 	var checks []Check
 	checks = append(checks, nonWinPreflightChecks...)
 	checks = append(checks, wsl2PreflightCheck)
-	checks = append(checks, genericPreflightChecks(preset)...)
-	checks = append(checks, memoryCheck(preset))
-	checks = append(checks, genericCleanupChecks...)
+	checks = append(checks, genericPreflightChecks(opts.preset)...)
+	checks = append(checks, memoryCheck(opts.preset))
+	checks = append(checks, genericCleanupChecks(opts.libvirtNetwork.InstanceIP.String())...)
 	checks = append(checks, libvirtPreflightChecks(distro)...)
 	checks = append(checks, ubuntuPreflightChecks...)
 	checks = append(checks, nmPreflightChecks...)
-	checks = append(checks, systemdResolvedPreflightChecks...)
-	checks = append(checks, dnsmasqPreflightChecks...)
-	checks = append(checks, libvirtNetworkPreflightChecks...)
+	checks = append(checks, systemdResolvedPreflightChecks(opts.libvirtNetwork)...)
+	checks = append(checks, dnsmasqPreflightChecks(opts.libvirtNetwork)...)
+	checks = append(checks, libvirtNetworkPreflightChecks(opts.libvirtNetwork)...)
 	checks = append(checks, vsockPreflightCheck)
-	checks = append(checks, bundleCheck(bundlePath, preset, enableBundleQuayFallback))
+	checks = append(checks, bundleCheck(opts.bundlePath, opts.preset, opts.bundleMirror, opts.enableBundleQuayFallback))
+	// opt-in, it needs the bundle to be extracted
+	if opts.trustClusterCA {
+		checks = append(checks, clusterCATrustCheck(opts.bundlePath, opts.name))
+	}
 
 	return checks
 }
@@ -7,9 +7,7 @@ import (
 
 	"github.com/crc-org/crc/v2/pkg/crc/cluster"
 	"github.com/crc-org/crc/v2/pkg/crc/config"
-	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
-	"github.com/crc-org/crc/v2/pkg/crc/preset"
 	crcos "github.com/crc-org/crc/v2/pkg/os/linux"
 	"github.com/stretchr/testify/assert"
 )
@@ -20,7 +18,7 @@ func TestCountConfigurationOptions(t *testing.T) {
 	options := len(cfg.AllConfigs())
 
 	var preflightChecksCount int
-	for _, check := range getAllPreflightChecks() {
+	for _, check := range getAllPreflightChecks(network.DefaultLibvirtNetwork()) {
 		if check.configKeySuffix != "" {
 			preflightChecksCount++
 		}
@@ -74,7 +72,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -93,8 +91,8 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkNetworkManagerIsRunning},
 			{check: checkCrcDnsmasqAndNetworkManagerConfigFile},
 			{check: checkSystemdResolvedIsRunning},
-			{check: checkCrcNetworkManagerDispatcherFile},
-			{check: checkLibvirtCrcNetworkAvailable},
+			{configKeySuffix: "check-network-manager-dispatcher-file"},
+			{configKeySuffix: "check-crc-network"},
 			{check: checkLibvirtCrcNetworkActive},
 			{configKeySuffix: "check-bundle-extracted"},
 		},
@@ -114,7 +112,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -132,8 +130,8 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkNetworkManagerInstalled},
 			{check: checkNetworkManagerIsRunning},
 			{check: checkCrcNetworkManagerConfig},
-			{check: checkCrcDnsmasqConfigFile},
-			{check: checkLibvirtCrcNetworkAvailable},
+			{configKeySuffix: "check-crc-dnsmasq-file"},
+			{configKeySuffix: "check-crc-network"},
 			{check: checkLibvirtCrcNetworkActive},
 			{configKeySuffix: "check-bundle-extracted"},
 		},
@@ -153,7 +151,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -186,7 +184,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -205,8 +203,8 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkNetworkManagerIsRunning},
 			{check: checkCrcDnsmasqAndNetworkManagerConfigFile},
 			{check: checkSystemdResolvedIsRunning},
-			{check: checkCrcNetworkManagerDispatcherFile},
-			{check: checkLibvirtCrcNetworkAvailable},
+			{configKeySuffix: "check-network-manager-dispatcher-file"},
+			{configKeySuffix: "check-crc-network"},
 			{check: checkLibvirtCrcNetworkActive},
 			{configKeySuffix: "check-bundle-extracted"},
 		},
@@ -226,7 +224,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -244,8 +242,8 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkNetworkManagerInstalled},
 			{check: checkNetworkManagerIsRunning},
 			{check: checkCrcNetworkManagerConfig},
-			{check: checkCrcDnsmasqConfigFile},
-			{check: checkLibvirtCrcNetworkAvailable},
+			{configKeySuffix: "check-crc-dnsmasq-file"},
+			{configKeySuffix: "check-crc-network"},
 			{check: checkLibvirtCrcNetworkActive},
 			{configKeySuffix: "check-bundle-extracted"},
 		},
@@ -265,7 +263,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -298,7 +296,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -317,8 +315,8 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkNetworkManagerIsRunning},
 			{check: checkCrcDnsmasqAndNetworkManagerConfigFile},
 			{check: checkSystemdResolvedIsRunning},
-			{check: checkCrcNetworkManagerDispatcherFile},
-			{check: checkLibvirtCrcNetworkAvailable},
+			{configKeySuffix: "check-network-manager-dispatcher-file"},
+			{configKeySuffix: "check-crc-network"},
 			{check: checkLibvirtCrcNetworkActive},
 			{configKeySuffix: "check-bundle-extracted"},
 		},
@@ -338,7 +336,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -356,8 +354,8 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkNetworkManagerInstalled},
 			{check: checkNetworkManagerIsRunning},
 			{check: checkCrcNetworkManagerConfig},
-			{check: checkCrcDnsmasqConfigFile},
-			{check: checkLibvirtCrcNetworkAvailable},
+			{configKeySuffix: "check-crc-dnsmasq-file"},
+			{configKeySuffix: "check-crc-network"},
 			{check: checkLibvirtCrcNetworkActive},
 			{configKeySuffix: "check-bundle-extracted"},
 		},
@@ -377,7 +375,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -410,7 +408,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -430,8 +428,8 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkNetworkManagerIsRunning},
 			{check: checkCrcDnsmasqAndNetworkManagerConfigFile},
 			{check: checkSystemdResolvedIsRunning},
-			{check: checkCrcNetworkManagerDispatcherFile},
-			{check: checkLibvirtCrcNetworkAvailable},
+			{configKeySuffix: "check-network-manager-dispatcher-file"},
+			{configKeySuffix: "check-crc-network"},
 			{check: checkLibvirtCrcNetworkActive},
 			{configKeySuffix: "check-bundle-extracted"},
 		},
@@ -451,7 +449,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -470,8 +468,8 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkNetworkManagerInstalled},
 			{check: checkNetworkManagerIsRunning},
 			{check: checkCrcNetworkManagerConfig},
-			{check: checkCrcDnsmasqConfigFile},
-			{check: checkLibvirtCrcNetworkAvailable},
+			{configKeySuffix: "check-crc-dnsmasq-file"},
+			{configKeySuffix: "check-crc-network"},
 			{check: checkLibvirtCrcNetworkActive},
 			{configKeySuffix: "check-bundle-extracted"},
 		},
@@ -491,7 +489,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeAllLogs},
 			{cleanup: cluster.ForgetPullSecret},
 			{cleanup: removeHostsFileEntry},
-			{cleanup: removeCRCHostEntriesFromKnownHosts},
+			{cleanup: removeCRCHostEntriesFromKnownHosts("")},
 			{cleanup: removeCrcManPages},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
@@ -521,7 +519,7 @@ func assertFuncEqual(t *testing.T, func1 interface{}, func2 interface{}) {
 }
 
 func assertExpectedPreflights(t *testing.T, distro *crcos.OsRelease, networkMode network.Mode, systemdResolved bool) {
-	preflights := getPreflightChecksForDistro(distro, networkMode, systemdResolved, constants.GetDefaultBundlePath(preset.OpenShift), preset.OpenShift, false)
+	preflights := getPreflightChecksForDistro(distro, systemdResolved, defaultCheckOptions(networkMode, network.DefaultLibvirtNetwork()))
 	var expected checkListForDistro
 	for _, expected = range checkListForDistros {
 		if expected.distro == distro && expected.networkMode == networkMode && expected.systemdResolved == systemdResolved {
@@ -565,3 +563,35 @@ func TestCountPreflights(t *testing.T) {
 	assertExpectedPreflights(t, &ubuntu, network.SystemNetworkingMode, false)
 	assertExpectedPreflights(t, &ubuntu, network.UserNetworkingMode, false)
 }
+
+func TestTrustClusterCAPreflight(t *testing.T) {
+	opts := defaultCheckOptions(network.SystemNetworkingMode, network.DefaultLibvirtNetwork())
+	withoutCA := getPreflightChecksForDistro(&fedora, true, opts)
+	opts.trustClusterCA = true
+	preflights := getPreflightChecksForDistro(&fedora, true, opts)
+	assert.Len(t, preflights, len(withoutCA)+1)
+	assert.Equal(t, "check-cluster-ca-trusted", preflights[len(preflights)-1].configKeySuffix)
+}
+
+func TestLibvirtNetworkXML(t *testing.T) {
+	netXML, err := getLibvirtNetworkXML(network.DefaultLibvirtNetwork())
+	assert.NoError(t, err)
+	assert.Equal(t, "<network><name>crc</name><uuid>49eee855-d342-46c3-9ed3-b8d1758814cd</uuid>"+
+		"<forward mode='nat'><nat><port start='1024' end='65535'/></nat></forward>"+
+		"<bridge name='crc' stp='on' delay='0'/><mac address='52:54:00:fd:be:d0'/>"+
+		"<ip family='ipv4' address='192.168.130.1' prefix='24'><dhcp>"+
+		"<host mac='52:fd:fc:07:21:82' ip='192.168.130.11'/>"+
+		"</dhcp></ip></network>", trimSpacesFromXML(netXML))
+
+	libvirtNetwork, err := network.ParseLibvirtNetwork("crc1", "10.88.0.0/16", "", "10.88.0.10-10.88.0.100")
+	assert.NoError(t, err)
+	netXML, err = getLibvirtNetworkXML(libvirtNetwork)
+	assert.NoError(t, err)
+	assert.Contains(t, trimSpacesFromXML(netXML), "<bridge name='crc1' stp='on' delay='0'/>")
+	assert.Contains(t, trimSpacesFromXML(netXML), "<ip family='ipv4' address='10.88.0.1' prefix='16'><dhcp>"+
+		"<range start='10.88.0.10' end='10.88.0.100'/>"+
+		"<host mac='52:fd:fc:07:21:82' ip='10.88.0.10'/></dhcp></ip>")
+
+	assert.Equal(t, "server=/apps-crc.testing/10.88.0.10\nserver=/crc.testing/10.88.0.10\n", crcDnsmasqConfig(libvirtNetwork))
+	assert.Contains(t, crcNetworkManagerDispatcherConfig(libvirtNetwork), "systemd-resolve --interface crc1 --set-dns 10.88.0.10 --set-domain ~testing\n")
+}
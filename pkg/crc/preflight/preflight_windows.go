@@ -6,9 +6,7 @@ import (
 	"os"
 	"strings"
 
-	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
-	crcpreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/crc-org/crc/v2/pkg/os/windows/powershell"
 	"github.com/crc-org/crc/v2/pkg/os/windows/win32"
 )
@@ -211,19 +209,20 @@ func checkVsock() error {
 //
 // Passing 'UserNetworkingMode' to getPreflightChecks currently achieves this
 // as there are no system networking specific checks
-func getAllPreflightChecks() []Check {
-	return getPreflightChecks(true, network.UserNetworkingMode, constants.GetDefaultBundlePath(crcpreset.OpenShift), crcpreset.OpenShift, false)
+func getAllPreflightChecks(libvirtNetwork *network.LibvirtNetwork) []Check {
+	return getPreflightChecks(defaultCheckOptions(network.UserNetworkingMode, libvirtNetwork))
 }
 
-func getChecks(bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getChecks(opts checkOptions) []Check {
 	checks := []Check{}
-	checks = append(checks, memoryCheck(preset))
+	checks = append(checks, memoryCheck(opts.preset))
 	checks = append(checks, hypervPreflightChecks...)
 	checks = append(checks, crcUsersGroupExistsCheck)
 	checks = append(checks, userPartOfCrcUsersAndHypervAdminsGroupCheck)
 	checks = append(checks, vsockChecks...)
-	checks = append(checks, bundleCheck(bundlePath, preset, enableBundleQuayFallback))
-	checks = append(checks, genericCleanupChecks...)
+	checks = append(checks, bundleCheck(opts.bundlePath, opts.preset, opts.bundleMirror, opts.enableBundleQuayFallback))
+	// the instance is reached through vsock or localhost
+	checks = append(checks, genericCleanupChecks("")...)
 	checks = append(checks, cleanupCheckRemoveCrcVM)
 	checks = append(checks, daemonTaskChecks...)
 	checks = append(checks, adminHelperServiceCheks...)
@@ -231,9 +230,9 @@ func getChecks(bundlePath string, preset crcpreset.Preset, enableBundleQuayFallb
 	return checks
 }
 
-func getPreflightChecks(_ bool, networkMode network.Mode, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getPreflightChecks(opts checkOptions) []Check {
 	filter := newFilter()
-	filter.SetNetworkMode(networkMode)
+	filter.SetNetworkMode(opts.networkMode)
 
-	return filter.Apply(getChecks(bundlePath, preset, enableBundleQuayFallback))
+	return filter.Apply(getChecks(opts))
 }
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/crc-org/crc/v2/pkg/crc/adminhelper"
-	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/errors"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
@@ -80,7 +79,7 @@ func dnsServers(serviceConfig services.ServicePostStartConfig) ([]network.NameSe
 	if serviceConfig.NetworkMode == network.UserNetworkingMode {
 		return []network.NameServer{
 			{
-				IPAddress: constants.VSockGateway,
+				IPAddress: serviceConfig.UserNetworkGatewayIP,
 			},
 		}, nil
 	}
@@ -205,12 +204,30 @@ func matchIP(ips []net.IP, expectedIP string) bool {
 }
 
 func addOpenShiftHosts(serviceConfig services.ServicePostStartConfig) error {
+	if previous := serviceConfig.PreviousCustomAppsDomain; previous != "" && previous != serviceConfig.CustomAppsDomain {
+		if err := RemoveCustomAppsDomainHosts(previous); err != nil {
+			return err
+		}
+	}
 	hostnames := getApplicableHostnames(serviceConfig)
 	return adminhelper.UpdateHostsFile(serviceConfig.IP, hostnames...)
 }
 
+// RemoveCustomAppsDomainHosts removes the hosts file records of the routes moved to appsDomain
+func RemoveCustomAppsDomainHosts(appsDomain string) error {
+	return adminhelper.RemoveFromHostsFile(customAppsDomainHostnames(appsDomain)...)
+}
+
+func customAppsDomainHostnames(appsDomain string) []string {
+	var hostnames []string
+	for _, appName := range []string{"oauth-openshift", "console-openshift-console", "downloads-openshift-console"} {
+		hostnames = append(hostnames, fmt.Sprintf("%s.%s", appName, appsDomain))
+	}
+	return hostnames
+}
+
 func getApplicableHostnames(serviceConfig services.ServicePostStartConfig) []string {
-	return []string{
+	hostnames := []string{
 		serviceConfig.BundleMetadata.GetAPIHostname(),
 		serviceConfig.BundleMetadata.GetFQDN("host"),
 		serviceConfig.BundleMetadata.GetAppHostname("oauth-openshift"),
@@ -219,4 +236,8 @@ func getApplicableHostnames(serviceConfig services.ServicePostStartConfig) []str
 		serviceConfig.BundleMetadata.GetAppHostname("canary-openshift-ingress-canary"),
 		serviceConfig.BundleMetadata.GetAppHostname("default-route-openshift-image-registry"),
 	}
+	if serviceConfig.CustomAppsDomain != "" {
+		hostnames = append(hostnames, customAppsDomainHostnames(serviceConfig.CustomAppsDomain)...)
+	}
+	return hostnames
 }
@@ -36,3 +36,60 @@ func TestGetApplicableHostnames(t *testing.T) {
 		"default-route-openshift-image-registry.apps.crc.testing",
 	}, hostnames)
 }
+
+func TestGetApplicableHostnamesWithCustomAppsDomain(t *testing.T) {
+	serviceConfig := services.ServicePostStartConfig{
+		BundleMetadata: bundle.CrcBundleInfo{
+			ClusterInfo: bundle.ClusterInfo{
+				ClusterName: "crc",
+				BaseDomain:  "testing",
+				AppsDomain:  "apps-crc.testing",
+			},
+		},
+		CustomAppsDomain: "apps.dev.example.com",
+	}
+	assert.Equal(t, []string{
+		"api.crc.testing",
+		"host.crc.testing",
+		"oauth-openshift.apps-crc.testing",
+		"console-openshift-console.apps-crc.testing",
+		"downloads-openshift-console.apps-crc.testing",
+		"canary-openshift-ingress-canary.apps-crc.testing",
+		"default-route-openshift-image-registry.apps-crc.testing",
+		"oauth-openshift.apps.dev.example.com",
+		"console-openshift-console.apps.dev.example.com",
+		"downloads-openshift-console.apps.dev.example.com",
+	}, getApplicableHostnames(serviceConfig))
+}
+
+func TestDnsmasqConfigWithCustomAppsDomain(t *testing.T) {
+	dnsConfig, err := createDNSConfigFile(dnsmasqConfFileValues{
+		BaseDomain:       "testing",
+		ClusterName:      "crc",
+		Hostname:         "crc-abcde-master-0",
+		IP:               "192.168.130.11",
+		AppsDomain:       "apps-crc.testing",
+		InternalIP:       "192.168.126.11",
+		CustomAppsDomain: "apps.dev.example.com",
+	}, dnsmasqConfTemplate)
+	assert.NoError(t, err)
+	assert.Equal(t, `listen-address=192.168.130.11
+expand-hosts
+log-queries
+local=/crc.testing/
+domain=crc.testing
+address=/apps-crc.testing/192.168.130.11
+address=/apps.dev.example.com/192.168.130.11
+address=/api.crc.testing/192.168.130.11
+address=/api-int.crc.testing/192.168.130.11
+address=/crc-abcde-master-0.crc.testing/192.168.126.11
+`, dnsConfig)
+}
+
+func TestCustomAppsDomainHostnames(t *testing.T) {
+	assert.Equal(t, []string{
+		"oauth-openshift.apps.dev.example.com",
+		"console-openshift-console.apps.dev.example.com",
+		"downloads-openshift-console.apps.dev.example.com",
+	}, customAppsDomainHostnames("apps.dev.example.com"))
+}
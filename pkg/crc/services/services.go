@@ -13,4 +13,10 @@ type ServicePostStartConfig struct {
 	IP              string
 	NetworkMode     network.Mode
 	ModifyHostsFile bool
+	// DNS server of the instance with the user network mode
+	UserNetworkGatewayIP string
+	// Apps domain of the console and oauth routes when it's not the one of the bundle
+	CustomAppsDomain string
+	// Custom apps domain configured by the previous start, its hosts file records are removed when it changes
+	PreviousCustomAppsDomain string
 }
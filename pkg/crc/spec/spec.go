@@ -0,0 +1,177 @@
+package spec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/spf13/cast"
+	yaml "gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	defaultOperatorSource    = "redhat-operators"
+	defaultOperatorNamespace = "openshift-operators"
+
+	hiddenValue = "********"
+)
+
+// Spec is the content of a crc.yaml file, which describes the configuration
+// of a cluster and the actions applied to it once it's started
+type Spec struct {
+	// Settings are the values of the settings registered by config.RegisterSettings, by name
+	Settings  map[string]interface{} `yaml:"settings"`
+	PostStart PostStart              `yaml:"postStart"`
+}
+
+type PostStart struct {
+	Namespaces []string   `yaml:"namespaces"`
+	Manifests  []string   `yaml:"manifests"`
+	Operators  []Operator `yaml:"operators"`
+}
+
+type Operator struct {
+	Name      string `yaml:"name"`
+	Channel   string `yaml:"channel"`
+	Source    string `yaml:"source"`
+	Namespace string `yaml:"namespace"`
+}
+
+// SettingChange is a setting whose value in the configuration differs from the spec
+type SettingChange struct {
+	Name    string
+	Current interface{}
+	Wanted  interface{}
+	// Message tells when the change is applied, such as after a restart
+	Message string
+}
+
+// Load reads the spec file at path. The relative paths of its manifests are
+// relative to the directory of the file.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &Spec{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(spec); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid spec file %s: %w", path, err)
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	for i, manifest := range spec.PostStart.Manifests {
+		if !isURL(manifest) && !filepath.IsAbs(manifest) {
+			spec.PostStart.Manifests[i] = filepath.Join(dir, manifest)
+		}
+	}
+	for i := range spec.PostStart.Operators {
+		operator := &spec.PostStart.Operators[i]
+		if operator.Source == "" {
+			operator.Source = defaultOperatorSource
+		}
+		if operator.Namespace == "" {
+			operator.Namespace = defaultOperatorNamespace
+		}
+	}
+	return spec, nil
+}
+
+// Validate checks the settings with their validation function and the post-start actions
+func (spec *Spec) Validate(cfg *crcConfig.Config) error {
+	for _, name := range spec.settingNames() {
+		if err := cfg.Validate(name, spec.Settings[name]); err != nil {
+			return err
+		}
+	}
+	for _, namespace := range spec.PostStart.Namespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid namespace '%s': %s", namespace, strings.Join(errs, ", "))
+		}
+	}
+	for _, manifest := range spec.PostStart.Manifests {
+		if isURL(manifest) {
+			continue
+		}
+		if _, err := os.Stat(manifest); err != nil {
+			return fmt.Errorf("invalid manifest: %w", err)
+		}
+	}
+	for _, operator := range spec.PostStart.Operators {
+		if operator.Name == "" {
+			return errors.New("operators must have a name")
+		}
+		if errs := validation.IsDNS1123Label(operator.Namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid namespace '%s' for operator %s: %s", operator.Namespace, operator.Name, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// Apply validates the spec and sets the settings whose value differs in cfg,
+// the changes are returned in the order of the setting names
+func (spec *Spec) Apply(cfg *crcConfig.Config) ([]SettingChange, error) {
+	if err := spec.Validate(cfg); err != nil {
+		return nil, err
+	}
+	var changes []SettingChange
+	for _, name := range spec.settingNames() {
+		setting := cfg.Get(name)
+		wanted := spec.Settings[name]
+		if cast.ToString(setting.Value) == cast.ToString(wanted) {
+			continue
+		}
+		message, err := cfg.Set(name, wanted)
+		if err != nil {
+			return changes, err
+		}
+		change := SettingChange{
+			Name:    name,
+			Current: setting.Value,
+			Wanted:  wanted,
+			Message: message,
+		}
+		if setting.IsSecret {
+			change.Current, change.Wanted = hiddenValue, hiddenValue
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// PostStartActions returns the actions to apply once the cluster is started
+func (spec *Spec) PostStartActions() types.PostStartActions {
+	actions := types.PostStartActions{
+		Namespaces: spec.PostStart.Namespaces,
+		Manifests:  spec.PostStart.Manifests,
+	}
+	for _, operator := range spec.PostStart.Operators {
+		actions.Operators = append(actions.Operators, types.Operator(operator))
+	}
+	return actions
+}
+
+func (spec *Spec) settingNames() []string {
+	var names []string
+	for name := range spec.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isURL(manifest string) bool {
+	return strings.HasPrefix(manifest, "http://") || strings.HasPrefix(manifest, "https://")
+}
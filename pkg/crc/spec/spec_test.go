@@ -0,0 +1,85 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newInMemoryConfig() *crcConfig.Config {
+	cfg := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	crcConfig.RegisterSettings(cfg)
+	return cfg
+}
+
+func TestLoad(t *testing.T) {
+	spec, err := Load(filepath.Join("testdata", "crc.yaml"))
+	require.NoError(t, err)
+
+	testdata, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+	assert.Equal(t, types.PostStartActions{
+		Namespaces: []string{"my-app"},
+		Manifests:  []string{filepath.Join(testdata, "manifests", "database.yaml"), "https://example.com/deployment.yaml"},
+		Operators: []types.Operator{
+			{Name: "postgresql", Channel: "stable", Source: "redhat-operators", Namespace: "openshift-operators"},
+		},
+	}, spec.PostStartActions())
+	assert.NoError(t, spec.Validate(newInMemoryConfig()))
+}
+
+func TestLoadUnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crc.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("setting:\n  cpus: 4\n"), 0600))
+	_, err := Load(path)
+	assert.ErrorContains(t, err, "field setting not found")
+}
+
+func TestApply(t *testing.T) {
+	spec, err := Load(filepath.Join("testdata", "crc.yaml"))
+	require.NoError(t, err)
+	cfg := newInMemoryConfig()
+	_, err = cfg.Set(crcConfig.ConsentTelemetry, "no")
+	require.NoError(t, err)
+
+	changes, err := spec.Apply(cfg)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, crcConfig.DisableUpdateCheck, changes[0].Name)
+	assert.Equal(t, false, changes[0].Current)
+	assert.Equal(t, true, changes[0].Wanted)
+	assert.Equal(t, crcConfig.IngressHTTPPort, changes[1].Name)
+	assert.Contains(t, changes[1].Message, "will break OpenShift HTTP routes")
+	assert.Equal(t, uint(8080), cfg.Get(crcConfig.IngressHTTPPort).AsUInt())
+
+	// the configuration matches the spec
+	changes, err = spec.Apply(cfg)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestApplyInvalidSettings(t *testing.T) {
+	for _, tc := range []struct {
+		settings map[string]interface{}
+		err      string
+	}{
+		{map[string]interface{}{"cpu": 4}, "Configuration property 'cpu' does not exist"},
+		{map[string]interface{}{crcConfig.IngressHTTPPort: 80}, "Value '80' for configuration property 'ingress-http-port' is invalid, reason: Provided 80 but requires value in range of 1024-65535"},
+	} {
+		spec := &Spec{Settings: tc.settings}
+		_, err := spec.Apply(newInMemoryConfig())
+		assert.EqualError(t, err, tc.err)
+	}
+}
+
+func TestValidatePostStart(t *testing.T) {
+	cfg := newInMemoryConfig()
+	assert.ErrorContains(t, (&Spec{PostStart: PostStart{Namespaces: []string{"My_App"}}}).Validate(cfg), "invalid namespace 'My_App'")
+	assert.ErrorContains(t, (&Spec{PostStart: PostStart{Manifests: []string{"/nonexistent/manifest.yaml"}}}).Validate(cfg), "invalid manifest")
+	assert.EqualError(t, (&Spec{PostStart: PostStart{Operators: []Operator{{Namespace: "openshift-operators"}}}}).Validate(cfg), "operators must have a name")
+}
@@ -4,41 +4,55 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	log "github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/pkg/sftp"
 	"golang.org/x/crypto/ssh"
 )
 
 type Client interface {
 	Run(command string) ([]byte, []byte, error)
+	RunInteractive(command string, tty bool, stdin io.Reader, stdout, stderr io.Writer) error
+	NewSFTPClient(privileged bool) (*sftp.Client, error)
 	Close()
 }
 
 type NativeClient struct {
-	User     string
-	Hostname string
-	Port     int
-	Keys     []string
+	User           string
+	Hostname       string
+	Port           int
+	KnownHostsFile string
+	// BundleKnownHostsFile records the host key of the bundle of the VM, see pinnedHostKey
+	BundleKnownHostsFile string
+	Keys                 []string
 
+	lock sync.Mutex
 	conn *ssh.Client
 }
 
-func NewClient(user string, host string, port int, keys ...string) (Client, error) {
+// NewClient returns a client connecting to host as user with one of keys. The
+// host key is pinned in knownHostsFile, or verified against the one of the
+// bundle in bundleKnownHostsFile until it is pinned, see pinnedHostKey.
+func NewClient(user string, host string, port int, knownHostsFile string, bundleKnownHostsFile string, keys ...string) (Client, error) {
 	return &NativeClient{
-		User:     user,
-		Hostname: host,
-		Port:     port,
-		Keys:     keys,
+		User:                 user,
+		Hostname:             host,
+		Port:                 port,
+		KnownHostsFile:       knownHostsFile,
+		BundleKnownHostsFile: bundleKnownHostsFile,
+		Keys:                 keys,
 	}, nil
 }
 
 // nolint:gosec // G703 - paths are supplied by calling functions,
 // this function is not supposed to check them for path traversal
-func clientConfig(user string, keys []string) (*ssh.ClientConfig, error) {
+func clientConfig(user string, knownHostsFile string, bundleKnownHostsFile string, keys []string) (*ssh.ClientConfig, error) {
 	var (
 		privateKeys []ssh.Signer
 		keyPaths    []string
@@ -65,44 +79,159 @@ func clientConfig(user string, keys []string) (*ssh.ClientConfig, error) {
 	}
 	log.Debugf("Using ssh private keys: %v", keyPaths)
 
+	hostKeyCallback, hostKeyAlgorithms, err := pinnedHostKey(knownHostsFile, bundleKnownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ssh.ClientConfig{
-		User: user,
-		Auth: []ssh.AuthMethod{ssh.PublicKeys(privateKeys...)},
-		// #nosec G106
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         10 * time.Second,
+		User:              user,
+		Auth:              []ssh.AuthMethod{ssh.PublicKeys(privateKeys...)},
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: hostKeyAlgorithms,
+		Timeout:           10 * time.Second,
 	}, nil
 }
 
-func (client *NativeClient) session() (*ssh.Session, error) {
+func (client *NativeClient) connect() (*ssh.Client, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
 	if client.conn == nil {
 		var err error
-		config, err := clientConfig(client.User, client.Keys)
+		client.conn, err = client.dial()
 		if err != nil {
-			return nil, fmt.Errorf("error getting config for native Go SSH: %w", err)
+			return nil, err
 		}
-		client.conn, err = ssh.Dial("tcp", net.JoinHostPort(client.Hostname, strconv.Itoa(client.Port)), config)
+	}
+	return client.conn, nil
+}
+
+func (client *NativeClient) dial() (*ssh.Client, error) {
+	config, err := clientConfig(client.User, client.KnownHostsFile, client.BundleKnownHostsFile, client.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("error getting config for native Go SSH: %w", err)
+	}
+	return ssh.Dial("tcp", net.JoinHostPort(client.Hostname, strconv.Itoa(client.Port)), config)
+}
+
+// session opens a session on the connection of the client, the returned
+// function must be called once the session is closed, see retryOnNewConnection
+func (client *NativeClient) session() (*ssh.Session, func(), error) {
+	var session *ssh.Session
+	release, err := client.retryOnNewConnection(func(conn *ssh.Client) error {
+		var err error
+		session, err = conn.NewSession()
+		return err
+	})
+	if err != nil {
+		log.Debugf("Failed to create new ssh session: %s", err)
+		return nil, nil, err
+	}
+	return session, release, nil
+}
+
+// retryOnNewConnection runs open with the connection of the client. The
+// connection is shared by the runners of a pool, so it is only replaced when a
+// keepalive request shows it is broken, open then runs again on a new one.
+// When the VM rejects the channel, for instance once the sessions of the
+// connection reach MaxSessions of sshd, open runs again on a connection of its
+// own, closed by the returned function.
+func (client *NativeClient) retryOnNewConnection(open func(*ssh.Client) error) (func(), error) {
+	conn, err := client.connect()
+	if err != nil {
+		return nil, err
+	}
+	err = open(conn)
+	if err == nil {
+		return func() {}, nil
+	}
+
+	var rejected *ssh.OpenChannelError
+	if errors.As(err, &rejected) {
+		log.Debugf("SSH connection to %s rejected the channel: %v, retrying with a dedicated connection", client.Hostname, err)
+		conn, err := client.dial()
 		if err != nil {
 			return nil, err
 		}
+		if err := open(conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return func() {
+			conn.Close()
+		}, nil
+	}
+
+	if connectionAlive(conn, keepaliveTimeout) {
+		return nil, err
 	}
-	session, err := client.conn.NewSession()
+	log.Debugf("SSH connection to %s is broken: %v, retrying with a new connection", client.Hostname, err)
+	client.disconnect(conn)
+	conn, err = client.connect()
 	if err != nil {
 		return nil, err
 	}
-	return session, err
+	if err := open(conn); err != nil {
+		return nil, err
+	}
+	return func() {}, nil
+}
+
+// disconnect closes conn when it is still the connection of the client, the
+// next session dials a new connection
+func (client *NativeClient) disconnect(conn *ssh.Client) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+	if client.conn != conn {
+		return
+	}
+	if err := client.conn.Close(); err != nil {
+		log.Debugf("Error closing ssh client: %s", err)
+	}
+	client.conn = nil
+}
+
+// checkConnection closes the connection of the client when the VM does not
+// answer a keepalive request within timeout
+func (client *NativeClient) checkConnection(timeout time.Duration) {
+	client.lock.Lock()
+	conn := client.conn
+	client.lock.Unlock()
+	if conn == nil {
+		return
+	}
+	if !connectionAlive(conn, timeout) {
+		client.disconnect(conn)
+	}
+}
+
+// connectionAlive sends a keepalive request on conn and returns whether the
+// VM answers it within timeout
+func connectionAlive(conn *ssh.Client, timeout time.Duration) bool {
+	errCh := make(chan error, 1)
+	go func() {
+		// the reply is negative as the server does not know this request
+		_, _, err := conn.SendRequest("keepalive@openssh.com", true, nil)
+		errCh <- err
+	}()
+	select {
+	case err := <-errCh:
+		if err == nil {
+			return true
+		}
+		log.Debugf("SSH connection to %s is broken: %v", conn.RemoteAddr(), err)
+	case <-time.After(timeout):
+		log.Debugf("SSH connection to %s is not responding", conn.RemoteAddr())
+	}
+	return false
 }
 
 func (client *NativeClient) Run(command string) ([]byte, []byte, error) {
-	session, err := client.session()
+	session, release, err := client.session()
 	if err != nil {
-		if client.conn != nil {
-			log.Debugf("Failed to create new ssh session: %s", err)
-			client.conn.Close()
-			client.conn = nil
-		}
 		return nil, nil, err
 	}
+	defer release()
 	defer session.Close()
 
 	var (
@@ -118,11 +247,17 @@ func (client *NativeClient) Run(command string) ([]byte, []byte, error) {
 }
 
 func (client *NativeClient) Close() {
-	if client.conn == nil {
+	client.closeConnection()
+}
+
+// closeConnection closes the connection of the client, the next session dials
+// a new one. Unlike Close, it also applies to the clients of a pool.
+func (client *NativeClient) closeConnection() {
+	client.lock.Lock()
+	conn := client.conn
+	client.lock.Unlock()
+	if conn == nil {
 		return
 	}
-	err := client.conn.Close()
-	if err != nil {
-		log.Debugf("Error closing ssh client: %s", err)
-	}
+	client.disconnect(conn)
 }
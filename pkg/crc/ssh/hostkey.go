@@ -0,0 +1,137 @@
+package ssh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	log "github.com/crc-org/crc/v2/pkg/crc/logging"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// pinnedHostKey returns the callback verifying the host key of the VM against
+// the one recorded in knownHostsFile, and the host key algorithms matching it.
+// When the file is missing the VM still has the host keys of its bundle, they
+// are verified against bundleKnownHostsFile until RegenerateHostKeys pins the
+// key of the VM. The file is removed when the VM is recreated.
+// nolint:gosec // G304 - knownHostsFile is in the machine directory
+func pinnedHostKey(knownHostsFile string, bundleKnownHostsFile string) (ssh.HostKeyCallback, []string, error) {
+	if knownHostsFile == "" {
+		return nil, nil, errors.New("no known hosts file to verify the host key of the VM")
+	}
+	data, err := os.ReadFile(knownHostsFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return bundleHostKey(knownHostsFile, bundleKnownHostsFile)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	pinned, err := parseHostKey(knownHostsFile, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return verifyHostKey(pinned, fmt.Sprintf("recorded in %s, remove this file if the VM was recreated outside of crc", knownHostsFile)), hostKeyAlgorithms(pinned.Type()), nil
+}
+
+// bundleHostKey returns the callback verifying the host key of a VM which
+// still has the host keys of its bundle. They are the same for all the VMs
+// created from the bundle, the key presented by the first one is recorded in
+// bundleKnownHostsFile and the next ones must present the same key.
+// nolint:gosec // G304 - bundleKnownHostsFile is in the bundle directory
+func bundleHostKey(knownHostsFile string, bundleKnownHostsFile string) (ssh.HostKeyCallback, []string, error) {
+	if bundleKnownHostsFile == "" {
+		return nil, nil, fmt.Errorf("the host key of the VM is not recorded in %s yet, start the instance first", knownHostsFile)
+	}
+	data, err := os.ReadFile(bundleKnownHostsFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			return pinHostKey(bundleKnownHostsFile, key)
+		}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	bundleKey, err := parseHostKey(bundleKnownHostsFile, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return verifyHostKey(bundleKey, fmt.Sprintf("of the bundle recorded in %s", bundleKnownHostsFile)), hostKeyAlgorithms(bundleKey.Type()), nil
+}
+
+func parseHostKey(knownHostsFile string, data []byte) (ssh.PublicKey, error) {
+	_, _, key, _, _, err := ssh.ParseKnownHosts(data)
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s has no host key, remove it to record the host key of the VM again", knownHostsFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Cannot parse %s: %w", knownHostsFile, err)
+	}
+	return key, nil
+}
+
+func verifyHostKey(expected ssh.PublicKey, origin string) ssh.HostKeyCallback {
+	return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+		if bytes.Equal(key.Marshal(), expected.Marshal()) {
+			return nil
+		}
+		return fmt.Errorf("the host key of the VM %s does not match the key %s %s", ssh.FingerprintSHA256(key), ssh.FingerprintSHA256(expected), origin)
+	}
+}
+
+// RegenerateHostKeys replaces the host keys of the VM, which come from the
+// bundle and are shared by all the VMs created from it, and pins the new
+// ed25519 key in knownHostsFile. The connection of the runner, which verified
+// the host key of the bundle, is closed so that the next commands run on a
+// connection verifying the pinned key.
+func (runner *Runner) RegenerateHostKeys(knownHostsFile string) error {
+	if _, _, err := runner.RunPrivileged("regenerate the SSH host keys", "sh", "-c",
+		"'rm -f /etc/ssh/ssh_host_*key* && ssh-keygen -A && restorecon /etc/ssh/ssh_host_*key*'"); err != nil {
+		return err
+	}
+	stdout, _, err := runner.Run("cat", "/etc/ssh/ssh_host_ed25519_key.pub")
+	if err != nil {
+		return err
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(stdout))
+	if err != nil {
+		return fmt.Errorf("Cannot parse the host key of the VM: %w", err)
+	}
+	if _, _, err := runner.RunPrivileged("use the new SSH host keys", "systemctl", "restart", "sshd"); err != nil {
+		return err
+	}
+	if err := pinHostKey(knownHostsFile, key); err != nil {
+		return err
+	}
+	if client, ok := runner.client.(interface{ closeConnection() }); ok {
+		client.closeConnection()
+	}
+	return nil
+}
+
+// pinHostKey records key in knownHostsFile, which must not exist. The key is
+// recorded for any host as the IP of the VM may change between restarts.
+func pinHostKey(knownHostsFile string, key ssh.PublicKey) error {
+	log.Debugf("Recording the host key %s of the VM in %s", ssh.FingerprintSHA256(key), knownHostsFile)
+	file, err := os.OpenFile(knownHostsFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("Cannot record the host key of the VM: %w", err)
+	}
+	if _, err := file.WriteString(knownhosts.Line([]string{"*"}, key) + "\n"); err != nil {
+		file.Close()
+		return fmt.Errorf("Cannot record the host key of the VM: %w", err)
+	}
+	return file.Close()
+}
+
+// hostKeyAlgorithms returns the algorithms to negotiate so that the server
+// presents a host key of type keyType
+func hostKeyAlgorithms(keyType string) []string {
+	if keyType == ssh.KeyAlgoRSA {
+		return []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	}
+	return []string{keyType}
+}
@@ -0,0 +1,145 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// serverHostKey returns the host key of the SSH server listening on addr
+func serverHostKey(t *testing.T, addr string) ssh.PublicKey {
+	var hostKey ssh.PublicKey
+	_, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User: "core",
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+			return errors.New("probe")
+		},
+	})
+	require.ErrorContains(t, err, "probe")
+	return hostKey
+}
+
+func TestRunnerPinnedHostKey(t *testing.T) {
+	dir := t.TempDir()
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	clientKeyFile := filepath.Join(dir, "private.key")
+	writePrivateKey(t, clientKeyFile, clientKey)
+	knownHostsFile := filepath.Join(dir, "known_hosts")
+	bundleKnownHostsFile := filepath.Join(dir, "bundle_known_hosts")
+
+	// the host key of the first VM of the bundle is recorded for the bundle
+	cancel, runner, _ := createListenerAndSSHServer(t, clientKey, clientKeyFile, knownHostsFile, bundleKnownHostsFile)
+	defer cancel()
+	_, _, err = runner.Run("echo hello")
+	require.NoError(t, err)
+	assert.NoFileExists(t, knownHostsFile)
+	bundleKnownHosts, err := os.ReadFile(bundleKnownHostsFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(bundleKnownHosts), "* ecdsa-sha2-nistp521 "))
+	// the test server handles a single connection at a time
+	runner.Close()
+
+	// a VM with another host key is rejected until its key is pinned
+	cancel, otherRunner, _ := createListenerAndSSHServer(t, clientKey, clientKeyFile, knownHostsFile, bundleKnownHostsFile)
+	defer cancel()
+	defer otherRunner.Close()
+	_, _, err = otherRunner.Run("echo hello")
+	assert.ErrorContains(t, err, "of the bundle recorded in")
+
+	client := runner.client.(*NativeClient)
+	hostKey := serverHostKey(t, fmt.Sprintf("%s:%d", client.Hostname, client.Port))
+	require.NoError(t, pinHostKey(knownHostsFile, hostKey))
+	knownHosts, err := os.ReadFile(knownHostsFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(knownHosts), "* ecdsa-sha2-nistp521 "))
+
+	// the pinned key is accepted without the one of the bundle
+	pinnedRunner, err := CreateRunner(client.Hostname, client.Port, knownHostsFile, "", clientKeyFile)
+	require.NoError(t, err)
+	_, _, err = pinnedRunner.Run("echo hello")
+	require.NoError(t, err)
+	pinnedRunner.Close()
+
+	_, _, err = otherRunner.Run("echo hello")
+	assert.ErrorContains(t, err, "does not match the key")
+
+	// an existing file is never replaced
+	assert.Error(t, pinHostKey(knownHostsFile, hostKey))
+	recorded, err := os.ReadFile(knownHostsFile)
+	require.NoError(t, err)
+	assert.Equal(t, knownHosts, recorded)
+
+	// the key of the bundle is verified again once the file is removed, as done when the VM is recreated
+	require.NoError(t, os.Remove(knownHostsFile))
+	_, _, err = otherRunner.Run("echo hello")
+	assert.ErrorContains(t, err, "of the bundle recorded in")
+	_, _, err = runner.Run("echo hello")
+	assert.NoError(t, err)
+	runner.Close()
+
+	// and nothing is accepted without it
+	unpinnedRunner, err := CreateRunner(client.Hostname, client.Port, knownHostsFile, "", clientKeyFile)
+	require.NoError(t, err)
+	_, _, err = unpinnedRunner.Run("echo hello")
+	assert.ErrorContains(t, err, "is not recorded in")
+}
+
+func TestRunnerRegenerateHostKeys(t *testing.T) {
+	dir := t.TempDir()
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	clientKeyFile := filepath.Join(dir, "private.key")
+	writePrivateKey(t, clientKeyFile, clientKey)
+	knownHostsFile := filepath.Join(dir, "known_hosts")
+	bundleKnownHostsFile := filepath.Join(dir, "bundle_known_hosts")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var hostKey atomic.Value
+	createSSHServer(ctx, t, listener, clientKey, func(input string) (byte, string) {
+		switch input {
+		case "echo hello", "sudo systemctl restart sshd",
+			"sudo sh -c 'rm -f /etc/ssh/ssh_host_*key* && ssh-keygen -A && restorecon /etc/ssh/ssh_host_*key*'":
+			return 0, ""
+		case "cat /etc/ssh/ssh_host_ed25519_key.pub":
+			return 0, string(ssh.MarshalAuthorizedKey(hostKey.Load().(ssh.PublicKey)))
+		}
+		return 1, fmt.Sprintf("unexpected command: %q", input)
+	})
+	addr := listener.Addr().String()
+	hostKey.Store(serverHostKey(t, addr))
+
+	runner, err := CreateRunner(ipFor(addr), portFor(addr), knownHostsFile, bundleKnownHostsFile, clientKeyFile)
+	require.NoError(t, err)
+	defer runner.Close()
+	require.NoError(t, runner.RegenerateHostKeys(knownHostsFile))
+	knownHosts, err := os.ReadFile(knownHostsFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(knownHosts), "* ecdsa-sha2-nistp521 "))
+	assert.Error(t, runner.RegenerateHostKeys(knownHostsFile))
+
+	// the runner connects again, verifying the pinned key
+	require.NoError(t, os.Remove(bundleKnownHostsFile))
+	_, _, err = runner.Run("echo hello")
+	assert.NoError(t, err)
+	assert.NoFileExists(t, bundleKnownHostsFile)
+}
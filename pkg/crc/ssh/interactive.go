@@ -0,0 +1,123 @@
+package ssh
+
+import (
+	"io"
+	"math"
+	"os"
+
+	log "github.com/crc-org/crc/v2/pkg/crc/logging"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+const (
+	defaultTerminalType   = "xterm-256color"
+	defaultTerminalWidth  = 80
+	defaultTerminalHeight = 24
+)
+
+// ExitError is returned by RunInteractive when the command exits with a non-zero status
+type ExitError = ssh.ExitError
+
+// RunInteractive runs command in the VM with the given standard streams, or a
+// login shell when command is empty. When tty is true and stdin is a terminal,
+// a pseudo-terminal of the same size is allocated in the VM and stdin is put
+// in raw mode until the command exits.
+func (runner *Runner) RunInteractive(command string, tty bool, stdin io.Reader, stdout, stderr io.Writer) error {
+	if command == "" {
+		log.Debugf("Running an interactive SSH session")
+	} else {
+		log.Debugf("Running interactive SSH command: %s", command)
+	}
+	return runner.client.RunInteractive(command, tty, stdin, stdout, stderr)
+}
+
+func (client *NativeClient) RunInteractive(command string, tty bool, stdin io.Reader, stdout, stderr io.Writer) error {
+	session, release, err := client.session()
+	if err != nil {
+		return err
+	}
+	defer release()
+	defer session.Close()
+
+	session.Stdout = stdout
+	session.Stderr = stderr
+	// stdin is copied outside of the session, Wait() would otherwise block
+	// until stdin is closed, after the command exits
+	stdinPipe, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	if fd, ok := terminalFd(stdin); ok && tty {
+		restore, err := requestPty(session, fd, stdout)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	if command == "" {
+		err = session.Shell()
+	} else {
+		err = session.Start(command)
+	}
+	if err != nil {
+		return err
+	}
+	go func() {
+		_, _ = io.Copy(stdinPipe, stdin)
+		_ = stdinPipe.Close()
+	}()
+	return session.Wait()
+}
+
+// requestPty allocates a pseudo-terminal for session with the size of the
+// terminal of the caller and puts the terminal fd in raw mode. It returns the
+// function restoring the terminal.
+func requestPty(session *ssh.Session, fd int, stdout io.Writer) (func(), error) {
+	sizeFd := fd
+	if outFd, ok := terminalFd(stdout); ok {
+		// on Windows, the size is only available from the output console
+		sizeFd = outFd
+	}
+	width, height, err := term.GetSize(sizeFd)
+	if err != nil {
+		log.Debugf("Cannot get the terminal size: %v", err)
+		width, height = defaultTerminalWidth, defaultTerminalHeight
+	}
+	terminalType := os.Getenv("TERM")
+	if terminalType == "" {
+		terminalType = defaultTerminalType
+	}
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 14400,
+		ssh.TTY_OP_OSPEED: 14400,
+	}
+	if err := session.RequestPty(terminalType, height, width, modes); err != nil {
+		return nil, err
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	stopResize := forwardTerminalResize(session, sizeFd)
+	return func() {
+		stopResize()
+		if err := term.Restore(fd, state); err != nil {
+			log.Debugf("Cannot restore the terminal: %v", err)
+		}
+	}, nil
+}
+
+// terminalFd returns the file descriptor of stream when it is a terminal
+func terminalFd(stream interface{}) (int, bool) {
+	file, ok := stream.(*os.File)
+	if !ok || file.Fd() > math.MaxInt {
+		return 0, false
+	}
+	fd := int(file.Fd()) // #nosec G115
+	return fd, term.IsTerminal(fd)
+}
@@ -9,12 +9,16 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 var (
@@ -75,7 +79,14 @@ func GenerateSSHKey(path string) error {
 	return nil
 }
 
-func RemoveCRCHostEntriesFromKnownHosts() error {
+// KnownHostsEntry returns the host of ip:port as written in known_hosts files
+func KnownHostsEntry(ip string, port int) string {
+	return knownhosts.Normalize(net.JoinHostPort(ip, strconv.Itoa(port)))
+}
+
+// RemoveCRCHostEntriesFromKnownHosts removes the entries of hosts from the
+// known_hosts file of the user, see KnownHostsEntry
+func RemoveCRCHostEntriesFromKnownHosts(hosts ...string) error {
 	knownHostsPath := filepath.Join(constants.GetHomeDir(), ".ssh", "known_hosts")
 	if _, err := os.Stat(knownHostsPath); err != nil {
 		return nil
@@ -125,7 +136,7 @@ func RemoveCRCHostEntriesFromKnownHosts() error {
 	scanner.Split(splitFunc)
 	writer := bufio.NewWriter(tempHostsFile)
 	for scanner.Scan() {
-		if strings.Contains(scanner.Text(), "[127.0.0.1]:2222") || strings.Contains(scanner.Text(), "192.168.130.11") {
+		if isKnownHostsEntryOf(scanner.Text(), hosts) {
 			foundCRCEntries = true
 			continue
 		}
@@ -155,3 +166,23 @@ func RemoveCRCHostEntriesFromKnownHosts() error {
 	}
 	return nil
 }
+
+// isKnownHostsEntryOf returns true when the known_hosts line is an entry of
+// one of hosts
+func isKnownHostsEntryOf(line string, hosts []string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	patterns := fields[0]
+	if strings.HasPrefix(patterns, "@") && len(fields) > 1 {
+		// @cert-authority or @revoked marker
+		patterns = fields[1]
+	}
+	for _, pattern := range strings.Split(patterns, ",") {
+		if slices.Contains(hosts, pattern) {
+			return true
+		}
+	}
+	return false
+}
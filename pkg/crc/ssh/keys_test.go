@@ -15,3 +15,20 @@ func TestNewKeyPair(t *testing.T) {
 		t.Fatal("No PEM returned")
 	}
 }
+
+func TestIsKnownHostsEntryOf(t *testing.T) {
+	hosts := []string{KnownHostsEntry("127.0.0.1", 2222), KnownHostsEntry("192.168.130.11", 22)}
+	for line, expected := range map[string]bool{
+		"[127.0.0.1]:2222 ssh-ed25519 AAAA":               true,
+		"github.com,192.168.130.11 ssh-ed25519 AAAA":      true,
+		"@revoked 192.168.130.11 ssh-ed25519 AAAA":        true,
+		"192.168.130.110 ssh-ed25519 AAAA":                false,
+		"[127.0.0.1]:22220 ssh-ed25519 AAAA":              false,
+		"# 192.168.130.11 is the address of the instance": false,
+		"": false,
+	} {
+		if actual := isKnownHostsEntryOf(line, hosts); actual != expected {
+			t.Errorf("isKnownHostsEntryOf(%q) = %v, expected %v", line, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package ssh
+
+import (
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+)
+
+const (
+	keepaliveInterval = 30 * time.Second
+	keepaliveTimeout  = 10 * time.Second
+)
+
+// Pool keeps a long-lived SSH connection to each VM, shared by the sessions
+// of the runners it creates. The connections are health-checked with
+// keepalive requests and dialed again once broken, for instance after the VM
+// restarted.
+type Pool struct {
+	lock    sync.Mutex
+	clients map[string]*pooledClient
+}
+
+func NewPool() *Pool {
+	return &Pool{
+		clients: make(map[string]*pooledClient),
+	}
+}
+
+// pooledClient is the client shared by the runners of a pool. Its connection
+// is closed once it's removed from the pool and released by all its runners.
+type pooledClient struct {
+	*NativeClient
+	done chan struct{}
+
+	// protected by the lock of the pool
+	refs    int
+	removed bool
+}
+
+func (client *pooledClient) close() {
+	close(client.done)
+	client.NativeClient.Close()
+}
+
+func (client *pooledClient) healthCheck() {
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-client.done:
+			return
+		case <-ticker.C:
+			client.checkConnection(keepaliveTimeout)
+		}
+	}
+}
+
+// runnerClient is the reference of a runner to a pooled client, closing the
+// runner releases it
+type runnerClient struct {
+	*pooledClient
+	pool *Pool
+	once sync.Once
+}
+
+func (client *runnerClient) Close() {
+	client.once.Do(func() {
+		client.pool.release(client.pooledClient)
+	})
+}
+
+// CreateRunner returns a runner using the connection of the pool to the VM,
+// see the CreateRunner function for the arguments
+func (pool *Pool) CreateRunner(ip string, port int, knownHostsFile string, bundleKnownHostsFile string, privateKeys ...string) (*Runner, error) {
+	address := net.JoinHostPort(ip, strconv.Itoa(port))
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	client, ok := pool.clients[address]
+	if ok && (client.KnownHostsFile != knownHostsFile || client.BundleKnownHostsFile != bundleKnownHostsFile || !slices.Equal(client.Keys, privateKeys)) {
+		pool.remove(address, client)
+		ok = false
+	}
+	if !ok {
+		client = &pooledClient{
+			NativeClient: &NativeClient{
+				User:                 constants.DefaultSSHUser,
+				Hostname:             ip,
+				Port:                 port,
+				KnownHostsFile:       knownHostsFile,
+				BundleKnownHostsFile: bundleKnownHostsFile,
+				Keys:                 privateKeys,
+			},
+			done: make(chan struct{}),
+		}
+		go client.healthCheck()
+		pool.clients[address] = client
+	}
+	client.refs++
+	return &Runner{
+		client: &runnerClient{
+			pooledClient: client,
+			pool:         pool,
+		},
+	}, nil
+}
+
+// remove takes client out of the pool, it is closed once its runners are
+func (pool *Pool) remove(address string, client *pooledClient) {
+	delete(pool.clients, address)
+	client.removed = true
+	if client.refs == 0 {
+		client.close()
+	}
+}
+
+func (pool *Pool) release(client *pooledClient) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	client.refs--
+	if client.refs == 0 && client.removed {
+		client.close()
+	}
+}
+
+// Close closes the connections of the pool, the runners created afterwards
+// dial new ones. The connections used by runners are closed with them.
+func (pool *Pool) Close() {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	for address, client := range pool.clients {
+		pool.remove(address, client)
+	}
+}
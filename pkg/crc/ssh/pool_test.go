@@ -0,0 +1,261 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestPoolSharesConnection(t *testing.T) {
+	dir := t.TempDir()
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	clientKeyFile := filepath.Join(dir, "private.key")
+	writePrivateKey(t, clientKeyFile, clientKey)
+	knownHostsFile := filepath.Join(dir, "known_hosts")
+	bundleKnownHostsFile := filepath.Join(dir, "bundle_known_hosts")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(t, err)
+	defer listener.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	totalConn := createSSHServer(ctx, t, listener, clientKey, func(_ string) (byte, string) {
+		return 0, "hello"
+	})
+	addr := listener.Addr().String()
+
+	pool := NewPool()
+	defer pool.Close()
+	for i := 0; i < 3; i++ {
+		runner, err := pool.CreateRunner(ipFor(addr), portFor(addr), knownHostsFile, bundleKnownHostsFile, clientKeyFile)
+		require.NoError(t, err)
+		out, _, err := runner.Run("echo hello")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", out)
+		// the connection stays open for the next runners
+		runner.Close()
+	}
+	assert.Equal(t, 1, *totalConn)
+
+	// a healthy connection is kept
+	client := pool.clients[addr]
+	client.checkConnection(keepaliveTimeout)
+	assert.NotNil(t, client.conn)
+
+	// the connections are dialed again once the pool is closed, as done when the VM restarts
+	pool.Close()
+	assert.Nil(t, client.conn)
+	runner, err := pool.CreateRunner(ipFor(addr), portFor(addr), knownHostsFile, bundleKnownHostsFile, clientKeyFile)
+	require.NoError(t, err)
+	_, _, err = runner.Run("echo hello")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *totalConn)
+}
+
+func createPoolTestServer(t *testing.T) (string, string, string, string, *int) {
+	dir := t.TempDir()
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	clientKeyFile := filepath.Join(dir, "private.key")
+	writePrivateKey(t, clientKeyFile, clientKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	totalConn := createSSHServer(ctx, t, listener, clientKey, func(_ string) (byte, string) {
+		return 0, "hello"
+	})
+	return listener.Addr().String(), filepath.Join(dir, "known_hosts"), filepath.Join(dir, "bundle_known_hosts"), clientKeyFile, totalConn
+}
+
+func TestPoolReplacedClient(t *testing.T) {
+	addr, knownHostsFile, bundleKnownHostsFile, clientKeyFile, totalConn := createPoolTestServer(t)
+	pool := NewPool()
+	defer pool.Close()
+
+	runner, err := pool.CreateRunner(ipFor(addr), portFor(addr), knownHostsFile, bundleKnownHostsFile, clientKeyFile)
+	require.NoError(t, err)
+	_, _, err = runner.Run("echo hello")
+	require.NoError(t, err)
+	replaced := pool.clients[addr]
+
+	// the client is replaced when the VM is recreated, the runners using it keep working
+	otherRunner, err := pool.CreateRunner(ipFor(addr), portFor(addr), knownHostsFile+".new", bundleKnownHostsFile, clientKeyFile)
+	require.NoError(t, err)
+	defer otherRunner.Close()
+	assert.NotSame(t, replaced, pool.clients[addr])
+	_, _, err = runner.Run("echo hello")
+	assert.NoError(t, err)
+	assert.NotNil(t, replaced.conn)
+
+	// and its connection is closed with its last runner
+	runner.Close()
+	runner.Close()
+	assert.Nil(t, replaced.conn)
+	assert.Equal(t, 0, replaced.refs)
+	_, _, err = otherRunner.Run("echo hello")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *totalConn)
+}
+
+func TestPoolRetriesOnNewConnection(t *testing.T) {
+	addr, knownHostsFile, bundleKnownHostsFile, clientKeyFile, totalConn := createPoolTestServer(t)
+	pool := NewPool()
+	defer pool.Close()
+
+	runner, err := pool.CreateRunner(ipFor(addr), portFor(addr), knownHostsFile, bundleKnownHostsFile, clientKeyFile)
+	require.NoError(t, err)
+	defer runner.Close()
+	_, _, err = runner.Run("echo hello")
+	require.NoError(t, err)
+
+	// the connection breaks before the health check notices it
+	require.NoError(t, pool.clients[addr].conn.Close())
+	out, _, err := runner.Run("echo hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", out)
+	assert.Equal(t, 2, *totalConn)
+}
+
+func TestPoolConcurrentSessions(t *testing.T) {
+	dir := t.TempDir()
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	clientKeyFile := filepath.Join(dir, "private.key")
+	writePrivateKey(t, clientKeyFile, clientKey)
+
+	const sessions = 4
+	started := make(chan struct{}, sessions+1)
+	unblock := make(chan struct{})
+	addr, totalConn := createMaxSessionsServer(t, clientKey, 2, started, unblock)
+
+	pool := NewPool()
+	defer pool.Close()
+	runner, err := pool.CreateRunner(ipFor(addr), portFor(addr), filepath.Join(dir, "known_hosts"), filepath.Join(dir, "bundle_known_hosts"), clientKeyFile)
+	require.NoError(t, err)
+	defer runner.Close()
+
+	// the sessions beyond the limit of the connection run on connections of their own
+	errs := make(chan error, sessions)
+	for i := 0; i < sessions; i++ {
+		go func() {
+			_, _, err := runner.Run("echo hello")
+			errs <- err
+		}()
+	}
+	for i := 0; i < sessions; i++ {
+		<-started
+	}
+	conn := pool.clients[addr].conn
+	require.NotNil(t, conn)
+	close(unblock)
+	for i := 0; i < sessions; i++ {
+		assert.NoError(t, <-errs)
+	}
+	assert.Equal(t, int32(3), totalConn.Load())
+
+	// while the shared connection is kept
+	assert.Same(t, conn, pool.clients[addr].conn)
+	_, _, err = runner.Run("echo hello")
+	assert.NoError(t, err)
+	assert.Same(t, conn, pool.clients[addr].conn)
+}
+
+// createMaxSessionsServer starts an SSH server handling its connections
+// concurrently, which rejects the sessions beyond maxSessions on a connection
+// as sshd does. The commands signal started and wait for unblock to be closed.
+func createMaxSessionsServer(t *testing.T, clientKey *ecdsa.PrivateKey, maxSessions int, started chan<- struct{}, unblock <-chan struct{}) (string, *atomic.Int32) {
+	pub, err := ssh.NewPublicKey(&clientKey.PublicKey)
+	require.NoError(t, err)
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), pub.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown public key")
+		},
+	}
+	serverKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(serverKey)
+	require.NoError(t, err)
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	var totalConn atomic.Int32
+	go func() {
+		for {
+			nConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			totalConn.Add(1)
+			go func() {
+				conn, chans, reqs, err := ssh.NewServerConn(nConn, config)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				go ssh.DiscardRequests(reqs)
+
+				var lock sync.Mutex
+				openSessions := 0
+				for newChannel := range chans {
+					lock.Lock()
+					if openSessions == maxSessions {
+						lock.Unlock()
+						_ = newChannel.Reject(ssh.ResourceShortage, "too many sessions")
+						continue
+					}
+					openSessions++
+					lock.Unlock()
+
+					channel, requests, err := newChannel.Accept()
+					if err != nil {
+						return
+					}
+					go func() {
+						defer func() {
+							lock.Lock()
+							openSessions--
+							lock.Unlock()
+						}()
+						for req := range requests {
+							_ = req.Reply(req.Type == "exec", nil)
+							if req.Type != "exec" {
+								continue
+							}
+							started <- struct{}{}
+							<-unblock
+							_, _ = channel.Write([]byte("hello"))
+							_, _ = channel.SendRequest("exit-status", false, []byte{0, 0, 0, 0})
+							_ = channel.Close()
+						}
+					}()
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), &totalConn
+}
@@ -0,0 +1,241 @@
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftp-server of the VM, started with sudo for the privileged transfers
+const sftpServerPath = "/usr/libexec/openssh/sftp-server"
+
+// NewSFTPClient returns an SFTP client using the SSH connection to the VM.
+// The SFTP server runs as root when privileged is true.
+func (client *NativeClient) NewSFTPClient(privileged bool) (*sftp.Client, error) {
+	opts := []sftp.ClientOption{sftp.UseConcurrentWrites(true)}
+	if !privileged {
+		var sftpClient *sftp.Client
+		release, err := client.retryOnNewConnection(func(conn *ssh.Client) error {
+			var err error
+			sftpClient, err = sftp.NewClient(conn, opts...)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			// Wait returns once the client is closed
+			_ = sftpClient.Wait()
+			release()
+		}()
+		return sftpClient, nil
+	}
+
+	session, release, err := client.session()
+	if err != nil {
+		return nil, err
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		release()
+		return nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		session.Close()
+		release()
+		return nil, err
+	}
+	if err := session.Start("sudo " + sftpServerPath); err != nil {
+		session.Close()
+		release()
+		return nil, err
+	}
+	sftpClient, err := sftp.NewClientPipe(stdout, stdin, opts...)
+	if err != nil {
+		session.Close()
+		release()
+		return nil, err
+	}
+	go func() {
+		// the server exits once the client is closed
+		_ = session.Wait()
+		session.Close()
+		release()
+	}()
+	return sftpClient, nil
+}
+
+// Upload copies the file src of the host to dest in the VM, directories are
+// only copied when recursive is true. As with cp, src is copied inside dest
+// when dest is an existing directory. The files are written as root when
+// privileged is true.
+func (runner *Runner) Upload(src, dest string, recursive, privileged bool) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() && !recursive {
+		return fmt.Errorf("%s is a directory", src)
+	}
+	client, err := runner.client.NewSFTPClient(privileged)
+	if err != nil {
+		return fmt.Errorf("Cannot start the SFTP session: %w", err)
+	}
+	defer client.Close()
+
+	if destInfo, err := client.Stat(dest); err == nil && destInfo.IsDir() {
+		dest = path.Join(dest, filepath.Base(src))
+	}
+	if !info.IsDir() {
+		return uploadFile(client, src, dest, info.Mode())
+	}
+	return filepath.WalkDir(src, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		target := path.Join(dest, filepath.ToSlash(rel))
+		switch {
+		case entry.IsDir():
+			return client.MkdirAll(target)
+		case entry.Type().IsRegular():
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			return uploadFile(client, file, target, info.Mode())
+		default:
+			log.Warnf("Skipping %s, only regular files and directories are copied", file)
+			return nil
+		}
+	})
+}
+
+func uploadFile(client *sftp.Client, src, dest string, mode os.FileMode) error {
+	log.Debugf("Copying %s to %s in the VM", src, dest)
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	return writeRemoteFile(client, in, dest, mode)
+}
+
+// writeRemoteFile writes the content of in to dest in the VM, the permissions
+// are set before the content is written
+func writeRemoteFile(client *sftp.Client, in io.Reader, dest string, mode os.FileMode) error {
+	out, err := client.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return fmt.Errorf("Cannot create %s: %w", dest, err)
+	}
+	if err := out.Chmod(mode.Perm()); err != nil {
+		out.Close()
+		return err
+	}
+	if _, err := out.ReadFrom(in); err != nil {
+		out.Close()
+		return fmt.Errorf("Cannot write %s: %w", dest, err)
+	}
+	return out.Close()
+}
+
+// Download copies the file src of the VM to dest on the host, directories
+// are only copied when recursive is true. As with cp, src is copied inside
+// dest when dest is an existing directory. The files are read as root when
+// privileged is true.
+func (runner *Runner) Download(src, dest string, recursive, privileged bool) error {
+	client, err := runner.client.NewSFTPClient(privileged)
+	if err != nil {
+		return fmt.Errorf("Cannot start the SFTP session: %w", err)
+	}
+	defer client.Close()
+
+	src = path.Clean(src)
+	info, err := client.Stat(src)
+	if err != nil {
+		return fmt.Errorf("Cannot access %s: %w", src, err)
+	}
+	if info.IsDir() && !recursive {
+		return fmt.Errorf("%s is a directory", src)
+	}
+	if destInfo, err := os.Stat(dest); err == nil && destInfo.IsDir() {
+		dest = filepath.Join(dest, path.Base(src))
+	}
+	if !info.IsDir() {
+		return downloadFile(client, src, dest, info.Mode())
+	}
+
+	walker := client.Walk(src)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return err
+		}
+		rel, err := remoteRelPath(src, walker.Path())
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, filepath.FromSlash(rel))
+		info := walker.Stat()
+		switch {
+		case info.IsDir():
+			if err := os.MkdirAll(target, 0750); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			if err := downloadFile(client, walker.Path(), target, info.Mode()); err != nil {
+				return err
+			}
+		default:
+			log.Warnf("Skipping %s, only regular files and directories are copied", walker.Path())
+		}
+	}
+	return nil
+}
+
+// remoteRelPath returns the path of file relative to dir, both being clean
+// slash separated paths of the VM with file in dir
+func remoteRelPath(dir, file string) (string, error) {
+	switch {
+	case file == dir:
+		return ".", nil
+	case dir == "." && !path.IsAbs(file) && file != ".." && !strings.HasPrefix(file, "../"):
+		return file, nil
+	}
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	if !strings.HasPrefix(file, prefix) {
+		return "", fmt.Errorf("%s is not in %s", file, dir)
+	}
+	return strings.TrimPrefix(file, prefix), nil
+}
+
+func downloadFile(client *sftp.Client, src, dest string, mode os.FileMode) error {
+	log.Debugf("Copying %s of the VM to %s", src, dest)
+	in, err := client.Open(src)
+	if err != nil {
+		return fmt.Errorf("Cannot open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := in.WriteTo(out); err != nil {
+		out.Close()
+		return fmt.Errorf("Cannot read %s: %w", src, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,203 @@
+package ssh
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// createSFTPServer starts an SSH server serving the files of the host with
+// the sftp subsystem, and with the sftp-server command for the privileged
+// transfers. It returns a runner connected to it and the privileged sessions count.
+func createSFTPServer(t *testing.T) (*Runner, func() int) {
+	dir := t.TempDir()
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	clientKeyFile := filepath.Join(dir, "private.key")
+	writePrivateKey(t, clientKeyFile, clientKey)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, _ ssh.PublicKey) (*ssh.Permissions, error) {
+			return &ssh.Permissions{}, nil
+		},
+	}
+	serverKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(serverKey)
+	require.NoError(t, err)
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	var (
+		lock               sync.Mutex
+		privilegedSessions int
+	)
+	go func() {
+		for {
+			nConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn, chans, reqs, err := ssh.NewServerConn(nConn, config)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			go ssh.DiscardRequests(reqs)
+
+			for newChannel := range chans {
+				channel, requests, err := newChannel.Accept()
+				if err != nil {
+					return
+				}
+				go func(in <-chan *ssh.Request) {
+					for req := range in {
+						length := binary.BigEndian.Uint32(req.Payload)
+						value := string(req.Payload[4 : 4+length])
+						serve := (req.Type == "subsystem" && value == "sftp") ||
+							(req.Type == "exec" && value == "sudo "+sftpServerPath)
+						_ = req.Reply(serve, nil)
+						if !serve {
+							continue
+						}
+						if req.Type == "exec" {
+							lock.Lock()
+							privilegedSessions++
+							lock.Unlock()
+						}
+						go func() {
+							server, err := sftp.NewServer(channel)
+							if err != nil {
+								return
+							}
+							_ = server.Serve()
+							_, _ = channel.SendRequest("exit-status", false, []byte{0, 0, 0, 0})
+							_ = channel.Close()
+						}()
+					}
+				}(requests)
+			}
+		}
+	}()
+
+	addr := listener.Addr().String()
+	runner, err := CreateRunner(ipFor(addr), portFor(addr), filepath.Join(dir, "known_hosts"), filepath.Join(dir, "bundle_known_hosts"), clientKeyFile)
+	require.NoError(t, err)
+	t.Cleanup(runner.Close)
+	return runner, func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return privilegedSessions
+	}
+}
+
+func writeTestTree(t *testing.T, dir string) {
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub", "empty"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), []byte("content"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "script"), []byte("#!/bin/sh"), 0700))
+}
+
+func assertTestTree(t *testing.T, dir string) {
+	data, err := os.ReadFile(filepath.Join(dir, "file"))
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+	info, err := os.Stat(filepath.Join(dir, "sub", "script"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	assert.DirExists(t, filepath.Join(dir, "sub", "empty"))
+}
+
+func TestUploadDownloadFile(t *testing.T) {
+	runner, _ := createSFTPServer(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	require.NoError(t, os.WriteFile(src, []byte("content"), 0640))
+
+	// copied inside the existing directory
+	vmDir := filepath.Join(dir, "vm")
+	require.NoError(t, os.Mkdir(vmDir, 0750))
+	require.NoError(t, runner.Upload(src, filepath.ToSlash(vmDir), false, false))
+	data, err := os.ReadFile(filepath.Join(vmDir, "src"))
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+
+	dest := filepath.Join(dir, "dest")
+	require.NoError(t, runner.Download(filepath.ToSlash(filepath.Join(vmDir, "src")), dest, false, false))
+	data, err = os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+}
+
+func TestUploadDownloadDirectory(t *testing.T) {
+	runner, privilegedSessions := createSFTPServer(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	writeTestTree(t, src)
+
+	assert.EqualError(t, runner.Upload(src, filepath.ToSlash(filepath.Join(dir, "vm")), false, false), src+" is a directory")
+	require.NoError(t, runner.Upload(src, filepath.ToSlash(filepath.Join(dir, "vm")), true, true))
+	assertTestTree(t, filepath.Join(dir, "vm"))
+	assert.Equal(t, 1, privilegedSessions())
+
+	dest := filepath.Join(dir, "dest")
+	require.NoError(t, os.Mkdir(dest, 0750))
+	require.NoError(t, runner.Download(filepath.ToSlash(filepath.Join(dir, "vm")), dest, true, false))
+	assertTestTree(t, filepath.Join(dest, "vm"))
+}
+
+func TestCopyData(t *testing.T) {
+	runner, privilegedSessions := createSFTPServer(t)
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret"), []byte("previous content"), 0644))
+	require.NoError(t, runner.CopyDataPrivileged([]byte("hello world"), filepath.ToSlash(filepath.Join(dir, "secret")), 0600))
+	data, err := os.ReadFile(filepath.Join(dir, "secret"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+	info, err := os.Stat(filepath.Join(dir, "secret"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.Equal(t, 1, privilegedSessions())
+
+	require.NoError(t, runner.CopyData([]byte("hello"), filepath.ToSlash(filepath.Join(dir, "hello")), 0644))
+	require.NoError(t, runner.CopyFileFromVM(filepath.ToSlash(filepath.Join(dir, "hello")), filepath.Join(dir, "copy"), 0600))
+	data, err = os.ReadFile(filepath.Join(dir, "copy"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, 2, privilegedSessions())
+}
+
+func TestRemoteRelPath(t *testing.T) {
+	for _, tt := range []struct {
+		dir, file, rel string
+	}{
+		{"/var/log", "/var/log", "."},
+		{"/var/log", "/var/log/crio/crio.log", "crio/crio.log"},
+		{"/", "/etc", "etc"},
+		{".", ".config/app", ".config/app"},
+		{".", "sub/.hidden", "sub/.hidden"},
+		{"..", "../file", "file"},
+	} {
+		rel, err := remoteRelPath(tt.dir, tt.file)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.rel, rel)
+	}
+	_, err := remoteRelPath("/var/log", "/var/logs/file")
+	assert.Error(t, err)
+	_, err = remoteRelPath(".", "/etc")
+	assert.Error(t, err)
+}
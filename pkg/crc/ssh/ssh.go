@@ -1,8 +1,8 @@
 package ssh
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
 	"fmt"
 	"os"
 	"strings"
@@ -17,8 +17,8 @@ type Runner struct {
 	client Client
 }
 
-func CreateRunner(ip string, port int, privateKeys ...string) (*Runner, error) {
-	client, err := NewClient(constants.DefaultSSHUser, ip, port, privateKeys...)
+func CreateRunner(ip string, port int, knownHostsFile string, bundleKnownHostsFile string, privateKeys ...string) (*Runner, error) {
+	client, err := NewClient(constants.DefaultSSHUser, ip, port, knownHostsFile, bundleKnownHostsFile, privateKeys...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,17 +51,16 @@ func (runner *Runner) RunPrivileged(reason string, cmdAndArgs ...string) (string
 	return runner.runSSHCommand(commandline, false)
 }
 
+// copyDataFull writes data to destFilename in the VM with SFTP, as root when
+// privileged is true
 func (runner *Runner) copyDataFull(data []byte, destFilename string, mode os.FileMode, privileged bool) error {
-	var sudo string
-	if privileged {
-		sudo = "sudo "
-	}
 	logging.Debugf("Creating %s with permissions 0%o in the CRC VM", destFilename, mode)
-	base64Data := base64.StdEncoding.EncodeToString(data)
-	command := fmt.Sprintf("%sinstall -m 0%o /dev/null %s && cat <<EOF | base64 --decode | %stee %s\n%s\nEOF", sudo, mode, destFilename, sudo, destFilename, base64Data)
-	_, _, err := runner.RunPrivate(command)
-
-	return err
+	client, err := runner.client.NewSFTPClient(privileged)
+	if err != nil {
+		return fmt.Errorf("Cannot start the SFTP session: %w", err)
+	}
+	defer client.Close()
+	return writeRemoteFile(client, bytes.NewReader(data), destFilename, mode)
 }
 
 func (runner *Runner) CopyDataPrivileged(data []byte, destFilename string, mode os.FileMode) error {
@@ -72,25 +71,24 @@ func (runner *Runner) CopyData(data []byte, destFilename string, mode os.FileMod
 	return runner.copyDataFull(data, destFilename, mode, false)
 }
 
+// CopyFileFromVM reads srcFilename of the VM as root and writes it to destFilename
 func (runner *Runner) CopyFileFromVM(srcFilename string, destFilename string, mode os.FileMode) error {
-	command := fmt.Sprintf("sudo base64 %s", srcFilename)
-	stdout, stderr, err := runner.RunPrivate(command)
-	if err != nil {
-		return fmt.Errorf("Failed to get file content %s : %w", stderr, err)
-	}
-	rawDecodedText, err := base64.StdEncoding.DecodeString(stdout)
+	client, err := runner.client.NewSFTPClient(true)
 	if err != nil {
-		return err
+		return fmt.Errorf("Cannot start the SFTP session: %w", err)
 	}
-	return os.WriteFile(destFilename, rawDecodedText, mode)
+	defer client.Close()
+	return downloadFile(client, srcFilename, destFilename, mode)
 }
 
+// CopyFile writes srcFilename of the host to destFilename in the VM as root
 func (runner *Runner) CopyFile(srcFilename string, destFilename string, mode os.FileMode) error {
-	data, err := os.ReadFile(srcFilename)
+	client, err := runner.client.NewSFTPClient(true)
 	if err != nil {
-		return err
+		return fmt.Errorf("Cannot start the SFTP session: %w", err)
 	}
-	return runner.CopyDataPrivileged(data, destFilename, mode)
+	defer client.Close()
+	return uploadFile(client, srcFilename, destFilename, mode)
 }
 
 func (runner *Runner) runSSHCommand(command string, runPrivate bool) (string, string, error) {
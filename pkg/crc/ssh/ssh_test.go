@@ -9,6 +9,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
@@ -31,7 +32,7 @@ func TestRunner(t *testing.T) {
 	clientKeyFile := filepath.Join(dir, "private.key")
 	writePrivateKey(t, clientKeyFile, clientKey)
 
-	cancel, runner, _ := createListenerAndSSHServer(t, clientKey, clientKeyFile)
+	cancel, runner, _ := createListenerAndSSHServer(t, clientKey, clientKeyFile, filepath.Join(t.TempDir(), "known_hosts"), filepath.Join(t.TempDir(), "bundle_known_hosts"))
 
 	assert.NoError(t, err)
 	defer runner.Close()
@@ -40,22 +41,49 @@ func TestRunner(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "hello", bin)
 	cancel()
-	// Expect error when sending data over close ssh server channel
-	assert.Error(t, runner.CopyDataPrivileged([]byte(`hello world`), "/hello", 0644))
-
-	_, runner, totalConn := createListenerAndSSHServer(t, clientKey, clientKeyFile)
-	assert.NoError(t, runner.CopyDataPrivileged([]byte(`hello world`), "/hello", 0644))
-	assert.NoError(t, runner.CopyDataPrivileged([]byte(`hello world`), "/hello", 0644))
-	assert.NoError(t, runner.CopyDataPrivileged([]byte(`hello world`), "/hello", 0644))
-	assert.NoError(t, runner.CopyData([]byte(`hello world`), "/home/core/hello", 0644))
+	// Expect error when sending a command over close ssh server channel
+	_, _, err = runner.Run("echo hello")
+	assert.Error(t, err)
+
+	_, runner, totalConn := createListenerAndSSHServer(t, clientKey, clientKeyFile, filepath.Join(t.TempDir(), "known_hosts"), filepath.Join(t.TempDir(), "bundle_known_hosts"))
+	for i := 0; i < 3; i++ {
+		_, _, err = runner.Run("echo hello")
+		assert.NoError(t, err)
+	}
 	assert.Equal(t, 1, *totalConn)
 }
 
-func createListenerAndSSHServer(t *testing.T, clientKey *ecdsa.PrivateKey, clientKeyFile string) (context.CancelFunc, *Runner, *int) {
+func TestRunnerRunInteractive(t *testing.T) {
+	dir := t.TempDir()
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	clientKeyFile := filepath.Join(dir, "private.key")
+	writePrivateKey(t, clientKeyFile, clientKey)
+
+	cancel, runner, _ := createListenerAndSSHServer(t, clientKey, clientKeyFile, filepath.Join(t.TempDir(), "known_hosts"), filepath.Join(t.TempDir(), "bundle_known_hosts"))
+	defer cancel()
+	defer runner.Close()
+
+	// the pseudo-terminal is only requested when stdin is a terminal
+	stdout := new(bytes.Buffer)
+	assert.NoError(t, runner.RunInteractive("echo hello", true, strings.NewReader(""), stdout, io.Discard))
+	assert.Equal(t, "hello", stdout.String())
+
+	stdout.Reset()
+	err = runner.RunInteractive("exit 3", false, strings.NewReader(""), stdout, io.Discard)
+	var exitErr *ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.ExitStatus())
+	assert.Equal(t, "exiting", stdout.String())
+}
+
+func createListenerAndSSHServer(t *testing.T, clientKey *ecdsa.PrivateKey, clientKeyFile string, knownHostsFile string, bundleKnownHostsFile string) (context.CancelFunc, *Runner, *int) {
 	listener, err := net.Listen("tcp", "127.0.0.1:")
 	require.NoError(t, err)
 	addr := listener.Addr().String()
-	runner, err := CreateRunner(ipFor(addr), portFor(addr), clientKeyFile)
+	runner, err := CreateRunner(ipFor(addr), portFor(addr), knownHostsFile, bundleKnownHostsFile, clientKeyFile)
 	require.NoError(t, err)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -64,15 +92,15 @@ func createListenerAndSSHServer(t *testing.T, clientKey *ecdsa.PrivateKey, clien
 		if escaped == `"echo hello"` {
 			return 0, "hello"
 		}
-		if escaped == `"sudo install -m 0644 /dev/null /hello && cat <<EOF | base64 --decode | sudo tee /hello\naGVsbG8gd29ybGQ=\nEOF"` {
-			return 0, ""
-		}
-		if escaped == `"install -m 0644 /dev/null /home/core/hello && cat <<EOF | base64 --decode | tee /home/core/hello\naGVsbG8gd29ybGQ=\nEOF"` {
-			return 0, ""
+		if escaped == `"exit 3"` {
+			return 3, "exiting"
 		}
 		return 1, fmt.Sprintf("unexpected command: %q", input)
 	})
-	return cancel, runner, totalConn
+	return func() {
+		cancel()
+		listener.Close()
+	}, runner, totalConn
 }
 
 func createSSHServer(ctx context.Context, t *testing.T, listener net.Listener, clientKey *ecdsa.PrivateKey, fun func(string) (byte, string)) *int {
@@ -110,7 +138,11 @@ func createSSHServer(ctx context.Context, t *testing.T, listener net.Listener, c
 			totalConn++
 
 			conn, chans, reqs, err := ssh.NewServerConn(nConn, config)
-			require.NoError(t, err)
+			if err != nil {
+				// the client rejects the host keys it does not trust
+				logrus.Debugf("handshake failed: %v", err)
+				continue
+			}
 			defer conn.Close()
 
 			logrus.Debugf("logged in with key %s\n", conn.Permissions.Extensions["pubkey-fp"])
@@ -0,0 +1,41 @@
+//go:build !windows
+
+package ssh
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/crc-org/crc/v2/pkg/crc/logging"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+// forwardTerminalResize sends the new size of the terminal fd to session
+// when it's resized, until the returned function is called
+func forwardTerminalResize(session *ssh.Session, fd int) func() {
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-resized:
+				width, height, err := term.GetSize(fd)
+				if err != nil {
+					continue
+				}
+				if err := session.WindowChange(height, width); err != nil {
+					log.Debugf("Cannot resize the remote terminal: %v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(resized)
+		close(done)
+	}
+}
@@ -0,0 +1,41 @@
+package ssh
+
+import (
+	"time"
+
+	log "github.com/crc-org/crc/v2/pkg/crc/logging"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+const terminalResizePollInterval = 250 * time.Millisecond
+
+// forwardTerminalResize sends the new size of the terminal fd to session
+// when it's resized, until the returned function is called. Windows has no
+// resize signal, the size of the console is polled.
+func forwardTerminalResize(session *ssh.Session, fd int) func() {
+	done := make(chan struct{})
+	go func() {
+		width, height, _ := term.GetSize(fd)
+		ticker := time.NewTicker(terminalResizePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				newWidth, newHeight, err := term.GetSize(fd)
+				if err != nil || (newWidth == width && newHeight == height) {
+					continue
+				}
+				width, height = newWidth, newHeight
+				if err := session.WindowChange(height, width); err != nil {
+					log.Debugf("Cannot resize the remote terminal: %v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
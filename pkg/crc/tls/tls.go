@@ -45,6 +45,8 @@ type CertCfg struct {
 	Subject      pkix.Name
 	Validity     time.Duration
 	IsCA         bool
+	// PermittedDNSDomains restricts the names a CA can sign certificates for
+	PermittedDNSDomains []string
 }
 
 // rsaPublicKey reflects the ASN.1 structure of a PKCS#1 public key.
@@ -78,6 +80,10 @@ func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate
 		SerialNumber:          serial,
 		Subject:               cfg.Subject,
 	}
+	if len(cfg.PermittedDNSDomains) > 0 {
+		cert.PermittedDNSDomains = cfg.PermittedDNSDomains
+		cert.PermittedDNSDomainsCritical = true
+	}
 	// verifies that the CN and/or OU for the cert is set
 	if len(cfg.Subject.CommonName) == 0 || len(cfg.Subject.OrganizationalUnit) == 0 {
 		return nil, errors.Errorf("certification's subject is not set, or invalid")
@@ -215,6 +221,39 @@ func GetSelfSignedCA() (*rsa.PrivateKey, *x509.Certificate, error) {
 	return GenerateSelfSignedCertificate(rootCAConf)
 }
 
+// GetSelfSignedIngressCA generates the CA signing the certificate served by the router for a custom apps domain.
+// Its name constraints only allow it to sign certificates for domains and their subdomains.
+func GetSelfSignedIngressCA(domains []string) (*rsa.PrivateKey, *x509.Certificate, error) {
+	if len(domains) == 0 {
+		return nil, nil, errors.New("the domains of the ingress CA must be set")
+	}
+	rootCAConf := &CertCfg{
+		Subject:             pkix.Name{CommonName: "ingress-custom-signer", OrganizationalUnit: []string{"openshift"}},
+		KeyUsages:           x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:            ValidityTenYears,
+		IsCA:                true,
+		PermittedDNSDomains: domains,
+	}
+	return GenerateSelfSignedCertificate(rootCAConf)
+}
+
+// GenerateServingCertificate generates a key and a serving certificate for dnsNames signed by the CA.
+// The certificate is valid for one year as some clients reject longer lived server certificates.
+func GenerateServingCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate, dnsNames ...string) ([]byte, []byte, error) {
+	servingConf := &CertCfg{
+		DNSNames:     dnsNames,
+		Subject:      pkix.Name{CommonName: dnsNames[0], OrganizationalUnit: []string{"openshift"}},
+		KeyUsages:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		Validity:     ValidityOneYear,
+	}
+	key, crt, err := GenerateSignedCertificate(caKey, caCert, servingConf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return PrivateKeyToPem(key), CertToPem(crt), nil
+}
+
 func GenerateClientCertificate(rootCAKey *rsa.PrivateKey, rootCACert *x509.Certificate) ([]byte, []byte, error) {
 	adminUserConf := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:admin", OrganizationalUnit: []string{"system:masters"}},
@@ -252,16 +291,35 @@ func CertToPem(cert *x509.Certificate) []byte {
 	return certInPem
 }
 
+// PemToCert parses the first certificate of a pem string
+func PemToCert(certPem []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPem)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("failed to decode certificate PEM")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// PemToPrivateKey parses an RSA private key in pem format
+func PemToPrivateKey(keyPem []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPem)
+	if block == nil || block.Type != "RSA PRIVATE KEY" {
+		return nil, errors.New("failed to decode private key PEM")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
 // VerifyCertificateAgainstRootCA  takes caPEM and certificatePEM as string
-// to validate if given certificate is signed by given ca.
-func VerifyCertificateAgainstRootCA(ca, certificate string) (bool, error) {
+// to validate if given certificate is signed by given ca for the given usage.
+// The certificates following the first one in certificatePEM are used as intermediates.
+func VerifyCertificateAgainstRootCA(ca, certificate string, usage x509.ExtKeyUsage) (bool, error) {
 	roots := x509.NewCertPool()
 	ok := roots.AppendCertsFromPEM([]byte(ca))
 	if !ok {
 		return false, errors.New("failed to parse root certificate")
 	}
 
-	block, _ := pem.Decode([]byte(certificate))
+	block, rest := pem.Decode([]byte(certificate))
 	if block == nil {
 		return false, errors.New("failed to decode client PEM")
 	}
@@ -269,10 +327,13 @@ func VerifyCertificateAgainstRootCA(ca, certificate string) (bool, error) {
 	if err != nil {
 		return false, errors.Wrapf(err, "failed to parse client PEM")
 	}
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM(rest)
 
 	opts := x509.VerifyOptions{
-		Roots:     roots,
-		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
 	}
 
 	if _, err := cert.Verify(opts); err != nil {
@@ -0,0 +1,29 @@
+package tls
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfSignedIngressCANameConstraints(t *testing.T) {
+	caKey, caCert, err := GetSelfSignedIngressCA([]string{"apps.dev.example.com", "apps-crc.testing"})
+	require.NoError(t, err)
+	assert.True(t, caCert.PermittedDNSDomainsCritical)
+
+	_, certPEM, err := GenerateServingCertificate(caKey, caCert, "*.apps.dev.example.com", "*.apps-crc.testing")
+	require.NoError(t, err)
+	ok, err := VerifyCertificateAgainstRootCA(string(CertToPem(caCert)), string(certPEM), x509.ExtKeyUsageServerAuth)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, certPEM, err = GenerateServingCertificate(caKey, caCert, "www.example.com")
+	require.NoError(t, err)
+	ok, _ = VerifyCertificateAgainstRootCA(string(CertToPem(caCert)), string(certPEM), x509.ExtKeyUsageServerAuth)
+	assert.False(t, ok)
+
+	_, _, err = GetSelfSignedIngressCA(nil)
+	assert.Error(t, err)
+}
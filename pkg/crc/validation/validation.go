@@ -7,6 +7,9 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"go.podman.io/common/pkg/strongunits"
@@ -196,6 +199,25 @@ func ValidateURL(uri string) error {
 	}
 }
 
+// ValidateBundleMirror checks if mirror is an http or https URL, or an existing local directory
+func ValidateBundleMirror(mirror string) error {
+	if mirror == "" || strings.HasPrefix(mirror, "http://") || strings.HasPrefix(mirror, "https://") {
+		return nil
+	}
+	path := strings.TrimPrefix(mirror, "file://")
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("invalid bundle mirror %s (only supported http, https or absolute local path)", mirror)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return &invalidPath{path: path}
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("bundle mirror %s is not a directory", path)
+	}
+	return nil
+}
+
 type invalidPath struct {
 	path string
 }
@@ -240,3 +262,59 @@ func ImagePullSecret(secret string) error {
 	}
 	return nil
 }
+
+var nameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+func validateName(kind, name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("'%s' is not a valid %s name, it must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character", name, kind)
+	}
+	return nil
+}
+
+// ValidateInstanceName checks if the provided name can be used as a CRC instance name
+func ValidateInstanceName(name string) error {
+	return validateName("instance", name)
+}
+
+// ValidateSnapshotName checks if the provided name can be used as a snapshot name
+func ValidateSnapshotName(name string) error {
+	return validateName("snapshot", name)
+}
+
+// ValidateAppsDomain checks if the provided domain can be used as the apps domain of the cluster
+func ValidateAppsDomain(domain string) error {
+	labels := strings.Split(domain, ".")
+	if len(domain) > 253 || len(labels) < 2 {
+		return fmt.Errorf("'%s' is not a valid apps domain, it must have a parent domain such as 'apps.example.com'", domain)
+	}
+	for _, label := range labels {
+		if !nameRegex.MatchString(label) {
+			return fmt.Errorf("'%s' is not a valid apps domain, its labels must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character", domain)
+		}
+	}
+	return nil
+}
+
+// ValidateRegistry checks if the provided registry is a host name with an optional port, such as 'quay.io'
+func ValidateRegistry(registry string) error {
+	host, port, err := net.SplitHostPort(registry)
+	if err != nil {
+		host, port = registry, ""
+	}
+	labels := strings.Split(host, ".")
+	if len(host) > 253 || len(labels) < 2 {
+		return fmt.Errorf("'%s' is not a valid registry, it must be a domain name such as 'quay.io'", registry)
+	}
+	for _, label := range labels {
+		if !nameRegex.MatchString(label) {
+			return fmt.Errorf("'%s' is not a valid registry, its labels must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character", registry)
+		}
+	}
+	if port != "" {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("'%s' is not a valid registry, its port must be a number between 0 and 65535", registry)
+		}
+	}
+	return nil
+}
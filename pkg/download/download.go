@@ -1,6 +1,7 @@
 package download
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
@@ -9,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"time"
 
@@ -22,11 +24,12 @@ import (
 	grab "github.com/sebrandon1/grab/lib"
 )
 
-func doRequest(client *grab.Client, req *grab.Request) (string, error) {
+func doRequest(client *grab.Client, req *grab.Request, reporter ProgressReporter) (string, error) {
 	const minSizeForProgressBar = 100_000_000
 
 	resp := client.Do(req)
-	if resp.Size() < minSizeForProgressBar {
+	showProgressBar := terminal.IsShowTerminalOutput() && resp.Size() >= minSizeForProgressBar
+	if !showProgressBar && reporter == nil {
 		<-resp.Done
 		return resp.Filename, resp.Err()
 	}
@@ -34,9 +37,12 @@ func doRequest(client *grab.Client, req *grab.Request) (string, error) {
 	t := time.NewTicker(500 * time.Millisecond)
 	defer t.Stop()
 	var bar *pb.ProgressBar
-	if terminal.IsShowTerminalOutput() {
+	if showProgressBar {
 		bar = pb.Start64(resp.Size())
 		bar.Set(pb.Bytes, true)
+		if resp.DidResume {
+			bar.SetCurrent(resp.BytesComplete())
+		}
 		// This is the same as the 'Default' template https://github.com/cheggaaa/pb/blob/224e0746e1e7b9c5309d6e2637264bfeb746d043/v3/preset.go#L8-L10
 		// except that the 'per second' suffix is changed to '/s' (by default it is ' p/s' which is unexpected)
 		progressBarTemplate := `{{with string . "prefix"}}{{.}} {{end}}{{counters . }} {{bar . }} {{percent . }} {{speed . "%s/s" "??/s"}}{{with string . "suffix"}} {{.}}{{end}}`
@@ -48,46 +54,98 @@ loop:
 	for {
 		select {
 		case <-t.C:
-			if terminal.IsShowTerminalOutput() {
+			if showProgressBar {
 				bar.SetCurrent(resp.BytesComplete())
 			}
+			reportProgress(reporter, req, resp)
 		case <-resp.Done:
 			break loop
 		}
 	}
+	if resp.Err() == nil {
+		reportProgress(reporter, req, resp)
+	}
 
 	return resp.Filename, resp.Err()
 }
 
+func reportProgress(reporter ProgressReporter, req *grab.Request, resp *grab.Response) {
+	if reporter == nil {
+		return
+	}
+	reporter.Progress(Progress{
+		URI:            req.URL().String(),
+		BytesComplete:  resp.BytesComplete(),
+		TotalBytes:     resp.Size(),
+		BytesPerSecond: resp.BytesPerSecond(),
+		ETA:            resp.ETA(),
+	})
+}
+
 // Download function takes sha256sum as hex decoded byte
 // something like hex.DecodeString("33daf4c03f86120fdfdc66bddf6bfff4661c7ca11c5d")
+//
+// The data is first written to a '.partial' file next to the destination file. When a
+// previous download was interrupted, it is resumed from this file with an HTTP Range
+// request if the server supports it. The sha256sum of the whole file is checked once
+// the download completes, and the partial file is discarded if it does not match.
+// Without sha256sum, the data of a previous download cannot be trusted and the file is
+// downloaded from scratch.
 func Download(ctx context.Context, uri, destination string, mode os.FileMode, sha256sum []byte) (string, error) {
 	logging.Debugf("Downloading %s to %s", uri, destination)
 
+	if ctx == nil {
+		panic("ctx is nil, this should not happen")
+	}
+
+	filename, err := destinationFilename(uri, destination)
+	if err != nil {
+		return "", err
+	}
+	partialFilename := filename + partialFileExtension
+	resume := sha256sum != nil
+	if !resume {
+		if err := os.Remove(partialFilename); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	} else if _, err := os.Stat(partialFilename); os.IsNotExist(err) {
+		// an already downloaded file is checked against the remote file, grab
+		// only fetches the missing data if it is incomplete
+		if err := os.Rename(filename, partialFilename); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
 	client := grab.NewClient()
 	client.UserAgent = version.UserAgent()
 	client.HTTPClient = &http.Client{Transport: httpproxy.HTTPTransport()}
-	req, err := grab.NewRequest(destination, uri)
+	req, err := grab.NewRequest(partialFilename, uri)
 	if err != nil {
 		return "", errors.Wrapf(err, "unable to get request from %s", uri)
 	}
+	req = req.WithContext(ctx)
+	req.NoResume = !resume
 
-	if ctx == nil {
-		panic("ctx is nil, this should not happen")
+	if _, err := doRequest(client, req, progressReporterFromContext(ctx)); err != nil {
+		if errors.Is(err, grab.ErrBadLength) {
+			// the partial file does not match the remote file, start from scratch next time
+			_ = os.Remove(partialFilename)
+		}
+		return "", err
 	}
-	req = req.WithContext(ctx)
 
 	if sha256sum != nil {
-		req.SetChecksum(sha256.New(), sha256sum, true)
+		if err := verifySha256sum(partialFilename, sha256sum); err != nil {
+			_ = os.Remove(partialFilename)
+			return "", err
+		}
 	}
 
-	filename, err := doRequest(client, req)
-	if err != nil {
+	if err := os.Chmod(partialFilename, mode); err != nil {
+		_ = os.Remove(partialFilename)
 		return "", err
 	}
-
-	if err := os.Chmod(filename, mode); err != nil {
-		_ = os.Remove(filename)
+	if err := os.Rename(partialFilename, filename); err != nil {
 		return "", err
 	}
 
@@ -95,6 +153,41 @@ func Download(ctx context.Context, uri, destination string, mode os.FileMode, sh
 	return filename, nil
 }
 
+const partialFileExtension = ".partial"
+
+// destinationFilename returns the path of the downloaded file, destination can either
+// be the path of the file or the directory where it must be saved
+func destinationFilename(uri, destination string) (string, error) {
+	if fi, err := os.Stat(destination); err != nil || !fi.IsDir() {
+		return destination, nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	filename := path.Base(u.Path)
+	if filename == "." || filename == "/" {
+		return "", fmt.Errorf("unable to get a filename from %s", uri)
+	}
+	return filepath.Join(destination, filename), nil
+}
+
+func verifySha256sum(filename string, expected []byte) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if sum := h.Sum(nil); !bytes.Equal(sum, expected) {
+		return fmt.Errorf("checksum mismatch for %s: expected %x, got %x", filepath.Base(filename), expected, sum)
+	}
+	return nil
+}
+
 // InMemory takes a URL and returns a ReadCloser object to the downloaded file
 // or the file itself if the URL is a file:// URL. In case of failure it returns
 // the respective error.
@@ -0,0 +1,113 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var content = bytes.Repeat([]byte("crc bundle content\n"), 1000)
+
+type rangeRecorder struct {
+	lock   sync.Mutex
+	ranges []string
+}
+
+func (r *rangeRecorder) handler(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet {
+		r.lock.Lock()
+		r.ranges = append(r.ranges, req.Header.Get("Range"))
+		r.lock.Unlock()
+	}
+	http.ServeContent(w, req, "crc.crcbundle", time.Time{}, bytes.NewReader(content))
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, *rangeRecorder) {
+	recorder := &rangeRecorder{}
+	server := httptest.NewServer(http.HandlerFunc(recorder.handler))
+	t.Cleanup(server.Close)
+	return server, recorder
+}
+
+func TestDownload(t *testing.T) {
+	server, recorder := newTestServer(t)
+	dir := t.TempDir()
+	sum := sha256.Sum256(content)
+
+	filename, err := Download(context.Background(), server.URL+"/crc.crcbundle", dir, 0600, sum[:])
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "crc.crcbundle"), filename)
+	assert.NoFileExists(t, filename+partialFileExtension)
+	downloaded, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+	assert.Equal(t, []string{""}, recorder.ranges)
+}
+
+func TestDownloadResume(t *testing.T) {
+	server, recorder := newTestServer(t)
+	destination := filepath.Join(t.TempDir(), "crc.crcbundle")
+	require.NoError(t, os.WriteFile(destination+partialFileExtension, content[:1000], 0600))
+	sum := sha256.Sum256(content)
+
+	filename, err := Download(context.Background(), server.URL+"/crc.crcbundle", destination, 0600, sum[:])
+	require.NoError(t, err)
+	downloaded, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+	assert.Equal(t, []string{"bytes=1000-"}, recorder.ranges)
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	server, _ := newTestServer(t)
+	destination := filepath.Join(t.TempDir(), "crc.crcbundle")
+	// corrupted data from an earlier download is detected once the download is resumed
+	corrupted := bytes.Repeat([]byte("x"), 1000)
+	require.NoError(t, os.WriteFile(destination+partialFileExtension, corrupted, 0600))
+	sum := sha256.Sum256(content)
+
+	_, err := Download(context.Background(), server.URL+"/crc.crcbundle", destination, 0600, sum[:])
+	assert.ErrorContains(t, err, "checksum mismatch")
+	assert.NoFileExists(t, destination)
+	assert.NoFileExists(t, destination+partialFileExtension)
+}
+
+func TestDownloadProgress(t *testing.T) {
+	server, _ := newTestServer(t)
+	var progress []Progress
+	ctx := WithProgressReporter(context.Background(), ProgressReporterFunc(func(p Progress) {
+		progress = append(progress, p)
+	}))
+
+	_, err := Download(ctx, server.URL+"/crc.crcbundle", t.TempDir(), 0600, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, progress)
+	last := progress[len(progress)-1]
+	assert.Equal(t, int64(len(content)), last.BytesComplete)
+	assert.Equal(t, int64(len(content)), last.TotalBytes)
+	assert.Equal(t, server.URL+"/crc.crcbundle", last.URI)
+}
+
+func TestDownloadWithoutChecksumDoesNotResume(t *testing.T) {
+	server, recorder := newTestServer(t)
+	destination := filepath.Join(t.TempDir(), "crc.crcbundle")
+	// stale data cannot be verified without a checksum
+	require.NoError(t, os.WriteFile(destination+partialFileExtension, bytes.Repeat([]byte("x"), 1000), 0600))
+
+	filename, err := Download(context.Background(), server.URL+"/crc.crcbundle", destination, 0600, nil)
+	require.NoError(t, err)
+	downloaded, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+	assert.Equal(t, []string{""}, recorder.ranges)
+}
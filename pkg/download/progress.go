@@ -0,0 +1,42 @@
+package download
+
+import (
+	"context"
+	"time"
+)
+
+// Progress describes the state of a download
+type Progress struct {
+	URI            string
+	BytesComplete  int64
+	TotalBytes     int64
+	BytesPerSecond float64
+	ETA            time.Time
+}
+
+// ProgressReporter is regularly notified of the progress of the downloads
+// started with a context returned by WithProgressReporter
+type ProgressReporter interface {
+	Progress(progress Progress)
+}
+
+// ProgressReporterFunc is an adapter to use a function as a ProgressReporter
+type ProgressReporterFunc func(progress Progress)
+
+func (f ProgressReporterFunc) Progress(progress Progress) {
+	f(progress)
+}
+
+type progressReporterKey struct{}
+
+// WithProgressReporter returns a copy of ctx which makes Download report its progress to reporter
+func WithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, reporter)
+}
+
+func progressReporterFromContext(ctx context.Context) ProgressReporter {
+	if reporter, ok := ctx.Value(progressReporterKey{}).(ProgressReporter); ok {
+		return reporter
+	}
+	return nil
+}
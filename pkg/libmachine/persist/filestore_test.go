@@ -91,6 +91,30 @@ func TestStoreExists(t *testing.T) {
 	assert.False(t, exists)
 }
 
+func TestStoreList(t *testing.T) {
+	store := getTestStore(t)
+
+	names, err := store.List()
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+
+	h := testHost()
+	assert.NoError(t, store.Save(h))
+	names, err = store.List()
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+
+	assert.NoError(t, store.SetExists(h.Name))
+	names, err = store.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{h.Name}, names)
+
+	assert.NoError(t, store.Remove(h.Name))
+	names, err = store.List()
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+}
+
 func TestStoreLoad(t *testing.T) {
 	store := getTestStore(t)
 
@@ -1121,7 +1121,7 @@ func EnsureVMPartitionSizeCorrect(expectedPVSizeStr string) error {
 		return fmt.Errorf("error in determining crc vm's ip address: %v", err)
 	}
 	crcIP := util.GetLastCommandOutput("stdout")
-	runner, err := ssh.CreateRunner(crcIP, 2222, filepath.Join(util.CRCHome, "machines", "crc", "id_ed25519"))
+	runner, err := ssh.CreateRunner(crcIP, 2222, filepath.Join(util.CRCHome, "machines", "crc", "known_hosts"), "", filepath.Join(util.CRCHome, "machines", "crc", "id_ed25519"))
 	if err != nil {
 		return fmt.Errorf("error creating ssh runner: %v", err)
 	}
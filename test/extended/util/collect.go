@@ -236,7 +236,7 @@ func sshClient() (ssh.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ssh.NewClient(connectionDetails.SSHUsername, connectionDetails.IP, connectionDetails.SSHPort, connectionDetails.SSHKeys...)
+	return ssh.NewClient(connectionDetails.SSHUsername, connectionDetails.IP, connectionDetails.SSHPort, connectionDetails.KnownHostsFile, connectionDetails.BundleKnownHostsFile, connectionDetails.SSHKeys...)
 }
 
 func command(command, target string) *CommandCollector {
@@ -240,7 +240,7 @@ func SendCommandToVM(cmd string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	sshClient, err := ssh.NewClient(connectionDetails.SSHUsername, connectionDetails.IP, connectionDetails.SSHPort, connectionDetails.SSHKeys...)
+	sshClient, err := ssh.NewClient(connectionDetails.SSHUsername, connectionDetails.IP, connectionDetails.SSHPort, connectionDetails.KnownHostsFile, connectionDetails.BundleKnownHostsFile, connectionDetails.SSHKeys...)
 	if err != nil {
 		return "", err
 	}
@@ -3,8 +3,13 @@
 package mocks
 
 import (
+	bundle "github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+
 	client "github.com/crc-org/crc/v2/pkg/crc/api/client"
+
 	mock "github.com/stretchr/testify/mock"
+
+	network "github.com/crc-org/crc/v2/pkg/crc/network"
 )
 
 // Client is an autogenerated mock type for the Client type
@@ -12,6 +17,41 @@ type Client struct {
 	mock.Mock
 }
 
+// AddPortForward provides a mock function with given fields: portForward
+func (_m *Client) AddPortForward(portForward network.PortForward) error {
+	ret := _m.Called(portForward)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(network.PortForward) error); ok {
+		r0 = rf(portForward)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelOperation provides a mock function with given fields: id
+func (_m *Client) CancelOperation(id string) (client.Operation, error) {
+	ret := _m.Called(id)
+
+	var r0 client.Operation
+	if rf, ok := ret.Get(0).(func(string) client.Operation); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(client.Operation)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields:
 func (_m *Client) Delete() error {
 	ret := _m.Called()
@@ -26,6 +66,71 @@ func (_m *Client) Delete() error {
 	return r0
 }
 
+// Diagnose provides a mock function with given fields: path
+func (_m *Client) Diagnose(path string) (client.DiagnoseResult, error) {
+	ret := _m.Called(path)
+
+	var r0 client.DiagnoseResult
+	if rf, ok := ret.Get(0).(func(string) client.DiagnoseResult); ok {
+		r0 = rf(path)
+	} else {
+		r0 = ret.Get(0).(client.DiagnoseResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DownloadBundle provides a mock function with given fields: bundleURI
+func (_m *Client) DownloadBundle(bundleURI string) (client.DownloadBundleResult, error) {
+	ret := _m.Called(bundleURI)
+
+	var r0 client.DownloadBundleResult
+	if rf, ok := ret.Get(0).(func(string) client.DownloadBundleResult); ok {
+		r0 = rf(bundleURI)
+	} else {
+		r0 = ret.Get(0).(client.DownloadBundleResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bundleURI)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBundle provides a mock function with given fields: name
+func (_m *Client) GetBundle(name string) (*bundle.CrcBundleInfo, error) {
+	ret := _m.Called(name)
+
+	var r0 *bundle.CrcBundleInfo
+	if rf, ok := ret.Get(0).(func(string) *bundle.CrcBundleInfo); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*bundle.CrcBundleInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetConfig provides a mock function with given fields: configs
 func (_m *Client) GetConfig(configs []string) (client.GetConfigResult, error) {
 	ret := _m.Called(configs)
@@ -47,6 +152,27 @@ func (_m *Client) GetConfig(configs []string) (client.GetConfigResult, error) {
 	return r0, r1
 }
 
+// GetOperation provides a mock function with given fields: id
+func (_m *Client) GetOperation(id string) (client.Operation, error) {
+	ret := _m.Called(id)
+
+	var r0 client.Operation
+	if rf, ok := ret.Get(0).(func(string) client.Operation); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(client.Operation)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsPullSecretDefined provides a mock function with given fields:
 func (_m *Client) IsPullSecretDefined() (bool, error) {
 	ret := _m.Called()
@@ -68,6 +194,76 @@ func (_m *Client) IsPullSecretDefined() (bool, error) {
 	return r0, r1
 }
 
+// ListBundles provides a mock function with given fields:
+func (_m *Client) ListBundles() (client.BundlesResult, error) {
+	ret := _m.Called()
+
+	var r0 client.BundlesResult
+	if rf, ok := ret.Get(0).(func() client.BundlesResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.BundlesResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPortForwards provides a mock function with given fields:
+func (_m *Client) ListPortForwards() (client.PortForwardsResult, error) {
+	ret := _m.Called()
+
+	var r0 client.PortForwardsResult
+	if rf, ok := ret.Get(0).(func() client.PortForwardsResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.PortForwardsResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveBundle provides a mock function with given fields: name
+func (_m *Client) RemoveBundle(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemovePortForward provides a mock function with given fields: portForward
+func (_m *Client) RemovePortForward(portForward network.PortForward) error {
+	ret := _m.Called(portForward)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(network.PortForward) error); ok {
+		r0 = rf(portForward)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetConfig provides a mock function with given fields: configs
 func (_m *Client) SetConfig(configs client.SetConfigRequest) (client.SetOrUnsetConfigResult, error) {
 	ret := _m.Called(configs)